@@ -3,17 +3,40 @@ package main
 import (
 	"context"
 	"log"
+	"os"
+	"strings"
+	"time"
 
 	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/configuration/database/mongodb"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/entity/api_key_entity"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/entity/auction_entity"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/entity/user_entity"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/event"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/infra/api/web/controller/api_key_controller"
 	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/infra/api/web/controller/auction_controller"
 	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/infra/api/web/controller/bid_controller"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/infra/api/web/controller/category_controller"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/infra/api/web/controller/stream_controller"
 	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/infra/api/web/controller/user_controller"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/infra/api/web/controller/webhook_controller"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/infra/api/web/middleware"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/infra/database/api_key"
 	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/infra/database/auction"
 	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/infra/database/bid"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/infra/database/category"
 	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/infra/database/user"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/infra/database/webhook"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/infra/dispatcher"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/infra/relay"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/infra/storage"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/infra/stream"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/internal_error"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/usecase/api_key_usecase"
 	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/usecase/auction_usecase"
 	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/usecase/bid_usecase"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/usecase/category_usecase"
 	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/usecase/user_usecase"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/usecase/webhook_usecase"
 	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv"
 	"go.mongodb.org/mongo-driver/mongo"
@@ -33,35 +56,189 @@ func main() {
 		return
 	}
 
+	imageStorage, err := storage.NewImageStorageFromEnv()
+	if err != nil {
+		log.Fatal(err.Error())
+		return
+	}
+
 	router := gin.Default()
 
-	userController, bidController, auctionsController := initDependencies(databaseConnection)
+	// Com a storage em disco, a própria aplicação serve as fotos
+	if fileStorage, ok := imageStorage.(*storage.FileStorage); ok && strings.HasPrefix(fileStorage.PublicURL, "/") {
+		router.Static(fileStorage.PublicURL, fileStorage.Dir)
+	}
 
-	router.GET("/auction", auctionsController.FindAuctions)
-	router.GET("/auction/:auctionId", auctionsController.FindAuctionById)
-	router.POST("/auction", auctionsController.CreateAuction)
-	router.GET("/auction/winner/:auctionId", auctionsController.FindWinningBidByAuctionId)
-	router.POST("/bid", bidController.CreateBid)
-	router.GET("/bid/:auctionId", bidController.FindBidByAuctionId)
+	userController, bidController, auctionsController, streamController, webhookController, apiKeyController,
+		categoryController, authenticator := initDependencies(ctx, databaseConnection, imageStorage)
+
+	authenticated := authenticator.Authenticate()
+	// As consultas seguem públicas, mas quem se identifica precisa de auction:read
+	read := router.Group("/", authenticator.AuthenticateOptional(),
+		middleware.RequireScopesIfAuthenticated(api_key_entity.ScopeAuctionRead))
+
+	read.GET("/auction", auctionsController.FindAuctions)
+	read.GET("/auction/:auctionId", auctionsController.FindAuctionById)
+	router.POST("/auction", authenticated,
+		middleware.RequireScopes(api_key_entity.ScopeAuctionWrite), auctionsController.CreateAuction)
+	read.GET("/auction/search", auctionsController.SearchAuctions)
+	router.GET("/auction/mine", authenticated,
+		middleware.RequireScopes(api_key_entity.ScopeAuctionWrite), auctionsController.FindMyAuctions)
+	router.PUT("/auction/:auctionId", authenticated,
+		middleware.RequireScopes(api_key_entity.ScopeAuctionWrite), auctionsController.UpdateAuction)
+	router.POST("/auction/:auctionId/buy", authenticated,
+		middleware.RequireScopes(api_key_entity.ScopeBidWrite), bidController.BuyNow)
+	router.POST("/auction/:auctionId/cancel", authenticated,
+		middleware.RequireScopes(api_key_entity.ScopeAuctionWrite), auctionsController.CancelAuction)
+	router.POST("/auction/:auctionId/images", authenticated,
+		middleware.RequireScopes(api_key_entity.ScopeAuctionWrite), auctionsController.AddAuctionImage)
+	router.DELETE("/auction/:auctionId/images/:imageId", authenticated,
+		middleware.RequireScopes(api_key_entity.ScopeAuctionWrite), auctionsController.RemoveAuctionImage)
+	read.GET("/auction/winner/:auctionId", auctionsController.FindWinningBidByAuctionId)
+	read.GET("/auction/:auctionId/events", streamController.StreamAuctionEvents)
+	read.GET("/auction/:auctionId/ws", streamController.WebSocketAuctionEvents)
+	router.POST("/bid", authenticated,
+		middleware.RequireScopes(api_key_entity.ScopeBidWrite), bidController.CreateBid)
+	read.GET("/bid/:auctionId", bidController.FindBidByAuctionId)
 	router.GET("/user/:userId", userController.FindUserById)
+	router.GET("/category", categoryController.FindCategories)
+	router.GET("/category/:categoryId", categoryController.FindCategoryById)
+
+	admin := router.Group("/", authenticated, middleware.RequireRoles(middleware.RoleAdmin))
+	admin.POST("/user", userController.CreateUser)
+	admin.PUT("/user/:userId", userController.UpdateUser)
+	admin.DELETE("/user/:userId", userController.DeleteUser)
+	admin.POST("/webhook", webhookController.CreateWebhook)
+	admin.GET("/webhook", webhookController.FindWebhooks)
+	admin.GET("/webhook/:webhookId", webhookController.FindWebhookById)
+	admin.DELETE("/webhook/:webhookId", webhookController.DeleteWebhook)
+	admin.GET("/webhook/:webhookId/deliveries", webhookController.FindDeliveriesByWebhookId)
+	admin.POST("/apikey", apiKeyController.IssueApiKey)
+	admin.GET("/apikey", apiKeyController.FindApiKeys)
+	admin.DELETE("/apikey/:apiKeyId", apiKeyController.RevokeApiKey)
+	admin.POST("/category", categoryController.CreateCategory)
+	admin.PUT("/category/:categoryId", categoryController.UpdateCategory)
+	admin.DELETE("/category/:categoryId", categoryController.DeleteCategory)
 
 	router.Run(":8080")
 }
 
-func initDependencies(database *mongo.Database) (
+func initDependencies(ctx context.Context, database *mongo.Database, imageStorage auction_entity.ImageStorage) (
 	userController *user_controller.UserController,
 	bidController *bid_controller.BidController,
-	auctionController *auction_controller.AuctionController) {
+	auctionController *auction_controller.AuctionController,
+	streamController *stream_controller.StreamController,
+	webhookController *webhook_controller.WebhookController,
+	apiKeyController *api_key_controller.ApiKeyController,
+	categoryController *category_controller.CategoryController,
+	authenticator *middleware.Authenticator) {
+
+	eventBus := event.NewBus()
+
+	hub := stream.NewHub()
+	hub.SubscribeTo(eventBus)
 
 	auctionRepository := auction.NewAuctionRepository(database)
+	if err := auctionRepository.CreateIndexes(ctx); err != nil {
+		log.Fatal(err.Error())
+	}
+	if err := auctionRepository.MigrateLegacyEndTimes(ctx); err != nil {
+		log.Fatal(err.Error())
+	}
+	auctionRepository.OnAuctionClosed(func(ctx context.Context, auctionId string) {
+		eventBus.Publish(ctx, event.AuctionClosed{
+			AuctionId: auctionId,
+			ClosedAt:  time.Now(),
+		})
+	})
+
 	bidRepository := bid.NewBidRepository(database, auctionRepository)
+	if err := bidRepository.CreateIndexes(ctx); err != nil {
+		log.Fatal(err.Error())
+	}
+	if err := bidRepository.MigrateLegacyAmounts(ctx); err != nil {
+		log.Fatal(err.Error())
+	}
+	if err := bidRepository.MigrateLegacyTimestamps(ctx); err != nil {
+		log.Fatal(err.Error())
+	}
+	if err := bidRepository.BackfillBidSummary(ctx); err != nil {
+		log.Fatal(err.Error())
+	}
 	userRepository := user.NewUserRepository(database)
+	if err := userRepository.CreateIndexes(ctx); err != nil {
+		log.Fatal(err.Error())
+	}
+	// Máximos de usuários suspensos ou banidos não geram lances automáticos
+	bidRepository.CheckBidder(func(ctx context.Context, userId string) (bool, *internal_error.InternalError) {
+		userEntity, err := userRepository.FindUserById(ctx, userId)
+		if err != nil {
+			if err.Err == "not_found" {
+				return false, nil
+			}
+			return false, err
+		}
+		return userEntity.Status == user_entity.Active, nil
+	})
+	categoryRepository := category.NewCategoryRepository(database)
+	if err := categoryRepository.CreateIndexes(ctx); err != nil {
+		log.Fatal(err.Error())
+	}
+	if err := auctionRepository.MigrateLegacyCategories(ctx, categoryRepository); err != nil {
+		log.Fatal(err.Error())
+	}
+	webhookRepository := webhook.NewWebhookRepository(database)
+	apiKeyRepository := api_key.NewApiKeyRepository(database)
+	if err := apiKeyRepository.CreateIndexes(ctx); err != nil {
+		log.Fatal(err.Error())
+	}
+
+	authenticator, err := middleware.NewAuthenticatorFromEnv(apiKeyRepository)
+	if err != nil {
+		log.Fatal(err.Error())
+	}
+
+	auctionUseCase := auction_usecase.NewAuctionUseCase(
+		auctionRepository, bidRepository, categoryRepository, auctionRepository, imageStorage, eventBus)
+
+	// Leilões encerrados sem lance que atinja a reserva ficam como não vendidos
+	auctionRepository.CheckSold(func(
+		ctx context.Context, auctionEntity *auction_entity.Auction) (bool, *internal_error.InternalError) {
+		winningBid, err := bidRepository.FindWinningBidByAuctionId(ctx, auctionEntity.Id)
+		if err != nil {
+			if err.Err == "not_found" {
+				return false, nil
+			}
+			return false, err
+		}
+		return auctionEntity.ReserveMet(winningBid.Amount), nil
+	})
+
+	webhookDispatcher := dispatcher.NewDispatcher(webhookRepository, auctionRepository.Scheduler)
+	webhookUseCase := webhook_usecase.NewWebhookUseCase(webhookRepository, auctionUseCase, webhookDispatcher)
+
+	// Os webhooks partem da mensagem auction.closed gravada no fechamento
+	sinks := []relay.Sink{relay.NewLogSink(), webhook_usecase.NewOutboxSink(webhookUseCase)}
+	if webhookURL := os.Getenv("OUTBOX_WEBHOOK_URL"); webhookURL != "" {
+		sinks = append(sinks, relay.NewWebhookSink(webhookURL))
+	}
+	relay.NewRelay(auctionRepository.Outbox, sinks...).Start(ctx)
+
+	// Os assinantes precisam estar registrados antes de fechar os leilões vencidos
+	webhookDispatcher.RecoverPendingDeliveries(ctx)
+	if err := auctionRepository.RecoverActiveAuctions(ctx); err != nil {
+		log.Fatal(err.Error())
+	}
 
 	userController = user_controller.NewUserController(
 		user_usecase.NewUserUseCase(userRepository))
-	auctionController = auction_controller.NewAuctionController(
-		auction_usecase.NewAuctionUseCase(auctionRepository, bidRepository))
-	bidController = bid_controller.NewBidController(bid_usecase.NewBidUseCase(bidRepository))
+	auctionController = auction_controller.NewAuctionController(auctionUseCase)
+	bidController = bid_controller.NewBidController(bid_usecase.NewBidUseCase(bidRepository, userRepository, eventBus))
+	streamController = stream_controller.NewStreamController(hub)
+	webhookController = webhook_controller.NewWebhookController(webhookUseCase)
+	apiKeyController = api_key_controller.NewApiKeyController(api_key_usecase.NewApiKeyUseCase(apiKeyRepository, userRepository))
+	categoryController = category_controller.NewCategoryController(
+		category_usecase.NewCategoryUseCase(categoryRepository, auctionRepository))
 
 	return
 }
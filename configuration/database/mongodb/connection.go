@@ -30,5 +30,10 @@ func NewMongoDBConnection(ctx context.Context) (*mongo.Database, error) {
 		return nil, err
 	}
 
+	if err := CheckTransactionSupport(ctx, client); err != nil {
+		logger.Error("Error trying to check mongodb transaction support", err)
+		return nil, err
+	}
+
 	return client.Database(mongoDatabase), nil
 }
@@ -0,0 +1,47 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// ErrTransactionsNotSupported indica um servidor standalone, sem replica set
+var ErrTransactionsNotSupported = errors.New(
+	"mongodb does not support transactions; run it as a replica set (a single node is enough)")
+
+// WithTransaction executa fn dentro de uma transação.
+func WithTransaction(ctx context.Context, client *mongo.Client, fn func(ctx context.Context) error) error {
+	session, err := client.StartSession()
+	if err != nil {
+		return err
+	}
+	defer session.EndSession(ctx)
+
+	_, err = session.WithTransaction(ctx, func(sessionContext mongo.SessionContext) (interface{}, error) {
+		return nil, fn(sessionContext)
+	})
+
+	return err
+}
+
+// CheckTransactionSupport garante que o servidor aceita transações: membros de
+// replica set ou roteadores de cluster fragmentado. Sem isso o outbox perderia
+// a garantia de ser gravado junto com a escrita que o originou.
+func CheckTransactionSupport(ctx context.Context, client *mongo.Client) error {
+	var hello struct {
+		SetName string `bson:"setName"`
+		Msg     string `bson:"msg"`
+	}
+	if err := client.Database("admin").RunCommand(ctx, bson.D{{Key: "hello", Value: 1}}).Decode(&hello); err != nil {
+		return err
+	}
+
+	if hello.SetName == "" && hello.Msg != "isdbgrid" {
+		return ErrTransactionsNotSupported
+	}
+
+	return nil
+}
@@ -28,6 +28,10 @@ func ConvertError(internalError *internal_error.InternalError) *RestErr {
 		return NewBadRequestError(internalError.Error())
 	case "not_found":
 		return NewNotFoundError(internalError.Error())
+	case "forbidden":
+		return NewForbiddenError(internalError.Error())
+	case "conflict":
+		return NewConflictError(internalError.Error())
 	default:
 		return NewInternalServerError(internalError.Error())
 	}
@@ -59,3 +63,39 @@ func NewNotFoundError(message string) *RestErr {
 		Causes:  nil,
 	}
 }
+
+func NewUnauthorizedError(message string) *RestErr {
+	return &RestErr{
+		Message: message,
+		Err:     "unauthorized",
+		Code:    http.StatusUnauthorized,
+		Causes:  nil,
+	}
+}
+
+func NewForbiddenError(message string) *RestErr {
+	return &RestErr{
+		Message: message,
+		Err:     "forbidden",
+		Code:    http.StatusForbidden,
+		Causes:  nil,
+	}
+}
+
+func NewConflictError(message string) *RestErr {
+	return &RestErr{
+		Message: message,
+		Err:     "conflict",
+		Code:    http.StatusConflict,
+		Causes:  nil,
+	}
+}
+
+func NewRequestEntityTooLargeError(message string) *RestErr {
+	return &RestErr{
+		Message: message,
+		Err:     "request_entity_too_large",
+		Code:    http.StatusRequestEntityTooLarge,
+		Causes:  nil,
+	}
+}
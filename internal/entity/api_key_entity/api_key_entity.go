@@ -0,0 +1,103 @@
+package api_key_entity
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"strings"
+	"time"
+
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/internal_error"
+	"github.com/google/uuid"
+)
+
+const (
+	ScopeAuctionRead  = "auction:read"
+	ScopeAuctionWrite = "auction:write"
+	ScopeBidWrite     = "bid:write"
+)
+
+const (
+	keyPrefix       = "ak_"
+	displayedLength = 10
+)
+
+// ApiKey guarda apenas o hash da chave; o valor em texto só existe no
+// momento da emissão.
+type ApiKey struct {
+	Id        string
+	Name      string
+	UserId    string
+	Prefix    string
+	KeyHash   string
+	Scopes    []string
+	CreatedAt time.Time
+	RevokedAt time.Time
+}
+
+func IssueApiKey(name, userId string, scopes []string) (*ApiKey, string, *internal_error.InternalError) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, "", internal_error.NewInternalServerError("Error trying to generate api key")
+	}
+	plainKey := keyPrefix + base64.RawURLEncoding.EncodeToString(secret)
+
+	apiKey := &ApiKey{
+		Id:        uuid.New().String(),
+		Name:      strings.TrimSpace(name),
+		UserId:    userId,
+		Prefix:    plainKey[:displayedLength],
+		KeyHash:   HashApiKey(plainKey),
+		Scopes:    scopes,
+		CreatedAt: time.Now(),
+	}
+
+	if err := apiKey.Validate(); err != nil {
+		return nil, "", err
+	}
+
+	return apiKey, plainKey, nil
+}
+
+func HashApiKey(plainKey string) string {
+	sum := sha256.Sum256([]byte(plainKey))
+	return hex.EncodeToString(sum[:])
+}
+
+func (ak *ApiKey) Validate() *internal_error.InternalError {
+	if ak.Name == "" {
+		return internal_error.NewBadRequestError("name is required")
+	}
+
+	if _, err := uuid.Parse(ak.UserId); err != nil {
+		return internal_error.NewBadRequestError("user_id must be a valid UUID")
+	}
+
+	if len(ak.Scopes) == 0 {
+		return internal_error.NewBadRequestError("at least one scope is required")
+	}
+
+	for _, scope := range ak.Scopes {
+		if scope != ScopeAuctionRead && scope != ScopeAuctionWrite && scope != ScopeBidWrite {
+			return internal_error.NewBadRequestError("scope " + scope + " is not supported")
+		}
+	}
+
+	return nil
+}
+
+func (ak *ApiKey) Revoked() bool {
+	return !ak.RevokedAt.IsZero()
+}
+
+type ApiKeyRepositoryInterface interface {
+	CreateApiKey(ctx context.Context, apiKey *ApiKey) *internal_error.InternalError
+
+	FindApiKeys(ctx context.Context) ([]ApiKey, *internal_error.InternalError)
+
+	FindApiKeyByHash(ctx context.Context, keyHash string) (*ApiKey, *internal_error.InternalError)
+
+	RevokeApiKey(ctx context.Context, id string) *internal_error.InternalError
+}
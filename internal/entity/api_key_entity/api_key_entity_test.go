@@ -0,0 +1,34 @@
+package api_key_entity
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIssueApiKey(t *testing.T) {
+	apiKey, plainKey, err := IssueApiKey("partner", uuid.New().String(), []string{ScopeAuctionRead})
+
+	assert.Nil(t, err)
+	assert.True(t, strings.HasPrefix(plainKey, "ak_"))
+	assert.True(t, strings.HasPrefix(plainKey, apiKey.Prefix))
+	assert.Equal(t, HashApiKey(plainKey), apiKey.KeyHash)
+	assert.NotContains(t, apiKey.KeyHash, plainKey)
+	assert.False(t, apiKey.Revoked())
+
+	_, otherKey, _ := IssueApiKey("partner", uuid.New().String(), []string{ScopeAuctionRead})
+	assert.NotEqual(t, plainKey, otherKey)
+}
+
+func TestIssueApiKeyValidation(t *testing.T) {
+	_, _, err := IssueApiKey("", uuid.New().String(), []string{ScopeAuctionRead})
+	assert.Equal(t, "bad_request", err.Err)
+
+	_, _, err = IssueApiKey("partner", "invalid", []string{ScopeAuctionRead})
+	assert.Equal(t, "bad_request", err.Err)
+
+	_, _, err = IssueApiKey("partner", uuid.New().String(), []string{"auction:delete"})
+	assert.Equal(t, "bad_request", err.Err)
+}
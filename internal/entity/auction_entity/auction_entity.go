@@ -4,21 +4,24 @@ import (
 	"context"
 	"time"
 
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/entity/money_entity"
 	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/internal_error"
 	"github.com/google/uuid"
 )
 
 func CreateAuction(
-	productName, category, description string,
+	sellerId, productName, category, description string,
 	condition ProductCondition) (*Auction, *internal_error.InternalError) {
 	auction := &Auction{
 		Id:          uuid.New().String(),
+		SellerId:    sellerId,
 		ProductName: productName,
 		Category:    category,
 		Description: description,
 		Condition:   condition,
 		Status:      Active,
 		Timestamp:   time.Now(),
+		Currency:    money_entity.DefaultCurrency,
 	}
 
 	if err := auction.Validate(); err != nil {
@@ -29,6 +32,10 @@ func CreateAuction(
 }
 
 func (au *Auction) Validate() *internal_error.InternalError {
+	if au.SellerId == "" {
+		return internal_error.NewBadRequestError("auction seller is required")
+	}
+
 	if len(au.ProductName) <= 1 ||
 		len(au.Category) <= 2 ||
 		len(au.Description) <= 10 ||
@@ -36,25 +43,219 @@ func (au *Auction) Validate() *internal_error.InternalError {
 		return internal_error.NewBadRequestError("invalid auction object")
 	}
 
+	if !money_entity.Supported(au.Currency) {
+		return internal_error.NewBadRequestError("auction currency is not supported")
+	}
+
+	if au.StartingPrice.IsNegative() || au.ReservePrice.IsNegative() ||
+		!au.StartingPrice.In(au.Currency) || !au.ReservePrice.In(au.Currency) ||
+		au.MinIncrement < 0 || au.MinIncrement > money_entity.MaxUnits ||
+		(au.IncrementType != AbsoluteIncrement && au.IncrementType != PercentageIncrement) {
+		return internal_error.NewBadRequestError("invalid auction bid rules")
+	}
+
+	if au.HasBuyNow() && (!au.BuyNowPrice.In(au.Currency) ||
+		au.BuyNowPrice.LessThan(au.StartingPrice) || au.BuyNowPrice.LessThan(au.ReservePrice)) {
+		return internal_error.NewBadRequestError("buy now price must be at least the starting and reserve prices")
+	}
+
+	if err := au.validateType(); err != nil {
+		return err
+	}
+
+	if !au.EndTime.IsZero() && !au.EndTime.After(au.OpensAt()) {
+		return internal_error.NewBadRequestError("auction end time must be after its start")
+	}
+
 	return nil
 }
 
+// SetCategory associa o leilão a uma categoria da árvore, guardando também
+// o nome para exibição e para a busca textual.
+func (au *Auction) SetCategory(categoryId, category string) *internal_error.InternalError {
+	au.CategoryId = categoryId
+	au.Category = category
+
+	return au.Validate()
+}
+
+// SetDetails altera os dados descritivos que o vendedor pode corrigir antes do primeiro lance.
+func (au *Auction) SetDetails(categoryId, category, description string) *internal_error.InternalError {
+	au.CategoryId = categoryId
+	au.Category = category
+	au.Description = description
+
+	return au.Validate()
+}
+
+func (au *Auction) OwnedBy(userId string) bool {
+	return au.SellerId != "" && au.SellerId == userId
+}
+
+// Schedule adia a abertura de um leilão ainda não gravado para startsAt.
+func (au *Auction) Schedule(startsAt time.Time) *internal_error.InternalError {
+	if !startsAt.After(au.Timestamp) {
+		return internal_error.NewBadRequestError("auction start must be in the future")
+	}
+
+	au.StartsAt = startsAt
+	au.Status = Scheduled
+
+	return au.Validate()
+}
+
+// OpensAt é o momento a partir do qual o leilão aceita lances.
+func (au *Auction) OpensAt() time.Time {
+	if au.StartsAt.IsZero() {
+		return au.Timestamp
+	}
+
+	return au.StartsAt
+}
+
+func (au *Auction) SetEndTime(endTime time.Time) *internal_error.InternalError {
+	au.EndTime = endTime
+
+	return au.Validate()
+}
+
+// SetBidRules define a moeda do leilão pela do preço inicial. O incremento é
+// em unidades menores da moeda ou, se percentual, em pontos-base (500 = 5%).
+func (au *Auction) SetBidRules(
+	startingPrice money_entity.Money,
+	minIncrement int64,
+	incrementType IncrementType) *internal_error.InternalError {
+	au.Currency = startingPrice.Currency
+	au.StartingPrice = startingPrice
+	au.MinIncrement = minIncrement
+	au.IncrementType = incrementType
+
+	return au.Validate()
+}
+
+// SetReservePrice define o preço de reserva oculto; zero indica leilão sem reserva.
+func (au *Auction) SetReservePrice(reservePrice money_entity.Money) *internal_error.InternalError {
+	au.ReservePrice = reservePrice
+
+	return au.Validate()
+}
+
+// SetBuyNowPrice define o preço de compra imediata; zero indica leilão sem compra imediata.
+func (au *Auction) SetBuyNowPrice(buyNowPrice money_entity.Money) *internal_error.InternalError {
+	if buyNowPrice.IsNegative() {
+		return internal_error.NewBadRequestError("buy now price must not be negative")
+	}
+
+	au.BuyNowPrice = buyNowPrice
+
+	return au.Validate()
+}
+
+func (au *Auction) HasBuyNow() bool {
+	return au.BuyNowPrice.Units > 0
+}
+
+// BuyNowAvailable indica se ainda cabe a compra imediata. Ela deixa de valer
+// quando um lance passa de threshold pontos-base do preço de compra (zero
+// desativa no primeiro lance) ou alcança o próprio preço de compra.
+func (au *Auction) BuyNowAvailable(highestAmount money_entity.Money, hasBids bool, threshold int64) bool {
+	if !au.HasBuyNow() {
+		return false
+	}
+
+	if !hasBids {
+		return true
+	}
+
+	limit := money_entity.New(au.BuyNowPrice.Percent(threshold), au.Currency)
+	return !highestAmount.GreaterThan(limit) && highestAmount.LessThan(au.BuyNowPrice)
+}
+
+func (au *Auction) HasReserve() bool {
+	return au.ReservePrice.Units > 0
+}
+
+func (au *Auction) ReserveMet(amount money_entity.Money) bool {
+	return !au.HasReserve() || !amount.LessThan(au.ReservePrice)
+}
+
+// MinimumBid retorna o menor valor aceito para o próximo lance. Sem incremento
+// configurado, o novo lance precisa superar o atual em ao menos uma unidade
+// menor da moeda (um centavo, no real).
+func (au *Auction) MinimumBid(highestAmount money_entity.Money, hasBids bool) money_entity.Money {
+	if !hasBids {
+		return money_entity.Max(
+			money_entity.New(au.StartingPrice.Units, au.Currency),
+			money_entity.New(minimumIncrement, au.Currency))
+	}
+
+	increment := au.MinIncrement
+	if au.IncrementType == PercentageIncrement {
+		increment = highestAmount.Percent(au.MinIncrement)
+	}
+
+	if increment < minimumIncrement {
+		increment = minimumIncrement
+	}
+
+	return highestAmount.Add(increment)
+}
+
+func (au *Auction) AcceptsBidAmount(amount, highestAmount money_entity.Money, hasBids bool) bool {
+	return !amount.LessThan(au.MinimumBid(highestAmount, hasBids))
+}
+
 type Auction struct {
 	Id          string
+	SellerId    string
 	ProductName string
+	CategoryId  string
 	Category    string
 	Description string
 	Condition   ProductCondition
 	Status      AuctionStatus
 	Timestamp   time.Time
+	StartsAt    time.Time
+	EndTime     time.Time
+	Type        AuctionType
+
+	// Todos os valores do leilão e dos seus lances estão nesta moeda
+	Currency      string
+	StartingPrice money_entity.Money
+	MinIncrement  int64
+	IncrementType IncrementType
+	ReservePrice  money_entity.Money
+	BuyNowPrice   money_entity.Money
+
+	// Só para leilões holandeses
+	PriceSchedule PriceSchedule
+
+	// Resumo dos lances gravados, mantido pelo repositório para ordenação
+	HighestBidAmount money_entity.Money
+	BidCount         int64
+
+	Images []AuctionImage
 }
 
 type ProductCondition int
 type AuctionStatus int
+type IncrementType int
 
 const (
 	Active AuctionStatus = iota
 	Completed
+	Cancelled
+	Draft
+	Scheduled
+	Unsold
+)
+
+// Menor incremento possível: uma unidade menor da moeda
+const minimumIncrement int64 = 1
+
+const (
+	AbsoluteIncrement IncrementType = iota
+	PercentageIncrement
 )
 
 const (
@@ -70,14 +271,26 @@ type AuctionRepositoryInterface interface {
 
 	FindAuctions(
 		ctx context.Context,
-		status AuctionStatus,
-		category, productName string) ([]Auction, *internal_error.InternalError)
+		query AuctionQuery) (*AuctionPage, *internal_error.InternalError)
 
 	FindAuctionById(
 		ctx context.Context, id string) (*Auction, *internal_error.InternalError)
 
+	UpdateAuction(
+		ctx context.Context,
+		auctionEntity *Auction) *internal_error.InternalError
+
 	UpdateAuctionStatus(
 		ctx context.Context,
 		id string,
 		status AuctionStatus) *internal_error.InternalError
+
+	AddAuctionImage(
+		ctx context.Context,
+		auctionId string,
+		auctionImage AuctionImage) *internal_error.InternalError
+
+	RemoveAuctionImage(
+		ctx context.Context,
+		auctionId, imageId string) *internal_error.InternalError
 }
@@ -0,0 +1,211 @@
+package auction_entity
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"testing"
+	"time"
+
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/entity/money_entity"
+	"github.com/stretchr/testify/assert"
+)
+
+func brl(units int64) money_entity.Money {
+	return money_entity.New(units, "BRL")
+}
+
+func TestAuctionMinimumBid(t *testing.T) {
+	tests := []struct {
+		name          string
+		auction       Auction
+		highestAmount money_entity.Money
+		hasBids       bool
+		expected      string
+	}{
+		{
+			name:     "first bid must reach starting price",
+			auction:  Auction{Currency: "BRL", StartingPrice: brl(5000), MinIncrement: 500},
+			expected: "50.00",
+		},
+		{
+			name:          "absolute increment over highest bid",
+			auction:       Auction{Currency: "BRL", StartingPrice: brl(5000), MinIncrement: 500},
+			highestAmount: brl(6000),
+			hasBids:       true,
+			expected:      "65.00",
+		},
+		{
+			name:          "percentage increment over highest bid",
+			auction:       Auction{Currency: "BRL", MinIncrement: 1000, IncrementType: PercentageIncrement},
+			highestAmount: brl(8000),
+			hasBids:       true,
+			expected:      "88.00",
+		},
+		{
+			name:          "percentage increment is rounded to the cent",
+			auction:       Auction{Currency: "BRL", MinIncrement: 250, IncrementType: PercentageIncrement},
+			highestAmount: brl(1010),
+			hasBids:       true,
+			expected:      "10.35",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.auction.MinimumBid(tt.highestAmount, tt.hasBids).String())
+		})
+	}
+}
+
+func TestAuctionAcceptsBidAmount(t *testing.T) {
+	withIncrement := Auction{Currency: "BRL", StartingPrice: brl(1000), MinIncrement: 200}
+	assert.False(t, withIncrement.AcceptsBidAmount(brl(999), brl(0), false))
+	assert.True(t, withIncrement.AcceptsBidAmount(brl(1000), brl(0), false))
+	assert.False(t, withIncrement.AcceptsBidAmount(brl(1199), brl(1000), true))
+	assert.True(t, withIncrement.AcceptsBidAmount(brl(1200), brl(1000), true))
+
+	withoutIncrement := Auction{Currency: "BRL"}
+	assert.False(t, withoutIncrement.AcceptsBidAmount(brl(1000), brl(1000), true), "equal bid must not outbid")
+	assert.True(t, withoutIncrement.AcceptsBidAmount(brl(1001), brl(1000), true))
+}
+
+func TestAuctionCurrency(t *testing.T) {
+	auction, err := CreateAuction("seller-id", "Product", "Category", "Auction in dollars description", New)
+	assert.Nil(t, err)
+	assert.Equal(t, money_entity.DefaultCurrency, auction.Currency)
+
+	assert.Nil(t, auction.SetBidRules(money_entity.New(1000, "USD"), 100, AbsoluteIncrement))
+	assert.Equal(t, "USD", auction.Currency)
+
+	err = auction.SetReservePrice(brl(5000))
+	assert.Equal(t, "bad_request", err.Err)
+
+	err = auction.SetBidRules(money_entity.New(1000, "XYZ"), 100, AbsoluteIncrement)
+	assert.Equal(t, "bad_request", err.Err)
+}
+
+func TestBuyNowAvailable(t *testing.T) {
+	auction, err := CreateAuction("seller-id", "Product", "Category", "Buy now auction description", New)
+	assert.Nil(t, err)
+	assert.Nil(t, auction.SetBidRules(brl(10000), 100, AbsoluteIncrement))
+	assert.Nil(t, auction.SetReservePrice(brl(30000)))
+	assert.False(t, auction.BuyNowAvailable(brl(0), false, 0))
+
+	err = auction.SetBuyNowPrice(brl(20000))
+	assert.Equal(t, "bad_request", err.Err, "buy now below the reserve")
+
+	assert.Nil(t, auction.SetBuyNowPrice(brl(50000)))
+	assert.True(t, auction.BuyNowAvailable(brl(0), false, 0))
+	assert.False(t, auction.BuyNowAvailable(brl(10000), true, 0), "any bid disables it without threshold")
+
+	assert.True(t, auction.BuyNowAvailable(brl(25000), true, 5000))
+	assert.False(t, auction.BuyNowAvailable(brl(25001), true, 5000))
+	assert.False(t, auction.BuyNowAvailable(brl(50000), true, 10000), "a bid at the buy now price wins")
+}
+
+func TestDutchAuctionCurrentPrice(t *testing.T) {
+	auction, err := CreateAuction("seller-id", "Product", "Category", "Dutch auction description", New)
+	assert.Nil(t, err)
+	assert.Nil(t, auction.SetBidRules(brl(100000), 0, AbsoluteIncrement))
+
+	err = auction.SetPriceSchedule(brl(100000), 5000, 10*time.Minute)
+	assert.Equal(t, "bad_request", err.Err, "floor must be below the starting price")
+	err = auction.SetPriceSchedule(brl(40000), 5000, 1500*time.Millisecond)
+	assert.Equal(t, "bad_request", err.Err, "interval must be whole seconds")
+
+	assert.Nil(t, auction.SetPriceSchedule(brl(40000), 7000, 10*time.Minute))
+	opensAt := auction.OpensAt()
+
+	assert.Equal(t, brl(100000), auction.CurrentPrice(opensAt.Add(-time.Hour)))
+	assert.Equal(t, brl(100000), auction.CurrentPrice(opensAt.Add(9*time.Minute)))
+	assert.Equal(t, brl(93000), auction.CurrentPrice(opensAt.Add(10*time.Minute)))
+	assert.Equal(t, brl(44000), auction.CurrentPrice(opensAt.Add(80*time.Minute)))
+	assert.Equal(t, brl(40000), auction.CurrentPrice(opensAt.Add(90*time.Minute)), "never below the floor")
+	assert.Equal(t, opensAt.Add(90*time.Minute), auction.FloorReachedAt())
+
+	err = auction.SetBuyNowPrice(brl(200000))
+	assert.Equal(t, "bad_request", err.Err, "dutch auctions have no buy now price")
+}
+
+func TestAuctionStatusTransitions(t *testing.T) {
+	allowed := map[AuctionStatus][]AuctionStatus{
+		Draft:     {Scheduled, Active, Cancelled},
+		Scheduled: {Active, Cancelled},
+		Active:    {Completed, Unsold, Cancelled},
+	}
+	statuses := []AuctionStatus{Draft, Scheduled, Active, Completed, Cancelled, Unsold}
+
+	for _, current := range statuses {
+		for _, next := range statuses {
+			expected := false
+			for _, status := range allowed[current] {
+				expected = expected || status == next
+			}
+
+			assert.Equal(t, expected, current.CanTransitionTo(next), "%s -> %s", current, next)
+		}
+	}
+
+	assert.Equal(t, []AuctionStatus{Draft, Scheduled, Active}, PreviousStatuses(Cancelled))
+	assert.Equal(t, []AuctionStatus{Active}, PreviousStatuses(Completed))
+
+	auction := Auction{Status: Completed}
+	err := auction.TransitionTo(Active)
+	assert.Equal(t, "bad_request", err.Err)
+	assert.Equal(t, Completed, auction.Status)
+}
+
+func TestScheduleAuction(t *testing.T) {
+	auction, err := CreateAuction("seller-id", "Product", "Category", "Scheduled auction description", New)
+	assert.Nil(t, err)
+
+	err = auction.Schedule(auction.Timestamp.Add(-time.Minute))
+	assert.Equal(t, "bad_request", err.Err)
+	assert.Equal(t, Active, auction.Status)
+
+	startsAt := auction.Timestamp.Add(48 * time.Hour)
+	assert.Nil(t, auction.Schedule(startsAt))
+	assert.Equal(t, Scheduled, auction.Status)
+	assert.Equal(t, startsAt, auction.OpensAt())
+
+	// O término é contado a partir da abertura
+	err = auction.SetEndTime(startsAt.Add(-time.Hour))
+	assert.Equal(t, "bad_request", err.Err)
+	assert.Nil(t, auction.SetEndTime(startsAt.Add(time.Hour)))
+}
+
+func TestProcessImage(t *testing.T) {
+	source := image.NewRGBA(image.Rect(0, 0, 1200, 600))
+	var encoded bytes.Buffer
+	assert.NoError(t, jpeg.Encode(&encoded, source, nil))
+
+	processed, err := ProcessImage(context.Background(), "auction-id", encoded.Bytes())
+	assert.Nil(t, err)
+	assert.Equal(t, "image/jpeg", processed.Image.ContentType)
+	assert.Equal(t, 1200, processed.Image.Width)
+	assert.Equal(t, 600, processed.Image.Height)
+	assert.Equal(t, "auctions/auction-id/"+processed.Image.Id+".jpg", processed.Image.Key)
+
+	thumbnail, _, decodeErr := image.DecodeConfig(bytes.NewReader(processed.Thumbnail))
+	assert.NoError(t, decodeErr)
+	assert.Equal(t, ThumbnailSize, thumbnail.Width)
+	assert.Equal(t, ThumbnailSize/2, thumbnail.Height)
+
+	_, err = ProcessImage(context.Background(), "auction-id", []byte("<svg xmlns=\"http://www.w3.org/2000/svg\"></svg>"))
+	assert.Equal(t, "bad_request", err.Err)
+
+	_, err = ProcessImage(context.Background(), "auction-id", encoded.Bytes()[:64])
+	assert.Equal(t, "bad_request", err.Err)
+
+	_, err = ProcessImage(context.Background(), "auction-id", make([]byte, MaxImageSize+1))
+	assert.Equal(t, "bad_request", err.Err)
+
+	// Recusada pelo cabeçalho, sem decodificar os pixels
+	encoded.Reset()
+	assert.NoError(t, png.Encode(&encoded, image.NewGray(image.Rect(0, 0, maxImageDimension+1, 1))))
+	_, err = ProcessImage(context.Background(), "auction-id", encoded.Bytes())
+	assert.Equal(t, "bad_request", err.Err)
+}
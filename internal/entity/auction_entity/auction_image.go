@@ -0,0 +1,188 @@
+package auction_entity
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	_ "image/gif"
+	"image/jpeg"
+	_ "image/png"
+	"net/http"
+	"time"
+
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/internal_error"
+	"github.com/google/uuid"
+	xdraw "golang.org/x/image/draw"
+	_ "golang.org/x/image/webp"
+)
+
+const (
+	MaxImageSize        = 5 << 20
+	MaxImagesPerAuction = 10
+	ThumbnailSize       = 320
+
+	// Limitam a memória usada para decodificar uma foto: 4000x4000 em RGBA
+	// ocupa 64 MB, e no máximo maxConcurrentDecodes fotos são decodificadas
+	// ao mesmo tempo
+	maxImageDimension    = 4000
+	maxImagePixels       = 4000 * 4000
+	maxConcurrentDecodes = 4
+	thumbnailQuality     = 80
+)
+
+var decodeSlots = make(chan struct{}, maxConcurrentDecodes)
+
+var imageExtensions = map[string]string{
+	"image/jpeg": "jpg",
+	"image/png":  "png",
+	"image/gif":  "gif",
+	"image/webp": "webp",
+}
+
+// AuctionImage guarda os metadados de uma foto do leilão. Os arquivos ficam
+// no ImageStorage, sob Key e ThumbnailKey.
+type AuctionImage struct {
+	Id           string
+	ContentType  string
+	Size         int64
+	Width        int
+	Height       int
+	Key          string
+	URL          string
+	ThumbnailKey string
+	ThumbnailURL string
+	CreatedAt    time.Time
+}
+
+// ProcessedImage é uma foto validada, pronta para ser gravada junto com a miniatura.
+type ProcessedImage struct {
+	Image     AuctionImage
+	Data      []byte
+	Thumbnail []byte
+}
+
+// ProcessImage valida tipo e tamanho da foto pelo conteúdo, não pelo nome do
+// arquivo, e gera uma miniatura JPEG que cabe em ThumbnailSize x ThumbnailSize.
+// As dimensões são conferidas pelo cabeçalho antes de decodificar a imagem.
+func ProcessImage(
+	ctx context.Context, auctionId string, data []byte) (*ProcessedImage, *internal_error.InternalError) {
+	if len(data) == 0 {
+		return nil, internal_error.NewBadRequestError("image file is empty")
+	}
+
+	if len(data) > MaxImageSize {
+		return nil, internal_error.NewBadRequestError(
+			fmt.Sprintf("image must have at most %d bytes", MaxImageSize))
+	}
+
+	contentType := http.DetectContentType(data)
+	extension, ok := imageExtensions[contentType]
+	if !ok {
+		return nil, internal_error.NewBadRequestError("image must be a JPEG, PNG, GIF or WebP file")
+	}
+
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, internal_error.NewBadRequestError("image file is corrupted")
+	}
+
+	if config.Width > maxImageDimension || config.Height > maxImageDimension ||
+		int64(config.Width)*int64(config.Height) > maxImagePixels {
+		return nil, internal_error.NewBadRequestError(
+			fmt.Sprintf("image dimensions must be at most %dx%d", maxImageDimension, maxImageDimension))
+	}
+
+	select {
+	case decodeSlots <- struct{}{}:
+	case <-ctx.Done():
+		return nil, internal_error.NewInternalServerError("Error trying to process image")
+	}
+	thumbnail, internalErr := decodeThumbnail(data)
+	<-decodeSlots
+
+	if internalErr != nil {
+		return nil, internalErr
+	}
+
+	imageId := uuid.New().String()
+	return &ProcessedImage{
+		Image: AuctionImage{
+			Id:           imageId,
+			ContentType:  contentType,
+			Size:         int64(len(data)),
+			Width:        config.Width,
+			Height:       config.Height,
+			Key:          fmt.Sprintf("auctions/%s/%s.%s", auctionId, imageId, extension),
+			ThumbnailKey: fmt.Sprintf("auctions/%s/%s_thumb.jpg", auctionId, imageId),
+			CreatedAt:    time.Now(),
+		},
+		Data:      data,
+		Thumbnail: thumbnail,
+	}, nil
+}
+
+func decodeThumbnail(data []byte) ([]byte, *internal_error.InternalError) {
+	source, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, internal_error.NewBadRequestError("image file is corrupted")
+	}
+
+	encoded, err := thumbnail(source)
+	if err != nil {
+		return nil, internal_error.NewInternalServerError("Error trying to generate image thumbnail")
+	}
+
+	return encoded, nil
+}
+
+// thumbnail reduz a imagem mantendo a proporção; áreas transparentes ficam brancas.
+func thumbnail(source image.Image) ([]byte, error) {
+	bounds := source.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	if width > ThumbnailSize || height > ThumbnailSize {
+		if width >= height {
+			width, height = ThumbnailSize, height*ThumbnailSize/width
+		} else {
+			width, height = width*ThumbnailSize/height, ThumbnailSize
+		}
+	}
+	if width < 1 {
+		width = 1
+	}
+	if height < 1 {
+		height = 1
+	}
+
+	destination := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(destination, destination.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+	xdraw.ApproxBiLinear.Scale(destination, destination.Bounds(), source, bounds, draw.Over, nil)
+
+	var encoded bytes.Buffer
+	if err := jpeg.Encode(&encoded, destination, &jpeg.Options{Quality: thumbnailQuality}); err != nil {
+		return nil, err
+	}
+
+	return encoded.Bytes(), nil
+}
+
+func (au *Auction) FindImage(imageId string) *AuctionImage {
+	for i := range au.Images {
+		if au.Images[i].Id == imageId {
+			return &au.Images[i]
+		}
+	}
+
+	return nil
+}
+
+// ImageStorage grava os arquivos das fotos e informa a URL pública de cada um.
+type ImageStorage interface {
+	PutObject(ctx context.Context, key, contentType string, data []byte) *internal_error.InternalError
+
+	DeleteObject(ctx context.Context, key string) *internal_error.InternalError
+
+	ObjectURL(key string) string
+}
@@ -0,0 +1,63 @@
+package auction_entity
+
+import (
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/internal_error"
+)
+
+type AuctionSort string
+
+const (
+	SortNewest         AuctionSort = "newest"
+	SortEndingSoonest  AuctionSort = "ending_soonest"
+	SortHighestBid     AuctionSort = "highest_bid"
+	SortMostBids       AuctionSort = "most_bids"
+	DefaultAuctionSort             = SortNewest
+
+	DefaultPageLimit = 20
+	MaxPageLimit     = 100
+)
+
+// AuctionQuery descreve uma página da listagem de leilões. O PageToken é
+// opaco para quem chama e só vale para a mesma ordenação que o gerou.
+// CategoryIds já inclui as subcategorias da categoria pedida.
+type AuctionQuery struct {
+	SellerId    string
+	Status      *AuctionStatus
+	CategoryIds []string
+	ProductName string
+	Sort        AuctionSort
+	Limit       int
+	PageToken   string
+}
+
+type AuctionPage struct {
+	Auctions      []Auction
+	NextPageToken string
+}
+
+// Normalize aplica os valores padrão e recusa ordenações e limites inválidos.
+func (q *AuctionQuery) Normalize() *internal_error.InternalError {
+	if q.Sort == "" {
+		q.Sort = DefaultAuctionSort
+	}
+
+	switch q.Sort {
+	case SortNewest, SortEndingSoonest, SortHighestBid, SortMostBids:
+	default:
+		return internal_error.NewBadRequestError("sort must be one of newest, ending_soonest, highest_bid, most_bids")
+	}
+
+	if q.Limit == 0 {
+		q.Limit = DefaultPageLimit
+	}
+
+	if q.Limit < 0 || q.Limit > MaxPageLimit {
+		return internal_error.NewBadRequestError("limit must be between 1 and 100")
+	}
+
+	if q.Status != nil && !q.Status.Valid() {
+		return internal_error.NewBadRequestError("invalid auction status")
+	}
+
+	return nil
+}
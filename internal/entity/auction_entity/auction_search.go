@@ -0,0 +1,55 @@
+package auction_entity
+
+import (
+	"context"
+	"strings"
+
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/internal_error"
+)
+
+// Pesos de relevância de cada campo na busca textual
+const (
+	ProductNameSearchWeight = 10
+	CategorySearchWeight    = 5
+	DescriptionSearchWeight = 1
+)
+
+type AuctionSearchQuery struct {
+	Text   string
+	Status *AuctionStatus
+	Limit  int
+}
+
+type AuctionSearchResult struct {
+	Auction Auction
+	Score   float64
+}
+
+// AuctionSearcherInterface permite trocar o mecanismo de busca; os resultados
+// vêm do mais para o menos relevante.
+type AuctionSearcherInterface interface {
+	SearchAuctions(
+		ctx context.Context,
+		query AuctionSearchQuery) ([]AuctionSearchResult, *internal_error.InternalError)
+}
+
+func (q *AuctionSearchQuery) Normalize() *internal_error.InternalError {
+	q.Text = strings.TrimSpace(q.Text)
+	if len(q.Text) < 2 {
+		return internal_error.NewBadRequestError("search text must have at least 2 characters")
+	}
+
+	if q.Limit == 0 {
+		q.Limit = DefaultPageLimit
+	}
+
+	if q.Limit < 0 || q.Limit > MaxPageLimit {
+		return internal_error.NewBadRequestError("limit must be between 1 and 100")
+	}
+
+	if q.Status != nil && !q.Status.Valid() {
+		return internal_error.NewBadRequestError("invalid auction status")
+	}
+
+	return nil
+}
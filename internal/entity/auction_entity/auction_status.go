@@ -0,0 +1,79 @@
+package auction_entity
+
+import (
+	"fmt"
+
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/internal_error"
+)
+
+// statusTransitions lista, para cada status, os status que podem sucedê-lo.
+// Completed, Cancelled e Unsold são finais.
+var statusTransitions = map[AuctionStatus][]AuctionStatus{
+	Draft:     {Scheduled, Active, Cancelled},
+	Scheduled: {Active, Cancelled},
+	Active:    {Completed, Unsold, Cancelled},
+}
+
+func (s AuctionStatus) String() string {
+	switch s {
+	case Draft:
+		return "draft"
+	case Scheduled:
+		return "scheduled"
+	case Active:
+		return "active"
+	case Completed:
+		return "completed"
+	case Cancelled:
+		return "cancelled"
+	case Unsold:
+		return "unsold"
+	default:
+		return fmt.Sprintf("unknown(%d)", int(s))
+	}
+}
+
+func (s AuctionStatus) Valid() bool {
+	return s >= Active && s <= Unsold
+}
+
+func (s AuctionStatus) Final() bool {
+	return s == Completed || s == Cancelled || s == Unsold
+}
+
+func (s AuctionStatus) CanTransitionTo(next AuctionStatus) bool {
+	for _, allowed := range statusTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+
+	return false
+}
+
+// PreviousStatuses retorna os status a partir dos quais é possível chegar em
+// next; usado como filtro nas atualizações condicionais do repositório.
+func PreviousStatuses(next AuctionStatus) []AuctionStatus {
+	var previous []AuctionStatus
+	for _, status := range []AuctionStatus{Draft, Scheduled, Active} {
+		if status.CanTransitionTo(next) {
+			previous = append(previous, status)
+		}
+	}
+
+	return previous
+}
+
+func (au *Auction) TransitionTo(next AuctionStatus) *internal_error.InternalError {
+	if !au.Status.CanTransitionTo(next) {
+		return NewIllegalTransitionError(au.Status, next)
+	}
+
+	au.Status = next
+	return nil
+}
+
+func NewIllegalTransitionError(current, next AuctionStatus) *internal_error.InternalError {
+	return internal_error.NewBadRequestError(
+		fmt.Sprintf("Auction cannot change from %s to %s", current, next))
+}
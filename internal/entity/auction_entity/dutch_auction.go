@@ -0,0 +1,108 @@
+package auction_entity
+
+import (
+	"time"
+
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/entity/money_entity"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/internal_error"
+)
+
+type AuctionType int
+
+const (
+	// EnglishAuction é o leilão tradicional, de lances crescentes
+	EnglishAuction AuctionType = iota
+	// DutchAuction começa no preço inicial e cai com o tempo até alguém comprar
+	DutchAuction
+)
+
+const (
+	maxPriceDrops        = 10000
+	minPriceDropInterval = time.Second
+	maxPriceDropInterval = 24 * time.Hour
+)
+
+// PriceSchedule descreve a queda de preço de um leilão holandês: a cada
+// Interval (em segundos inteiros) desde a abertura o preço cai Decrement
+// unidades menores da moeda, sem passar de FloorPrice.
+type PriceSchedule struct {
+	FloorPrice money_entity.Money
+	Decrement  int64
+	Interval   time.Duration
+}
+
+// SetPriceSchedule transforma o leilão em holandês. O preço inicial é o
+// StartingPrice definido nas regras de lance.
+func (au *Auction) SetPriceSchedule(
+	floorPrice money_entity.Money,
+	decrement int64,
+	interval time.Duration) *internal_error.InternalError {
+	au.Type = DutchAuction
+	au.PriceSchedule = PriceSchedule{
+		FloorPrice: floorPrice,
+		Decrement:  decrement,
+		Interval:   interval,
+	}
+
+	return au.Validate()
+}
+
+func (au *Auction) validateType() *internal_error.InternalError {
+	switch au.Type {
+	case EnglishAuction:
+		return nil
+	case DutchAuction:
+	default:
+		return internal_error.NewBadRequestError("invalid auction type")
+	}
+
+	if au.HasReserve() || au.HasBuyNow() {
+		return internal_error.NewBadRequestError("dutch auctions do not support reserve or buy now prices")
+	}
+
+	schedule := au.PriceSchedule
+	if schedule.FloorPrice.Units <= 0 || !schedule.FloorPrice.In(au.Currency) ||
+		!au.StartingPrice.GreaterThan(schedule.FloorPrice) ||
+		schedule.Decrement <= 0 || schedule.Decrement > money_entity.MaxUnits ||
+		schedule.Interval < minPriceDropInterval || schedule.Interval > maxPriceDropInterval ||
+		schedule.Interval%time.Second != 0 {
+		return internal_error.NewBadRequestError("invalid dutch auction price schedule")
+	}
+
+	if au.priceDrops() > maxPriceDrops {
+		return internal_error.NewBadRequestError("dutch auction price schedule must reach the floor in at most 10000 drops")
+	}
+
+	return nil
+}
+
+// priceDrops é quantas quedas levam o preço inicial até o piso
+func (au *Auction) priceDrops() int64 {
+	distance := au.StartingPrice.Units - au.PriceSchedule.FloorPrice.Units
+	return (distance + au.PriceSchedule.Decrement - 1) / au.PriceSchedule.Decrement
+}
+
+// CurrentPrice é o preço de compra de um leilão holandês no instante informado.
+// Antes da abertura vale o preço inicial.
+func (au *Auction) CurrentPrice(at time.Time) money_entity.Money {
+	if au.Type != DutchAuction {
+		return au.StartingPrice
+	}
+
+	elapsed := at.Sub(au.OpensAt())
+	if elapsed < 0 {
+		elapsed = 0
+	}
+
+	drops := int64(elapsed / au.PriceSchedule.Interval)
+	if drops >= au.priceDrops() {
+		return au.PriceSchedule.FloorPrice
+	}
+
+	return money_entity.New(au.StartingPrice.Units-drops*au.PriceSchedule.Decrement, au.Currency)
+}
+
+// FloorReachedAt é o momento em que o preço de um leilão holandês chega ao piso.
+func (au *Auction) FloorReachedAt() time.Time {
+	return au.OpensAt().Add(time.Duration(au.priceDrops()) * au.PriceSchedule.Interval)
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"time"
 
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/entity/money_entity"
 	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/internal_error"
 	"github.com/google/uuid"
 )
@@ -12,16 +13,34 @@ type Bid struct {
 	Id        string
 	UserId    string
 	AuctionId string
-	Amount    float64
+	Amount    money_entity.Money
 	Timestamp time.Time
+
+	// MaxAmount é o limite do lance automático; não faz parte do histórico
+	MaxAmount money_entity.Money
+	Automatic bool
+
+	// BuyNow indica o lance que encerrou o leilão pela compra imediata
+	BuyNow bool
+
+	// DutchPurchase indica o lance que arrematou um leilão holandês pelo preço corrente
+	DutchPurchase bool
+
+	// Void indica lance anulado pelo cancelamento do leilão
+	Void bool
 }
 
-func CreateBid(userId, auctionId string, amount float64) (*Bid, *internal_error.InternalError) {
+// CreateBid aceita um valor, um máximo para lances automáticos ou ambos.
+// Sem valor, o sistema usa o menor lance aceito limitado pelo máximo.
+func CreateBid(
+	userId, auctionId string,
+	amount, maxAmount money_entity.Money) (*Bid, *internal_error.InternalError) {
 	bid := &Bid{
 		Id:        uuid.New().String(),
 		UserId:    userId,
 		AuctionId: auctionId,
 		Amount:    amount,
+		MaxAmount: maxAmount,
 		Timestamp: time.Now(),
 	}
 
@@ -32,26 +51,131 @@ func CreateBid(userId, auctionId string, amount float64) (*Bid, *internal_error.
 	return bid, nil
 }
 
+// CreateBuyNowBid registra a compra imediata como um lance no preço de compra.
+func CreateBuyNowBid(
+	userId, auctionId string,
+	buyNowPrice money_entity.Money) (*Bid, *internal_error.InternalError) {
+	bid, err := CreateBid(userId, auctionId, buyNowPrice, money_entity.New(0, buyNowPrice.Currency))
+	if err != nil {
+		return nil, err
+	}
+
+	bid.BuyNow = true
+	return bid, nil
+}
+
+// CreateDutchPurchaseBid registra o arremate de um leilão holandês como um
+// lance no preço corrente.
+func CreateDutchPurchaseBid(
+	userId, auctionId string,
+	currentPrice money_entity.Money) (*Bid, *internal_error.InternalError) {
+	bid, err := CreateBid(userId, auctionId, currentPrice, money_entity.New(0, currentPrice.Currency))
+	if err != nil {
+		return nil, err
+	}
+
+	bid.DutchPurchase = true
+	return bid, nil
+}
+
 func (b *Bid) Validate() *internal_error.InternalError {
 	if err := uuid.Validate(b.UserId); err != nil {
 		return internal_error.NewBadRequestError("UserId is not a valid id")
 	} else if err := uuid.Validate(b.AuctionId); err != nil {
 		return internal_error.NewBadRequestError("AuctionId is not a valid id")
-	} else if b.Amount <= 0 {
+	} else if !money_entity.Supported(b.Amount.Currency) || b.MaxAmount.Currency != b.Amount.Currency {
+		return internal_error.NewBadRequestError("Currency is not supported")
+	} else if b.Amount.IsNegative() || b.MaxAmount.IsNegative() || (b.Amount.IsZero() && b.MaxAmount.IsZero()) {
 		return internal_error.NewBadRequestError("Amount is not a valid value")
+	} else if !b.MaxAmount.IsZero() && b.MaxAmount.LessThan(b.Amount) {
+		return internal_error.NewBadRequestError("MaxAmount must be greater than or equal to Amount")
 	}
 
 	return nil
 }
 
+func (b *Bid) Currency() string {
+	return b.Amount.Currency
+}
+
+type RejectionReason string
+
+const (
+	AuctionNotFound   RejectionReason = "auction_not_found"
+	AuctionClosed     RejectionReason = "auction_closed"
+	AuctionNotOpen    RejectionReason = "auction_not_open"
+	AmountTooLow      RejectionReason = "amount_too_low"
+	CurrencyMismatch  RejectionReason = "currency_mismatch"
+	BuyNowUnavailable RejectionReason = "buy_now_unavailable"
+	BidsNotAccepted   RejectionReason = "bids_not_accepted"
+	UserNotFound      RejectionReason = "user_not_found"
+	UserSuspended     RejectionReason = "user_suspended"
+	UserBanned        RejectionReason = "user_banned"
+)
+
+// BidResult é a decisão definitiva sobre um lance; lances aceitos já estão gravados.
+type BidResult struct {
+	Accepted bool
+	Reason   RejectionReason
+	Message  string
+
+	// Lances visíveis gravados, incluindo os gerados automaticamente
+	Bids    []Bid
+	Leading bool
+
+	// MaxBidUpdated indica que o líder só ajustou o próprio máximo, sem novo lance
+	MaxBidUpdated bool
+
+	// Novo término quando o lance prorrogou o leilão (soft close)
+	ExtendedEndTime time.Time
+}
+
+func NewAcceptedBidResult(bids []Bid, leading bool) *BidResult {
+	return &BidResult{
+		Accepted: true,
+		Bids:     bids,
+		Leading:  leading,
+	}
+}
+
+func NewMaxBidUpdatedResult(bids []Bid, leading bool) *BidResult {
+	return &BidResult{
+		Accepted:      true,
+		Bids:          bids,
+		Leading:       leading,
+		MaxBidUpdated: true,
+	}
+}
+
+func NewRejectedBidResult(reason RejectionReason, message string) *BidResult {
+	return &BidResult{
+		Accepted: false,
+		Reason:   reason,
+		Message:  message,
+	}
+}
+
 type BidEntityRepository interface {
-	CreateBid(
+	AcceptBid(
 		ctx context.Context,
-		bidEntities []Bid) *internal_error.InternalError
+		bidEntity *Bid) (*BidResult, *internal_error.InternalError)
 
 	FindBidByAuctionId(
 		ctx context.Context, auctionId string) ([]Bid, *internal_error.InternalError)
 
 	FindWinningBidByAuctionId(
 		ctx context.Context, auctionId string) (*Bid, *internal_error.InternalError)
+
+	// FindWinningBidsByAuctionIds devolve o lance vencedor de cada leilão que já tem lances
+	FindWinningBidsByAuctionIds(
+		ctx context.Context, auctionIds []string) (map[string]Bid, *internal_error.InternalError)
+
+	// CancelAuction cancela o leilão e anula seus lances na mesma transação;
+	// com withoutBids, só se ele não tiver lances
+	CancelAuction(
+		ctx context.Context, auctionId string, withoutBids bool) *internal_error.InternalError
+
+	// BuyNow encerra o leilão na hora, com o usuário como vencedor
+	BuyNow(
+		ctx context.Context, userId, auctionId string) (*BidResult, *internal_error.InternalError)
 }
@@ -0,0 +1,24 @@
+package bid_entity
+
+import (
+	"testing"
+
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/entity/money_entity"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPurchaseBidsAreFlaggedByKind(t *testing.T) {
+	userId, auctionId := uuid.New().String(), uuid.New().String()
+
+	buyNow, err := CreateBuyNowBid(userId, auctionId, money_entity.New(50000, "BRL"))
+	assert.Nil(t, err)
+	assert.True(t, buyNow.BuyNow)
+	assert.False(t, buyNow.DutchPurchase)
+
+	dutch, err := CreateDutchPurchaseBid(userId, auctionId, money_entity.New(42000, "BRL"))
+	assert.Nil(t, err)
+	assert.False(t, dutch.BuyNow)
+	assert.True(t, dutch.DutchPurchase)
+	assert.Equal(t, money_entity.New(42000, "BRL"), dutch.Amount)
+}
@@ -0,0 +1,106 @@
+package bid_entity
+
+import (
+	"time"
+
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/entity/money_entity"
+	"github.com/google/uuid"
+)
+
+// MaxBid é o valor máximo que um usuário autoriza o sistema a ofertar por ele.
+type MaxBid struct {
+	UserId    string
+	AuctionId string
+	MaxAmount money_entity.Money
+	Timestamp time.Time
+}
+
+// ResolveProxyBids gera os lances automáticos provocados pelo lance atual.
+// Entre dois máximos concorrentes só aparecem no histórico o último valor que
+// o perdedor alcança e a resposta do vencedor; empates ficam com o máximo
+// registrado primeiro.
+func ResolveProxyBids(
+	highest Bid,
+	maxBids []MaxBid,
+	minimumBid func(highestAmount money_entity.Money) money_entity.Money) []Bid {
+	var generated []Bid
+
+	place := func(userId string, amount money_entity.Money) {
+		highest = newAutomaticBid(userId, highest.AuctionId, amount)
+		generated = append(generated, highest)
+	}
+
+	for {
+		next := minimumBid(highest.Amount)
+		challenger := strongestChallenger(maxBids, highest.UserId, next)
+		if challenger == nil {
+			return generated
+		}
+
+		leaderMax := highest.Amount
+		leaderMaxBid := findMaxBid(maxBids, highest.UserId)
+		if leaderMaxBid != nil && leaderMaxBid.MaxAmount.GreaterThan(leaderMax) {
+			leaderMax = leaderMaxBid.MaxAmount
+		}
+
+		leaderWins := leaderMax.GreaterThan(challenger.MaxAmount) ||
+			(leaderMax == challenger.MaxAmount && leaderMaxBid != nil &&
+				!leaderMaxBid.Timestamp.After(challenger.Timestamp))
+
+		switch {
+		case leaderWins && leaderMax.GreaterThan(challenger.MaxAmount):
+			leaderId := highest.UserId
+			place(challenger.UserId, challenger.MaxAmount)
+			place(leaderId, money_entity.Min(leaderMax, minimumBid(challenger.MaxAmount)))
+		case leaderWins:
+			place(highest.UserId, leaderMax)
+		case leaderMax == challenger.MaxAmount:
+			place(challenger.UserId, challenger.MaxAmount)
+		default:
+			if !leaderMax.LessThan(next) {
+				place(highest.UserId, leaderMax)
+				next = minimumBid(highest.Amount)
+			}
+			place(challenger.UserId, money_entity.Min(challenger.MaxAmount, next))
+		}
+	}
+}
+
+func strongestChallenger(maxBids []MaxBid, leaderId string, minimumAmount money_entity.Money) *MaxBid {
+	var strongest *MaxBid
+	for i := range maxBids {
+		maxBid := &maxBids[i]
+		if maxBid.UserId == leaderId || maxBid.MaxAmount.LessThan(minimumAmount) {
+			continue
+		}
+
+		if strongest == nil ||
+			maxBid.MaxAmount.GreaterThan(strongest.MaxAmount) ||
+			(maxBid.MaxAmount == strongest.MaxAmount && maxBid.Timestamp.Before(strongest.Timestamp)) {
+			strongest = maxBid
+		}
+	}
+
+	return strongest
+}
+
+func findMaxBid(maxBids []MaxBid, userId string) *MaxBid {
+	for i := range maxBids {
+		if maxBids[i].UserId == userId {
+			return &maxBids[i]
+		}
+	}
+
+	return nil
+}
+
+func newAutomaticBid(userId, auctionId string, amount money_entity.Money) Bid {
+	return Bid{
+		Id:        uuid.New().String(),
+		UserId:    userId,
+		AuctionId: auctionId,
+		Amount:    amount,
+		Timestamp: time.Now(),
+		Automatic: true,
+	}
+}
@@ -0,0 +1,122 @@
+package bid_entity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/entity/money_entity"
+	"github.com/stretchr/testify/assert"
+)
+
+func brl(amount int64) money_entity.Money {
+	return money_entity.New(amount*100, "BRL")
+}
+
+func minimumBidWithIncrement(increment int64) func(money_entity.Money) money_entity.Money {
+	return func(highestAmount money_entity.Money) money_entity.Money {
+		return highestAmount.Add(increment * 100)
+	}
+}
+
+func visibleAmounts(bids []Bid) []string {
+	var amounts []string
+	for _, bid := range bids {
+		amounts = append(amounts, bid.Amount.String())
+	}
+	return amounts
+}
+
+func TestResolveProxyBids(t *testing.T) {
+	base := time.Now()
+
+	t.Run("no competing max bids generates nothing", func(t *testing.T) {
+		highest := Bid{UserId: "alice", AuctionId: "auction", Amount: brl(100)}
+
+		generated := ResolveProxyBids(highest, nil, minimumBidWithIncrement(5))
+
+		assert.Empty(t, generated)
+	})
+
+	t.Run("max bid outbids the current leader by one increment", func(t *testing.T) {
+		highest := Bid{UserId: "bob", AuctionId: "auction", Amount: brl(110)}
+		maxBids := []MaxBid{
+			{UserId: "alice", MaxAmount: brl(200), Timestamp: base},
+		}
+
+		generated := ResolveProxyBids(highest, maxBids, minimumBidWithIncrement(5))
+
+		assert.Equal(t, []string{"115.00"}, visibleAmounts(generated))
+		assert.Equal(t, "alice", generated[0].UserId)
+		assert.True(t, generated[0].Automatic)
+	})
+
+	t.Run("two max bids show only the final exchange", func(t *testing.T) {
+		highest := Bid{UserId: "bob", AuctionId: "auction", Amount: brl(110)}
+		maxBids := []MaxBid{
+			{UserId: "alice", MaxAmount: brl(200), Timestamp: base},
+			{UserId: "bob", MaxAmount: brl(150), Timestamp: base.Add(time.Second)},
+		}
+
+		generated := ResolveProxyBids(highest, maxBids, minimumBidWithIncrement(5))
+
+		assert.Equal(t, []string{"150.00", "155.00"}, visibleAmounts(generated))
+		assert.Equal(t, "bob", generated[0].UserId)
+		assert.Equal(t, "alice", generated[1].UserId)
+	})
+
+	t.Run("winner never bids above its own max", func(t *testing.T) {
+		highest := Bid{UserId: "bob", AuctionId: "auction", Amount: brl(110)}
+		maxBids := []MaxBid{
+			{UserId: "alice", MaxAmount: brl(152), Timestamp: base},
+			{UserId: "bob", MaxAmount: brl(150), Timestamp: base.Add(time.Second)},
+		}
+
+		generated := ResolveProxyBids(highest, maxBids, minimumBidWithIncrement(5))
+
+		assert.Equal(t, []string{"150.00", "152.00"}, visibleAmounts(generated))
+		assert.Equal(t, "alice", generated[1].UserId)
+	})
+
+	t.Run("equal max bids go to the earliest one", func(t *testing.T) {
+		highest := Bid{UserId: "bob", AuctionId: "auction", Amount: brl(110)}
+		maxBids := []MaxBid{
+			{UserId: "alice", MaxAmount: brl(150), Timestamp: base},
+			{UserId: "bob", MaxAmount: brl(150), Timestamp: base.Add(time.Second)},
+		}
+
+		generated := ResolveProxyBids(highest, maxBids, minimumBidWithIncrement(5))
+
+		assert.Equal(t, []string{"150.00"}, visibleAmounts(generated))
+		assert.Equal(t, "alice", generated[0].UserId)
+	})
+
+	t.Run("leader with higher max answers the challenger", func(t *testing.T) {
+		highest := Bid{UserId: "alice", AuctionId: "auction", Amount: brl(100)}
+		maxBids := []MaxBid{
+			{UserId: "alice", MaxAmount: brl(300), Timestamp: base},
+			{UserId: "bob", MaxAmount: brl(180), Timestamp: base.Add(time.Second)},
+		}
+
+		generated := ResolveProxyBids(highest, maxBids, minimumBidWithIncrement(5))
+
+		assert.Equal(t, []string{"180.00", "185.00"}, visibleAmounts(generated))
+		assert.Equal(t, "alice", generated[1].UserId)
+	})
+}
+
+func TestCreateBidWithMaxAmount(t *testing.T) {
+	userId := "1c5a6f2c-6d43-4f0a-9a0e-8d1d38d0f6a1"
+	auctionId := "0f6a3b7e-3a1f-4d8e-9c55-0b8a9c1d2e3f"
+
+	_, err := CreateBid(userId, auctionId, brl(0), brl(150))
+	assert.Nil(t, err)
+
+	_, err = CreateBid(userId, auctionId, brl(0), brl(0))
+	assert.NotNil(t, err)
+
+	_, err = CreateBid(userId, auctionId, brl(200), brl(150))
+	assert.NotNil(t, err)
+
+	_, err = CreateBid(userId, auctionId, brl(100), money_entity.New(15000, "USD"))
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,146 @@
+package category_entity
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/internal_error"
+	"github.com/google/uuid"
+	"golang.org/x/text/unicode/norm"
+)
+
+const maximumNameLength = 60
+
+var slugPattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+// Category é um nó da árvore de categorias. Categorias raiz não têm ParentId.
+type Category struct {
+	Id        string
+	Name      string
+	Slug      string
+	ParentId  string
+	CreatedAt time.Time
+}
+
+func CreateCategory(name, slug, parentId string) (*Category, *internal_error.InternalError) {
+	category := &Category{
+		Id:        uuid.New().String(),
+		CreatedAt: time.Now(),
+	}
+
+	if err := category.Update(name, slug, parentId); err != nil {
+		return nil, err
+	}
+
+	return category, nil
+}
+
+// Update substitui os dados editáveis da categoria. O slug é gerado a partir
+// do nome quando não é informado.
+func (c *Category) Update(name, slug, parentId string) *internal_error.InternalError {
+	c.Name = strings.TrimSpace(name)
+	c.Slug = strings.TrimSpace(slug)
+	c.ParentId = strings.TrimSpace(parentId)
+
+	if c.Slug == "" {
+		c.Slug = Slugify(c.Name)
+	}
+
+	return c.Validate()
+}
+
+func (c *Category) Validate() *internal_error.InternalError {
+	if len(c.Name) < 2 || len(c.Name) > maximumNameLength {
+		return internal_error.NewBadRequestError("category name must have between 2 and 60 characters")
+	}
+
+	if !slugPattern.MatchString(c.Slug) {
+		return internal_error.NewBadRequestError(
+			"category slug must contain only lowercase letters, numbers and single hyphens")
+	}
+
+	if c.ParentId != "" {
+		if err := uuid.Validate(c.ParentId); err != nil {
+			return internal_error.NewBadRequestError("category parent must be a valid id")
+		}
+
+		if c.ParentId == c.Id {
+			return internal_error.NewBadRequestError("category cannot be its own parent")
+		}
+	}
+
+	return nil
+}
+
+// Slugify gera um identificador legível para URLs: sem acentos, em caixa
+// baixa e com hífens no lugar de espaços e pontuação.
+func Slugify(text string) string {
+	var slug strings.Builder
+	hyphen := false
+	for _, r := range norm.NFD.String(strings.ToLower(text)) {
+		switch {
+		case unicode.Is(unicode.Mn, r):
+			continue
+		case r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)):
+			if hyphen && slug.Len() > 0 {
+				slug.WriteByte('-')
+			}
+			slug.WriteRune(r)
+			hyphen = false
+		default:
+			hyphen = true
+		}
+	}
+
+	return slug.String()
+}
+
+// FindCategory localiza uma categoria pelo id ou pelo slug.
+func FindCategory(categories []Category, idOrSlug string) *Category {
+	for i := range categories {
+		if categories[i].Id == idOrSlug || categories[i].Slug == idOrSlug {
+			return &categories[i]
+		}
+	}
+
+	return nil
+}
+
+// Descendants retorna o id da categoria informada seguido dos ids de todas
+// as suas subcategorias, em qualquer nível.
+func Descendants(categories []Category, categoryId string) []string {
+	children := make(map[string][]string)
+	for _, category := range categories {
+		if category.ParentId != "" {
+			children[category.ParentId] = append(children[category.ParentId], category.Id)
+		}
+	}
+
+	ids := []string{categoryId}
+	visited := map[string]bool{categoryId: true}
+	for i := 0; i < len(ids); i++ {
+		for _, childId := range children[ids[i]] {
+			if !visited[childId] {
+				visited[childId] = true
+				ids = append(ids, childId)
+			}
+		}
+	}
+
+	return ids
+}
+
+type CategoryRepositoryInterface interface {
+	CreateCategory(ctx context.Context, category *Category) *internal_error.InternalError
+
+	FindCategories(ctx context.Context) ([]Category, *internal_error.InternalError)
+
+	FindCategoryById(ctx context.Context, id string) (*Category, *internal_error.InternalError)
+
+	UpdateCategory(ctx context.Context, category *Category) *internal_error.InternalError
+
+	DeleteCategory(ctx context.Context, id string) *internal_error.InternalError
+}
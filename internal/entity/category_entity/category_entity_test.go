@@ -0,0 +1,66 @@
+package category_entity
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateCategory(t *testing.T) {
+	tests := []struct {
+		name         string
+		categoryName string
+		slug         string
+		parentId     string
+		expectedSlug string
+		valid        bool
+	}{
+		{"slug from name", "Eletrônicos & Informática", "", "", "eletronicos-informatica", true},
+		{"explicit slug", "Celulares", "smartphones", uuid.New().String(), "smartphones", true},
+		{"short name", "A", "", "", "", false},
+		{"invalid slug", "Celulares", "Celulares--Novos", "", "", false},
+		{"invalid parent", "Celulares", "", "electronics", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			category, err := CreateCategory(tt.categoryName, tt.slug, tt.parentId)
+			if tt.valid {
+				assert.Nil(t, err)
+				assert.NotEmpty(t, category.Id)
+				assert.Equal(t, tt.expectedSlug, category.Slug)
+			} else {
+				assert.Nil(t, category)
+				assert.Equal(t, "bad_request", err.Err)
+			}
+		})
+	}
+}
+
+func TestCategoryCannotBeItsOwnParent(t *testing.T) {
+	category, err := CreateCategory("Celulares", "", "")
+	assert.Nil(t, err)
+
+	err = category.Update("Celulares", "", category.Id)
+	assert.NotNil(t, err)
+	assert.Equal(t, "bad_request", err.Err)
+}
+
+func TestDescendants(t *testing.T) {
+	categories := []Category{
+		{Id: "electronics", Slug: "eletronicos"},
+		{Id: "phones", Slug: "celulares", ParentId: "electronics"},
+		{Id: "android", Slug: "android", ParentId: "phones"},
+		{Id: "computers", Slug: "computadores", ParentId: "electronics"},
+		{Id: "furniture", Slug: "moveis"},
+	}
+
+	assert.Equal(t, []string{"electronics", "phones", "computers", "android"}, Descendants(categories, "electronics"))
+	assert.Equal(t, []string{"phones", "android"}, Descendants(categories, "phones"))
+	assert.Equal(t, []string{"furniture"}, Descendants(categories, "furniture"))
+
+	assert.Equal(t, "phones", FindCategory(categories, "celulares").Id)
+	assert.Equal(t, "phones", FindCategory(categories, "phones").Id)
+	assert.Nil(t, FindCategory(categories, "livros"))
+}
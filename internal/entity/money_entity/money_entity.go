@@ -0,0 +1,182 @@
+package money_entity
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/internal_error"
+)
+
+// DefaultCurrency é a moeda dos leilões que não declaram outra, inclusive os
+// gravados antes de existir o campo.
+const DefaultCurrency = "BRL"
+
+// MaxUnits limita os valores aceitos para que os cálculos de incremento não
+// estourem o int64.
+const MaxUnits int64 = 100_000_000_000_000
+
+// Casas decimais de cada moeda aceita (ISO 4217)
+var exponents = map[string]int{
+	"BRL": 2,
+	"USD": 2,
+	"EUR": 2,
+	"GBP": 2,
+	"ARS": 2,
+	"CLP": 0,
+	"JPY": 0,
+}
+
+var decimalPattern = regexp.MustCompile(`^(\d+)(?:\.(\d+))?$`)
+
+// Money é um valor monetário em unidades menores da moeda (centavos, no caso
+// do real), sem passar por ponto flutuante.
+type Money struct {
+	Units    int64
+	Currency string
+}
+
+func New(units int64, currency string) Money {
+	return Money{Units: units, Currency: currency}
+}
+
+func Supported(currency string) bool {
+	_, ok := exponents[currency]
+	return ok
+}
+
+// Exponent retorna as casas decimais da moeda; moedas desconhecidas usam duas.
+func Exponent(currency string) int {
+	if exponent, ok := exponents[currency]; ok {
+		return exponent
+	}
+
+	return 2
+}
+
+// Parse lê um decimal exato, como "10.10", na moeda informada. Texto vazio
+// representa zero.
+func Parse(value, currency string) (Money, *internal_error.InternalError) {
+	if !Supported(currency) {
+		return Money{}, internal_error.NewBadRequestError(
+			fmt.Sprintf("currency %s is not supported", currency))
+	}
+
+	units, err := ParseDecimal(value, Exponent(currency))
+	if err != nil {
+		return Money{}, err
+	}
+
+	return New(units, currency), nil
+}
+
+// ParseDecimal converte um decimal sem sinal em inteiro multiplicado por
+// 10^scale. Casas além da escala só são aceitas quando são zeros.
+func ParseDecimal(value string, scale int) (int64, *internal_error.InternalError) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, nil
+	}
+
+	match := decimalPattern.FindStringSubmatch(value)
+	if match == nil {
+		return 0, internal_error.NewBadRequestError(
+			fmt.Sprintf("%s is not a valid non-negative decimal number", value))
+	}
+
+	fraction := strings.TrimRight(match[2], "0")
+	if len(fraction) > scale {
+		return 0, internal_error.NewBadRequestError(
+			fmt.Sprintf("%s has more than %d decimal places", value, scale))
+	}
+
+	digits := match[1] + fraction + strings.Repeat("0", scale-len(fraction))
+	units, err := strconv.ParseInt(digits, 10, 64)
+	if err != nil || units > MaxUnits {
+		return 0, internal_error.NewBadRequestError(
+			fmt.Sprintf("%s exceeds the maximum allowed value", value))
+	}
+
+	return units, nil
+}
+
+// FormatDecimal é o inverso de ParseDecimal: 1010 com escala 2 vira "10.10".
+func FormatDecimal(units int64, scale int) string {
+	sign := ""
+	if units < 0 {
+		sign, units = "-", -units
+	}
+
+	digits := strconv.FormatInt(units, 10)
+	if scale == 0 {
+		return sign + digits
+	}
+
+	if len(digits) <= scale {
+		digits = strings.Repeat("0", scale-len(digits)+1) + digits
+	}
+
+	return sign + digits[:len(digits)-scale] + "." + digits[len(digits)-scale:]
+}
+
+// String formata o valor com as casas decimais da moeda, sem o código.
+func (m Money) String() string {
+	return FormatDecimal(m.Units, Exponent(m.Currency))
+}
+
+// Format inclui o código da moeda, para mensagens: "BRL 10.10".
+func (m Money) Format() string {
+	return m.Currency + " " + m.String()
+}
+
+func (m Money) IsZero() bool {
+	return m.Units == 0
+}
+
+func (m Money) IsNegative() bool {
+	return m.Units < 0
+}
+
+// In indica se o valor pode ser usado em um leilão na moeda informada; zero
+// vale em qualquer moeda.
+func (m Money) In(currency string) bool {
+	return m.Units == 0 || m.Currency == currency
+}
+
+// As comparações assumem a mesma moeda; quem recebe valores de fora deve
+// conferir a moeda antes.
+func (m Money) GreaterThan(other Money) bool {
+	return m.Units > other.Units
+}
+
+func (m Money) LessThan(other Money) bool {
+	return m.Units < other.Units
+}
+
+func (m Money) Add(units int64) Money {
+	return New(m.Units+units, m.Currency)
+}
+
+// Percent calcula basisPoints centésimos de por cento do valor, em unidades
+// menores, arredondando metade para cima.
+func (m Money) Percent(basisPoints int64) int64 {
+	whole, rest := m.Units/10000, m.Units%10000
+	return whole*basisPoints + (rest*basisPoints+5000)/10000
+}
+
+func Min(a, b Money) Money {
+	if b.LessThan(a) {
+		return b
+	}
+
+	return a
+}
+
+func Max(a, b Money) Money {
+	if b.GreaterThan(a) {
+		return b
+	}
+
+	return a
+}
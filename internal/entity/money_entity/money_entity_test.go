@@ -0,0 +1,58 @@
+package money_entity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		value    string
+		currency string
+		units    int64
+		valid    bool
+	}{
+		{"10.10", "BRL", 1010, true},
+		{"10.1", "BRL", 1010, true},
+		{"10", "BRL", 1000, true},
+		{"0.07", "USD", 7, true},
+		{"10.100", "BRL", 1010, true},
+		{"", "BRL", 0, true},
+		{"1500", "JPY", 1500, true},
+		{"10.001", "BRL", 0, false},
+		{"10.5", "JPY", 0, false},
+		{"-1", "BRL", 0, false},
+		{"1e3", "BRL", 0, false},
+		{"10", "XYZ", 0, false},
+		{"99999999999999999999", "BRL", 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value+" "+tt.currency, func(t *testing.T) {
+			money, err := Parse(tt.value, tt.currency)
+			if tt.valid {
+				assert.Nil(t, err)
+				assert.Equal(t, New(tt.units, tt.currency), money)
+			} else {
+				assert.Equal(t, "bad_request", err.Err)
+			}
+		})
+	}
+}
+
+func TestFormat(t *testing.T) {
+	assert.Equal(t, "10.10", New(1010, "BRL").String())
+	assert.Equal(t, "0.05", New(5, "BRL").String())
+	assert.Equal(t, "0.00", New(0, "USD").String())
+	assert.Equal(t, "1500", New(1500, "JPY").String())
+	assert.Equal(t, "BRL 10.10", New(1010, "BRL").Format())
+	assert.Equal(t, "5.25", FormatDecimal(525, 2))
+}
+
+func TestPercent(t *testing.T) {
+	assert.Equal(t, int64(50), New(1000, "BRL").Percent(500))
+	assert.Equal(t, int64(1), New(10, "BRL").Percent(500))
+	assert.Equal(t, int64(0), New(9, "BRL").Percent(500))
+	assert.Equal(t, MaxUnits, New(MaxUnits, "BRL").Percent(10000))
+}
@@ -0,0 +1,85 @@
+package outbox_entity
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/internal_error"
+)
+
+const (
+	AuctionClosedEvent = "auction.closed"
+	BidPlacedEvent     = "bid.placed"
+)
+
+type MessageStatus string
+
+const (
+	Pending   MessageStatus = "pending"
+	Delivered MessageStatus = "delivered"
+	Failed    MessageStatus = "failed"
+)
+
+const maxRetryDelay = time.Hour
+
+// Message é um evento gravado junto com a alteração que o originou. O Id é
+// a chave de idempotência: o mesmo fato gera sempre o mesmo Id.
+type Message struct {
+	Id            string
+	EventType     string
+	AggregateId   string
+	Payload       []byte
+	Status        MessageStatus
+	Attempts      int
+	LastError     string
+	CreatedAt     time.Time
+	NextAttemptAt time.Time
+	DeliveredAt   time.Time
+}
+
+func NewMessage(eventType, aggregateId string, payload interface{}) (*Message, *internal_error.InternalError) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, internal_error.NewInternalServerError("Error trying to encode outbox payload")
+	}
+
+	now := time.Now()
+	return &Message{
+		Id:            eventType + ":" + aggregateId,
+		EventType:     eventType,
+		AggregateId:   aggregateId,
+		Payload:       data,
+		Status:        Pending,
+		CreatedAt:     now,
+		NextAttemptAt: now,
+	}, nil
+}
+
+// RegisterFailure agenda a próxima tentativa com backoff exponencial a partir
+// de baseDelay, limitado a uma hora. Esgotadas as tentativas, a mensagem fica marcada como falha.
+func (m *Message) RegisterFailure(err error, now time.Time, baseDelay time.Duration, maxAttempts int) {
+	m.Attempts++
+	m.LastError = err.Error()
+
+	if m.Attempts >= maxAttempts {
+		m.Status = Failed
+		return
+	}
+
+	delay := baseDelay << uint(m.Attempts-1)
+	if delay <= 0 || delay > maxRetryDelay {
+		delay = maxRetryDelay
+	}
+
+	m.NextAttemptAt = now.Add(delay)
+}
+
+type OutboxRepositoryInterface interface {
+	FindPendingMessages(
+		ctx context.Context, now time.Time, limit int64) ([]Message, *internal_error.InternalError)
+
+	MarkDelivered(ctx context.Context, id string) *internal_error.InternalError
+
+	UpdateFailedMessage(ctx context.Context, message Message) *internal_error.InternalError
+}
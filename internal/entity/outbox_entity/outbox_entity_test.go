@@ -0,0 +1,39 @@
+package outbox_entity
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewMessageUsesIdempotencyKey(t *testing.T) {
+	first, err := NewMessage(AuctionClosedEvent, "auction-id", map[string]string{"auction_id": "auction-id"})
+	assert.Nil(t, err)
+
+	second, err := NewMessage(AuctionClosedEvent, "auction-id", map[string]string{"auction_id": "auction-id"})
+	assert.Nil(t, err)
+
+	assert.Equal(t, "auction.closed:auction-id", first.Id)
+	assert.Equal(t, first.Id, second.Id)
+	assert.Equal(t, Pending, first.Status)
+	assert.JSONEq(t, `{"auction_id":"auction-id"}`, string(first.Payload))
+}
+
+func TestRegisterFailureBacksOffExponentially(t *testing.T) {
+	now := time.Now()
+	message := &Message{Status: Pending}
+
+	message.RegisterFailure(errors.New("timeout"), now, time.Second, 3)
+	assert.Equal(t, now.Add(time.Second), message.NextAttemptAt)
+	assert.Equal(t, Pending, message.Status)
+
+	message.RegisterFailure(errors.New("timeout"), now, time.Second, 3)
+	assert.Equal(t, now.Add(2*time.Second), message.NextAttemptAt)
+
+	message.RegisterFailure(errors.New("timeout"), now, time.Second, 3)
+	assert.Equal(t, Failed, message.Status)
+	assert.Equal(t, 3, message.Attempts)
+	assert.Equal(t, "timeout", message.LastError)
+}
@@ -2,16 +2,86 @@ package user_entity
 
 import (
 	"context"
+	"net/mail"
+	"strings"
+	"time"
 
 	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/internal_error"
+	"github.com/google/uuid"
 )
 
 type User struct {
-	Id   string
-	Name string
+	Id          string
+	Name        string
+	Email       string
+	DisplayName string
+	Status      UserStatus
+	CreatedAt   time.Time
+}
+
+type UserStatus int64
+
+const (
+	Active UserStatus = iota
+	Suspended
+	Banned
+)
+
+func CreateUser(name, email, displayName string) (*User, *internal_error.InternalError) {
+	user := &User{
+		Id:        uuid.New().String(),
+		Status:    Active,
+		CreatedAt: time.Now(),
+	}
+
+	if err := user.Update(name, email, displayName, Active); err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// Update substitui os dados editáveis do usuário. O nome de exibição
+// assume o nome quando não é informado.
+func (u *User) Update(name, email, displayName string, status UserStatus) *internal_error.InternalError {
+	u.Name = strings.TrimSpace(name)
+	u.Email = strings.ToLower(strings.TrimSpace(email))
+	u.DisplayName = strings.TrimSpace(displayName)
+	u.Status = status
+
+	if u.DisplayName == "" {
+		u.DisplayName = u.Name
+	}
+
+	return u.Validate()
+}
+
+func (u *User) Validate() *internal_error.InternalError {
+	if u.Name == "" {
+		return internal_error.NewBadRequestError("name is required")
+	}
+
+	if address, err := mail.ParseAddress(u.Email); err != nil || address.Address != u.Email {
+		return internal_error.NewBadRequestError("email is not a valid address")
+	}
+
+	if u.Status != Active && u.Status != Suspended && u.Status != Banned {
+		return internal_error.NewBadRequestError("invalid user status")
+	}
+
+	return nil
 }
 
 type UserRepositoryInterface interface {
+	CreateUser(
+		ctx context.Context, userEntity *User) *internal_error.InternalError
+
 	FindUserById(
 		ctx context.Context, userId string) (*User, *internal_error.InternalError)
+
+	UpdateUser(
+		ctx context.Context, userEntity *User) *internal_error.InternalError
+
+	DeleteUser(
+		ctx context.Context, userId string) *internal_error.InternalError
 }
@@ -0,0 +1,42 @@
+package user_entity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateUser(t *testing.T) {
+	t.Run("normalizes email and defaults display name", func(t *testing.T) {
+		user, err := CreateUser(" Maria Silva ", " Maria@Example.COM ", "")
+
+		assert.Nil(t, err)
+		assert.NotEmpty(t, user.Id)
+		assert.Equal(t, "maria@example.com", user.Email)
+		assert.Equal(t, "Maria Silva", user.DisplayName)
+		assert.Equal(t, Active, user.Status)
+		assert.False(t, user.CreatedAt.IsZero())
+	})
+
+	t.Run("rejects invalid data", func(t *testing.T) {
+		_, err := CreateUser("", "maria@example.com", "")
+		assert.Equal(t, "bad_request", err.Err)
+
+		_, err = CreateUser("Maria", "not-an-email", "")
+		assert.Equal(t, "bad_request", err.Err)
+
+		_, err = CreateUser("Maria", "Maria <maria@example.com>", "")
+		assert.Equal(t, "bad_request", err.Err)
+	})
+}
+
+func TestUpdateUserStatus(t *testing.T) {
+	user, err := CreateUser("Maria", "maria@example.com", "mari")
+	assert.Nil(t, err)
+
+	assert.Nil(t, user.Update("Maria", "maria@example.com", "mari", Suspended))
+	assert.Equal(t, Suspended, user.Status)
+
+	err = user.Update("Maria", "maria@example.com", "mari", UserStatus(9))
+	assert.Equal(t, "bad_request", err.Err)
+}
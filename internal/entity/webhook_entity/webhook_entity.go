@@ -0,0 +1,142 @@
+package webhook_entity
+
+import (
+	"context"
+	"net/url"
+	"time"
+
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/internal_error"
+	"github.com/google/uuid"
+)
+
+const (
+	AuctionClosedEvent    = "auction.closed"
+	WinnerDeterminedEvent = "auction.winner_determined"
+)
+
+const minimumSecretLength = 16
+
+type Webhook struct {
+	Id         string
+	URL        string
+	Secret     string
+	EventTypes []string
+	CreatedAt  time.Time
+}
+
+func CreateWebhook(
+	webhookURL, secret string, eventTypes []string) (*Webhook, *internal_error.InternalError) {
+	webhook := &Webhook{
+		Id:         uuid.New().String(),
+		URL:        webhookURL,
+		Secret:     secret,
+		EventTypes: eventTypes,
+		CreatedAt:  time.Now(),
+	}
+
+	if err := webhook.Validate(); err != nil {
+		return nil, err
+	}
+
+	return webhook, nil
+}
+
+func (w *Webhook) Validate() *internal_error.InternalError {
+	parsedURL, err := url.ParseRequestURI(w.URL)
+	if err != nil || (parsedURL.Scheme != "http" && parsedURL.Scheme != "https") || parsedURL.Host == "" {
+		return internal_error.NewBadRequestError("url must be an absolute http or https URL")
+	}
+
+	if len(w.Secret) < minimumSecretLength {
+		return internal_error.NewBadRequestError("secret must have at least 16 characters")
+	}
+
+	if len(w.EventTypes) == 0 {
+		return internal_error.NewBadRequestError("at least one event type is required")
+	}
+
+	for _, eventType := range w.EventTypes {
+		if eventType != AuctionClosedEvent && eventType != WinnerDeterminedEvent {
+			return internal_error.NewBadRequestError("event type " + eventType + " is not supported")
+		}
+	}
+
+	return nil
+}
+
+func (w *Webhook) Subscribes(eventType string) bool {
+	for _, subscribed := range w.EventTypes {
+		if subscribed == eventType {
+			return true
+		}
+	}
+
+	return false
+}
+
+type DeliveryStatus string
+
+const (
+	DeliveryPending   DeliveryStatus = "pending"
+	DeliverySucceeded DeliveryStatus = "succeeded"
+	DeliveryFailed    DeliveryStatus = "failed"
+	// O webhook foi removido ou deixou de assinar o evento antes da entrega
+	DeliveryAbandoned DeliveryStatus = "abandoned"
+)
+
+// Delivery registra cada envio de um evento para um webhook, incluindo o
+// corpo assinado, para que as novas tentativas reenviem exatamente o mesmo conteúdo.
+type Delivery struct {
+	Id             string
+	WebhookId      string
+	EventType      string
+	AuctionId      string
+	Payload        []byte
+	Status         DeliveryStatus
+	Attempts       int
+	ResponseStatus int
+	LastError      string
+	CreatedAt      time.Time
+	NextAttemptAt  time.Time
+}
+
+// NewDelivery usa um Id fixo por webhook, evento e leilão, para que a mesma
+// notificação repetida pelo outbox não gere uma segunda entrega.
+func NewDelivery(webhookId, eventType, auctionId string, payload []byte) *Delivery {
+	now := time.Now()
+	return &Delivery{
+		Id:            webhookId + ":" + eventType + ":" + auctionId,
+		WebhookId:     webhookId,
+		EventType:     eventType,
+		AuctionId:     auctionId,
+		Payload:       payload,
+		Status:        DeliveryPending,
+		CreatedAt:     now,
+		NextAttemptAt: now,
+	}
+}
+
+type WebhookRepositoryInterface interface {
+	CreateWebhook(ctx context.Context, webhook *Webhook) *internal_error.InternalError
+
+	FindWebhooks(ctx context.Context) ([]Webhook, *internal_error.InternalError)
+
+	FindWebhookById(ctx context.Context, id string) (*Webhook, *internal_error.InternalError)
+
+	FindWebhooksByEventType(ctx context.Context, eventType string) ([]Webhook, *internal_error.InternalError)
+
+	DeleteWebhook(ctx context.Context, id string) *internal_error.InternalError
+
+	CreateDelivery(ctx context.Context, delivery *Delivery) (bool, *internal_error.InternalError)
+
+	SaveDelivery(ctx context.Context, delivery *Delivery) *internal_error.InternalError
+
+	FindDeliveriesByWebhookId(ctx context.Context, webhookId string) ([]Delivery, *internal_error.InternalError)
+
+	FindPendingDeliveries(ctx context.Context) ([]Delivery, *internal_error.InternalError)
+}
+
+// WebhookDispatcher envia as entregas de forma assíncrona, cuidando das novas tentativas
+type WebhookDispatcher interface {
+	Dispatch(ctx context.Context, webhook Webhook, delivery Delivery)
+}
@@ -0,0 +1,39 @@
+package webhook_entity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateWebhook(t *testing.T) {
+	tests := []struct {
+		name       string
+		url        string
+		secret     string
+		eventTypes []string
+		valid      bool
+	}{
+		{"valid webhook", "https://billing.example.com/hooks", "0123456789abcdef", []string{AuctionClosedEvent}, true},
+		{"relative url", "/hooks", "0123456789abcdef", []string{AuctionClosedEvent}, false},
+		{"unsupported scheme", "ftp://billing.example.com", "0123456789abcdef", []string{AuctionClosedEvent}, false},
+		{"short secret", "https://billing.example.com/hooks", "secret", []string{AuctionClosedEvent}, false},
+		{"no event types", "https://billing.example.com/hooks", "0123456789abcdef", nil, false},
+		{"unknown event type", "https://billing.example.com/hooks", "0123456789abcdef", []string{"bid.placed"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			webhook, err := CreateWebhook(tt.url, tt.secret, tt.eventTypes)
+			if tt.valid {
+				assert.Nil(t, err)
+				assert.NotEmpty(t, webhook.Id)
+				assert.True(t, webhook.Subscribes(AuctionClosedEvent))
+				assert.False(t, webhook.Subscribes(WinnerDeterminedEvent))
+			} else {
+				assert.Nil(t, webhook)
+				assert.Equal(t, "bad_request", err.Err)
+			}
+		})
+	}
+}
@@ -0,0 +1,59 @@
+package event
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/configuration/logger"
+)
+
+type Handler func(ctx context.Context, e Event)
+
+// Bus entrega cada evento, na ordem de registro, a todos os handlers
+// inscritos no seu nome. A entrega é síncrona: handlers lentos devem
+// repassar o trabalho para uma goroutine própria.
+type Bus struct {
+	mu       sync.RWMutex
+	handlers map[string][]Handler
+}
+
+func NewBus() *Bus {
+	return &Bus{
+		handlers: make(map[string][]Handler),
+	}
+}
+
+func (b *Bus) Subscribe(handler Handler, names ...string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for _, name := range names {
+		b.handlers[name] = append(b.handlers[name], handler)
+	}
+}
+
+func (b *Bus) Publish(ctx context.Context, e Event) {
+	if b == nil {
+		return
+	}
+
+	b.mu.RLock()
+	handlers := b.handlers[e.Name()]
+	b.mu.RUnlock()
+
+	for _, handler := range handlers {
+		b.dispatch(ctx, handler, e)
+	}
+}
+
+// dispatch isola a falha de um handler para que os demais ainda recebam o evento
+func (b *Bus) dispatch(ctx context.Context, handler Handler, e Event) {
+	defer func() {
+		if r := recover(); r != nil {
+			logger.Error(fmt.Sprintf("Event handler for %s panicked", e.Name()), fmt.Errorf("%v", r))
+		}
+	}()
+
+	handler(ctx, e)
+}
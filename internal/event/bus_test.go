@@ -0,0 +1,55 @@
+package event
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/entity/bid_entity"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBusDeliversEventsBySubscription(t *testing.T) {
+	bus := NewBus()
+
+	var received []string
+	bus.Subscribe(func(ctx context.Context, e Event) {
+		received = append(received, "bids:"+e.Name())
+	}, BidAcceptedEvent)
+	bus.Subscribe(func(ctx context.Context, e Event) {
+		received = append(received, "all:"+e.Name())
+	}, BidAcceptedEvent, AuctionClosedEvent)
+
+	bus.Publish(context.Background(), BidAccepted{Bid: bid_entity.Bid{Id: "bid"}})
+	bus.Publish(context.Background(), AuctionClosed{AuctionId: "auction"})
+	bus.Publish(context.Background(), AuctionCreated{})
+
+	assert.Equal(t, []string{
+		"bids:" + BidAcceptedEvent,
+		"all:" + BidAcceptedEvent,
+		"all:" + AuctionClosedEvent,
+	}, received)
+}
+
+func TestBusIsolatesPanickingHandlers(t *testing.T) {
+	bus := NewBus()
+
+	delivered := false
+	bus.Subscribe(func(ctx context.Context, e Event) {
+		panic("handler failure")
+	}, AuctionClosedEvent)
+	bus.Subscribe(func(ctx context.Context, e Event) {
+		delivered = true
+	}, AuctionClosedEvent)
+
+	assert.NotPanics(t, func() {
+		bus.Publish(context.Background(), AuctionClosed{AuctionId: "auction"})
+	})
+	assert.True(t, delivered)
+}
+
+func TestNilBusIgnoresEvents(t *testing.T) {
+	var bus *Bus
+	assert.NotPanics(t, func() {
+		bus.Publish(context.Background(), AuctionClosed{AuctionId: "auction"})
+	})
+}
@@ -0,0 +1,55 @@
+package event
+
+import (
+	"time"
+
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/entity/auction_entity"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/entity/bid_entity"
+)
+
+const (
+	AuctionCreatedEvent    = "auction.created"
+	AuctionExtendedEvent   = "auction.extended"
+	AuctionClosedEvent     = "auction.closed"
+	AuctionCancelledEvent  = "auction.cancelled"
+	BidAcceptedEvent       = "bid.accepted"
+	HighestBidChangedEvent = "bid.highest_changed"
+)
+
+type Event interface {
+	Name() string
+}
+
+type AuctionCreated struct {
+	Auction auction_entity.Auction
+}
+
+type AuctionExtended struct {
+	AuctionId string
+	EndTime   time.Time
+}
+
+type AuctionClosed struct {
+	AuctionId string
+	ClosedAt  time.Time
+}
+
+type AuctionCancelled struct {
+	AuctionId   string
+	CancelledAt time.Time
+}
+
+type BidAccepted struct {
+	Bid bid_entity.Bid
+}
+
+type HighestBidChanged struct {
+	Bid bid_entity.Bid
+}
+
+func (AuctionCreated) Name() string    { return AuctionCreatedEvent }
+func (AuctionExtended) Name() string   { return AuctionExtendedEvent }
+func (AuctionClosed) Name() string     { return AuctionClosedEvent }
+func (AuctionCancelled) Name() string  { return AuctionCancelledEvent }
+func (BidAccepted) Name() string       { return BidAcceptedEvent }
+func (HighestBidChanged) Name() string { return HighestBidChangedEvent }
@@ -0,0 +1,76 @@
+package api_key_controller
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/configuration/rest_err"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/infra/api/web/validation"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/usecase/api_key_usecase"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type ApiKeyController struct {
+	apiKeyUseCase api_key_usecase.ApiKeyUseCaseInterface
+}
+
+func NewApiKeyController(apiKeyUseCase api_key_usecase.ApiKeyUseCaseInterface) *ApiKeyController {
+	return &ApiKeyController{
+		apiKeyUseCase: apiKeyUseCase,
+	}
+}
+
+func (u *ApiKeyController) IssueApiKey(c *gin.Context) {
+	var apiKeyInputDTO api_key_usecase.ApiKeyInputDTO
+
+	if err := c.ShouldBindJSON(&apiKeyInputDTO); err != nil {
+		restErr := validation.ValidateErr(err)
+
+		c.JSON(restErr.Code, restErr)
+		return
+	}
+
+	issuedApiKey, err := u.apiKeyUseCase.IssueApiKey(context.Background(), apiKeyInputDTO)
+	if err != nil {
+		restErr := rest_err.ConvertError(err)
+
+		c.JSON(restErr.Code, restErr)
+		return
+	}
+
+	c.JSON(http.StatusCreated, issuedApiKey)
+}
+
+func (u *ApiKeyController) FindApiKeys(c *gin.Context) {
+	apiKeys, err := u.apiKeyUseCase.FindApiKeys(context.Background())
+	if err != nil {
+		errRest := rest_err.ConvertError(err)
+		c.JSON(errRest.Code, errRest)
+		return
+	}
+
+	c.JSON(http.StatusOK, apiKeys)
+}
+
+func (u *ApiKeyController) RevokeApiKey(c *gin.Context) {
+	apiKeyId := c.Param("apiKeyId")
+
+	if err := uuid.Validate(apiKeyId); err != nil {
+		errRest := rest_err.NewBadRequestError("Invalid fields", rest_err.Causes{
+			Field:   "apiKeyId",
+			Message: "Invalid UUID value",
+		})
+
+		c.JSON(errRest.Code, errRest)
+		return
+	}
+
+	if err := u.apiKeyUseCase.RevokeApiKey(context.Background(), apiKeyId); err != nil {
+		errRest := rest_err.ConvertError(err)
+		c.JSON(errRest.Code, errRest)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
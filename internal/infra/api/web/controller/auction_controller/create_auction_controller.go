@@ -5,6 +5,7 @@ import (
 	"net/http"
 
 	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/configuration/rest_err"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/infra/api/web/middleware"
 	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/infra/api/web/validation"
 	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/usecase/auction_usecase"
 	"github.com/gin-gonic/gin"
@@ -30,6 +31,16 @@ func (u *AuctionController) CreateAuction(c *gin.Context) {
 		return
 	}
 
+	// O vendedor é sempre o dono do token
+	identity, ok := middleware.GetIdentity(c)
+	if !ok {
+		restErr := rest_err.NewUnauthorizedError("Invalid or missing access token")
+
+		c.JSON(restErr.Code, restErr)
+		return
+	}
+	auctionInputDTO.SellerId = identity.UserId
+
 	err := u.auctionUseCase.CreateAuction(context.Background(), auctionInputDTO)
 	if err != nil {
 		restErr := rest_err.ConvertError(err)
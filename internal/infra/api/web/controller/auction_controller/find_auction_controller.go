@@ -3,9 +3,9 @@ package auction_controller
 import (
 	"context"
 	"net/http"
-	"strconv"
 
 	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/configuration/rest_err"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/infra/api/web/validation"
 	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/usecase/auction_usecase"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -35,19 +35,16 @@ func (u *AuctionController) FindAuctionById(c *gin.Context) {
 }
 
 func (u *AuctionController) FindAuctions(c *gin.Context) {
-	status := c.Query("status")
-	category := c.Query("category")
-	productName := c.Query("productName")
+	var auctionQueryInputDTO auction_usecase.AuctionQueryInputDTO
 
-	statusNumber, errConv := strconv.Atoi(status)
-	if errConv != nil {
-		errRest := rest_err.NewBadRequestError("Error trying to validate auction status param")
-		c.JSON(errRest.Code, errRest)
+	if err := c.ShouldBindQuery(&auctionQueryInputDTO); err != nil {
+		restErr := validation.ValidateErr(err)
+
+		c.JSON(restErr.Code, restErr)
 		return
 	}
 
-	auctions, err := u.auctionUseCase.FindAuctions(context.Background(),
-		auction_usecase.AuctionStatus(statusNumber), category, productName)
+	auctions, err := u.auctionUseCase.FindAuctions(context.Background(), auctionQueryInputDTO)
 	if err != nil {
 		errRest := rest_err.ConvertError(err)
 		c.JSON(errRest.Code, errRest)
@@ -79,3 +76,23 @@ func (u *AuctionController) FindWinningBidByAuctionId(c *gin.Context) {
 
 	c.JSON(http.StatusOK, auctionData)
 }
+
+func (u *AuctionController) SearchAuctions(c *gin.Context) {
+	var auctionSearchInputDTO auction_usecase.AuctionSearchInputDTO
+
+	if err := c.ShouldBindQuery(&auctionSearchInputDTO); err != nil {
+		restErr := validation.ValidateErr(err)
+
+		c.JSON(restErr.Code, restErr)
+		return
+	}
+
+	results, err := u.auctionUseCase.SearchAuctions(context.Background(), auctionSearchInputDTO)
+	if err != nil {
+		errRest := rest_err.ConvertError(err)
+		c.JSON(errRest.Code, errRest)
+		return
+	}
+
+	c.JSON(http.StatusOK, results)
+}
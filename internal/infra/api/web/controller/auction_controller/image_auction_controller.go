@@ -0,0 +1,98 @@
+package auction_controller
+
+import (
+	"context"
+	"errors"
+	"io"
+	"mime/multipart"
+	"net/http"
+
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/configuration/rest_err"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/entity/auction_entity"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// Folga para os cabeçalhos do multipart além do próprio arquivo
+const multipartOverhead = 1 << 20
+
+func (u *AuctionController) AddAuctionImage(c *gin.Context) {
+	auctionId, identity, ok := managedAuction(c)
+	if !ok {
+		return
+	}
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, auction_entity.MaxImageSize+multipartOverhead)
+
+	fileHeader, err := c.FormFile("image")
+	if err != nil {
+		var maxBytesError *http.MaxBytesError
+		if errors.As(err, &maxBytesError) {
+			errRest := rest_err.NewRequestEntityTooLargeError("image must have at most 5 MB")
+			c.JSON(errRest.Code, errRest)
+			return
+		}
+
+		errRest := rest_err.NewBadRequestError("Invalid fields", rest_err.Causes{
+			Field:   "image",
+			Message: "multipart file field image is required",
+		})
+		c.JSON(errRest.Code, errRest)
+		return
+	}
+
+	data, err := readFormFile(fileHeader)
+	if err != nil {
+		errRest := rest_err.NewBadRequestError("image file could not be read")
+		c.JSON(errRest.Code, errRest)
+		return
+	}
+
+	imageData, useCaseErr := u.auctionUseCase.AddAuctionImage(
+		context.Background(), auctionId, identity.UserId, data)
+	if useCaseErr != nil {
+		errRest := rest_err.ConvertError(useCaseErr)
+		c.JSON(errRest.Code, errRest)
+		return
+	}
+
+	c.JSON(http.StatusCreated, imageData)
+}
+
+func (u *AuctionController) RemoveAuctionImage(c *gin.Context) {
+	auctionId, identity, ok := managedAuction(c)
+	if !ok {
+		return
+	}
+
+	imageId := c.Param("imageId")
+	if err := uuid.Validate(imageId); err != nil {
+		errRest := rest_err.NewBadRequestError("Invalid fields", rest_err.Causes{
+			Field:   "imageId",
+			Message: "Invalid UUID value",
+		})
+		c.JSON(errRest.Code, errRest)
+		return
+	}
+
+	if err := u.auctionUseCase.RemoveAuctionImage(
+		context.Background(), auctionId, imageId, identity.UserId); err != nil {
+		errRest := rest_err.ConvertError(err)
+		c.JSON(errRest.Code, errRest)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// readFormFile lê um byte além do limite para que a validação do tamanho
+// aconteça na entidade.
+func readFormFile(fileHeader *multipart.FileHeader) ([]byte, error) {
+	file, err := fileHeader.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return io.ReadAll(io.LimitReader(file, auction_entity.MaxImageSize+1))
+}
@@ -0,0 +1,105 @@
+package auction_controller
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/configuration/rest_err"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/infra/api/web/middleware"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/infra/api/web/validation"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/usecase/auction_usecase"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+func (u *AuctionController) FindMyAuctions(c *gin.Context) {
+	identity, ok := middleware.GetIdentity(c)
+	if !ok {
+		errRest := rest_err.NewUnauthorizedError("Invalid or missing access token")
+		c.JSON(errRest.Code, errRest)
+		return
+	}
+
+	var sellerAuctionQueryInputDTO auction_usecase.SellerAuctionQueryInputDTO
+	if err := c.ShouldBindQuery(&sellerAuctionQueryInputDTO); err != nil {
+		restErr := validation.ValidateErr(err)
+
+		c.JSON(restErr.Code, restErr)
+		return
+	}
+
+	auctions, err := u.auctionUseCase.FindAuctionsBySeller(
+		context.Background(), identity.UserId, sellerAuctionQueryInputDTO)
+	if err != nil {
+		errRest := rest_err.ConvertError(err)
+		c.JSON(errRest.Code, errRest)
+		return
+	}
+
+	c.JSON(http.StatusOK, auctions)
+}
+
+func (u *AuctionController) UpdateAuction(c *gin.Context) {
+	auctionId, identity, ok := managedAuction(c)
+	if !ok {
+		return
+	}
+
+	var auctionUpdateInputDTO auction_usecase.AuctionUpdateInputDTO
+	if err := c.ShouldBindJSON(&auctionUpdateInputDTO); err != nil {
+		restErr := validation.ValidateErr(err)
+
+		c.JSON(restErr.Code, restErr)
+		return
+	}
+
+	auctionData, err := u.auctionUseCase.UpdateAuction(
+		context.Background(), auctionId, identity.UserId, auctionUpdateInputDTO)
+	if err != nil {
+		errRest := rest_err.ConvertError(err)
+		c.JSON(errRest.Code, errRest)
+		return
+	}
+
+	c.JSON(http.StatusOK, auctionData)
+}
+
+func (u *AuctionController) CancelAuction(c *gin.Context) {
+	auctionId, identity, ok := managedAuction(c)
+	if !ok {
+		return
+	}
+
+	if err := u.auctionUseCase.CancelAuction(context.Background(),
+		auctionId, identity.UserId, identity.HasRole(middleware.RoleAdmin)); err != nil {
+		errRest := rest_err.ConvertError(err)
+		c.JSON(errRest.Code, errRest)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// managedAuction valida o id do leilão e identifica o vendedor da requisição
+func managedAuction(c *gin.Context) (string, *middleware.Identity, bool) {
+	auctionId := c.Param("auctionId")
+
+	if err := uuid.Validate(auctionId); err != nil {
+		errRest := rest_err.NewBadRequestError("Invalid fields", rest_err.Causes{
+			Field:   "auctionId",
+			Message: "Invalid UUID value",
+		})
+
+		c.JSON(errRest.Code, errRest)
+		return "", nil, false
+	}
+
+	identity, ok := middleware.GetIdentity(c)
+	if !ok {
+		errRest := rest_err.NewUnauthorizedError("Invalid or missing access token")
+		c.JSON(errRest.Code, errRest)
+		return "", nil, false
+	}
+
+	return auctionId, identity, true
+}
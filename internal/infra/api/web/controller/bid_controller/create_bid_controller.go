@@ -5,9 +5,12 @@ import (
 	"net/http"
 
 	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/configuration/rest_err"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/entity/bid_entity"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/infra/api/web/middleware"
 	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/infra/api/web/validation"
 	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/usecase/bid_usecase"
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 )
 
 type BidController struct {
@@ -30,7 +33,51 @@ func (u *BidController) CreateBid(c *gin.Context) {
 		return
 	}
 
-	err := u.bidUseCase.CreateBid(context.Background(), bidInputDTO)
+	// O autor do lance é sempre o dono do token
+	identity, ok := middleware.GetIdentity(c)
+	if !ok {
+		restErr := rest_err.NewUnauthorizedError("Invalid or missing access token")
+
+		c.JSON(restErr.Code, restErr)
+		return
+	}
+	bidInputDTO.UserId = identity.UserId
+
+	bidAcceptance, err := u.bidUseCase.CreateBid(context.Background(), bidInputDTO)
+	if err != nil {
+		restErr := rest_err.ConvertError(err)
+
+		c.JSON(restErr.Code, restErr)
+		return
+	}
+
+	c.JSON(bidAcceptanceStatusCode(bidAcceptance), bidAcceptance)
+}
+
+// BuyNow arremata o leilão pelo preço de compra imediata, ou pelo preço
+// corrente nos leilões holandeses, em nome do dono do token
+func (u *BidController) BuyNow(c *gin.Context) {
+	auctionId := c.Param("auctionId")
+
+	if err := uuid.Validate(auctionId); err != nil {
+		restErr := rest_err.NewBadRequestError("Invalid fields", rest_err.Causes{
+			Field:   "auctionId",
+			Message: "Invalid UUID value",
+		})
+
+		c.JSON(restErr.Code, restErr)
+		return
+	}
+
+	identity, ok := middleware.GetIdentity(c)
+	if !ok {
+		restErr := rest_err.NewUnauthorizedError("Invalid or missing access token")
+
+		c.JSON(restErr.Code, restErr)
+		return
+	}
+
+	bidAcceptance, err := u.bidUseCase.BuyNow(context.Background(), auctionId, identity.UserId)
 	if err != nil {
 		restErr := rest_err.ConvertError(err)
 
@@ -38,5 +85,25 @@ func (u *BidController) CreateBid(c *gin.Context) {
 		return
 	}
 
-	c.Status(http.StatusCreated)
+	c.JSON(bidAcceptanceStatusCode(bidAcceptance), bidAcceptance)
+}
+
+func bidAcceptanceStatusCode(bidAcceptance *bid_usecase.BidAcceptanceOutputDTO) int {
+	switch bidAcceptance.Status {
+	case bid_usecase.BidAccepted:
+		return http.StatusCreated
+	case bid_usecase.BidMaxBidUpdated:
+		return http.StatusOK
+	}
+
+	switch bid_entity.RejectionReason(bidAcceptance.Reason) {
+	case bid_entity.AuctionNotFound, bid_entity.UserNotFound:
+		return http.StatusNotFound
+	case bid_entity.UserSuspended, bid_entity.UserBanned:
+		return http.StatusForbidden
+	case bid_entity.BuyNowUnavailable:
+		return http.StatusConflict
+	default:
+		return http.StatusBadRequest
+	}
 }
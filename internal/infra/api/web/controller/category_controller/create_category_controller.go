@@ -0,0 +1,100 @@
+package category_controller
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/configuration/rest_err"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/infra/api/web/validation"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/usecase/category_usecase"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type CategoryController struct {
+	categoryUseCase category_usecase.CategoryUseCaseInterface
+}
+
+func NewCategoryController(categoryUseCase category_usecase.CategoryUseCaseInterface) *CategoryController {
+	return &CategoryController{
+		categoryUseCase: categoryUseCase,
+	}
+}
+
+func (u *CategoryController) CreateCategory(c *gin.Context) {
+	var categoryInputDTO category_usecase.CategoryInputDTO
+
+	if err := c.ShouldBindJSON(&categoryInputDTO); err != nil {
+		restErr := validation.ValidateErr(err)
+
+		c.JSON(restErr.Code, restErr)
+		return
+	}
+
+	categoryData, err := u.categoryUseCase.CreateCategory(context.Background(), categoryInputDTO)
+	if err != nil {
+		restErr := rest_err.ConvertError(err)
+
+		c.JSON(restErr.Code, restErr)
+		return
+	}
+
+	c.JSON(http.StatusCreated, categoryData)
+}
+
+func (u *CategoryController) UpdateCategory(c *gin.Context) {
+	categoryId, ok := validateCategoryId(c)
+	if !ok {
+		return
+	}
+
+	var categoryInputDTO category_usecase.CategoryInputDTO
+	if err := c.ShouldBindJSON(&categoryInputDTO); err != nil {
+		restErr := validation.ValidateErr(err)
+
+		c.JSON(restErr.Code, restErr)
+		return
+	}
+
+	categoryData, err := u.categoryUseCase.UpdateCategory(context.Background(), categoryId, categoryInputDTO)
+	if err != nil {
+		restErr := rest_err.ConvertError(err)
+
+		c.JSON(restErr.Code, restErr)
+		return
+	}
+
+	c.JSON(http.StatusOK, categoryData)
+}
+
+func (u *CategoryController) DeleteCategory(c *gin.Context) {
+	categoryId, ok := validateCategoryId(c)
+	if !ok {
+		return
+	}
+
+	if err := u.categoryUseCase.DeleteCategory(context.Background(), categoryId); err != nil {
+		restErr := rest_err.ConvertError(err)
+
+		c.JSON(restErr.Code, restErr)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func validateCategoryId(c *gin.Context) (string, bool) {
+	categoryId := c.Param("categoryId")
+
+	if err := uuid.Validate(categoryId); err != nil {
+		errRest := rest_err.NewBadRequestError("Invalid fields", rest_err.Causes{
+			Field:   "categoryId",
+			Message: "Invalid UUID value",
+		})
+
+		c.JSON(errRest.Code, errRest)
+		return "", false
+	}
+
+	return categoryId, true
+}
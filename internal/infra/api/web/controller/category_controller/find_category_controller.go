@@ -0,0 +1,36 @@
+package category_controller
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/configuration/rest_err"
+	"github.com/gin-gonic/gin"
+)
+
+func (u *CategoryController) FindCategories(c *gin.Context) {
+	categories, err := u.categoryUseCase.FindCategories(context.Background())
+	if err != nil {
+		errRest := rest_err.ConvertError(err)
+		c.JSON(errRest.Code, errRest)
+		return
+	}
+
+	c.JSON(http.StatusOK, categories)
+}
+
+func (u *CategoryController) FindCategoryById(c *gin.Context) {
+	categoryId, ok := validateCategoryId(c)
+	if !ok {
+		return
+	}
+
+	categoryData, err := u.categoryUseCase.FindCategoryById(context.Background(), categoryId)
+	if err != nil {
+		errRest := rest_err.ConvertError(err)
+		c.JSON(errRest.Code, errRest)
+		return
+	}
+
+	c.JSON(http.StatusOK, categoryData)
+}
@@ -0,0 +1,183 @@
+package stream_controller
+
+import (
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/configuration/logger"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/configuration/rest_err"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/infra/stream"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
+)
+
+const heartbeatInterval = 15 * time.Second
+
+type StreamController struct {
+	hub      *stream.Hub
+	upgrader websocket.Upgrader
+}
+
+func NewStreamController(hub *stream.Hub) *StreamController {
+	return &StreamController{
+		hub: hub,
+		upgrader: websocket.Upgrader{
+			CheckOrigin: checkOrigin(getAllowedOrigins()),
+		},
+	}
+}
+
+// checkOrigin aceita o WebSocket de clientes sem Origin (fora do navegador),
+// da mesma origem da API ou das origens liberadas em STREAM_ALLOWED_ORIGINS.
+// Qualquer outro site é recusado, evitando o sequestro da conexão.
+func checkOrigin(allowedOrigins map[string]struct{}) func(r *http.Request) bool {
+	return func(r *http.Request) bool {
+		origin := r.Header.Get("Origin")
+		if origin == "" {
+			return true
+		}
+
+		if _, ok := allowedOrigins[strings.ToLower(origin)]; ok {
+			return true
+		}
+
+		originURL, err := url.Parse(origin)
+		return err == nil && strings.EqualFold(originURL.Host, r.Host)
+	}
+}
+
+// getAllowedOrigins lê STREAM_ALLOWED_ORIGINS, uma lista separada por
+// vírgulas como "https://app.example.com,https://admin.example.com".
+func getAllowedOrigins() map[string]struct{} {
+	allowedOrigins := make(map[string]struct{})
+	for _, origin := range strings.Split(os.Getenv("STREAM_ALLOWED_ORIGINS"), ",") {
+		origin = strings.TrimRight(strings.TrimSpace(origin), "/")
+		if origin != "" {
+			allowedOrigins[strings.ToLower(origin)] = struct{}{}
+		}
+	}
+
+	return allowedOrigins
+}
+
+// StreamAuctionEvents envia os eventos do leilão via Server-Sent Events.
+func (sc *StreamController) StreamAuctionEvents(c *gin.Context) {
+	auctionId, ok := validateAuctionId(c)
+	if !ok {
+		return
+	}
+
+	subscription := sc.hub.Subscribe(auctionId)
+	defer sc.hub.Unsubscribe(subscription)
+
+	heartbeat := time.NewTicker(heartbeatInterval)
+	defer heartbeat.Stop()
+
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case event, ok := <-subscription.Events:
+			if !ok {
+				return false
+			}
+
+			c.SSEvent(string(event.Type), event)
+			_, final := closeReason(event.Type)
+			return !final
+		case <-heartbeat.C:
+			c.SSEvent("heartbeat", time.Now())
+			return true
+		case <-c.Request.Context().Done():
+			return false
+		}
+	})
+}
+
+// WebSocketAuctionEvents envia os mesmos eventos do SSE por WebSocket.
+func (sc *StreamController) WebSocketAuctionEvents(c *gin.Context) {
+	auctionId, ok := validateAuctionId(c)
+	if !ok {
+		return
+	}
+
+	conn, err := sc.upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		logger.Error("Error trying to upgrade websocket connection", err)
+		return
+	}
+	defer conn.Close()
+
+	subscription := sc.hub.Subscribe(auctionId)
+	defer sc.hub.Unsubscribe(subscription)
+
+	// O cliente só recebe eventos; a leitura serve para detectar o fechamento
+	disconnected := make(chan struct{})
+	go func() {
+		defer close(disconnected)
+		for {
+			if _, _, err := conn.NextReader(); err != nil {
+				return
+			}
+		}
+	}()
+
+	heartbeat := time.NewTicker(heartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case event, ok := <-subscription.Events:
+			if !ok {
+				return
+			}
+
+			if err := conn.WriteJSON(event); err != nil {
+				return
+			}
+
+			if reason, final := closeReason(event.Type); final {
+				conn.WriteMessage(websocket.CloseMessage,
+					websocket.FormatCloseMessage(websocket.CloseNormalClosure, reason))
+				return
+			}
+		case <-heartbeat.C:
+			if err := conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				return
+			}
+		case <-disconnected:
+			return
+		}
+	}
+}
+
+// closeReason indica se o evento encerra o leilão e, com isso, a transmissão.
+func closeReason(eventType stream.EventType) (string, bool) {
+	switch eventType {
+	case stream.AuctionClosed:
+		return "auction closed", true
+	case stream.AuctionCancelled:
+		return "auction cancelled", true
+	default:
+		return "", false
+	}
+}
+
+func validateAuctionId(c *gin.Context) (string, bool) {
+	auctionId := c.Param("auctionId")
+
+	if err := uuid.Validate(auctionId); err != nil {
+		errRest := rest_err.NewBadRequestError("Invalid fields", rest_err.Causes{
+			Field:   "auctionId",
+			Message: "Invalid UUID value",
+		})
+
+		c.JSON(errRest.Code, errRest)
+		return "", false
+	}
+
+	return auctionId, true
+}
@@ -0,0 +1,112 @@
+package stream_controller
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/infra/stream"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckOrigin(t *testing.T) {
+	t.Setenv("STREAM_ALLOWED_ORIGINS", "https://app.example.com/, https://Admin.example.com")
+	check := checkOrigin(getAllowedOrigins())
+
+	tests := []struct {
+		origin   string
+		expected bool
+	}{
+		{"", true},
+		{"http://localhost:8080", true},
+		{"https://app.example.com", true},
+		{"https://admin.example.com", true},
+		{"https://evil.example.com", false},
+		{"https://app.example.com.evil.com", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.origin, func(t *testing.T) {
+			request := httptest.NewRequest("GET", "http://localhost:8080/auction/id/ws", nil)
+			if tt.origin != "" {
+				request.Header.Set("Origin", tt.origin)
+			}
+
+			assert.Equal(t, tt.expected, check(request))
+		})
+	}
+}
+
+func TestStreamsEndWhenAuctionIsCancelled(t *testing.T) {
+	hub := stream.NewHub()
+	controller := NewStreamController(hub)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/auction/:auctionId/events", controller.StreamAuctionEvents)
+	router.GET("/auction/:auctionId/ws", controller.WebSocketAuctionEvents)
+
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	auctionId := uuid.New().String()
+	publishWhenSubscribed := func(count int) {
+		assert.Eventually(t, func() bool { return hub.SubscriberCount(auctionId) == count },
+			time.Second, 5*time.Millisecond)
+		hub.Publish(stream.Event{Type: stream.AuctionCancelled, AuctionId: auctionId})
+	}
+
+	t.Run("websocket", func(t *testing.T) {
+		wsURL := "ws" + strings.TrimPrefix(server.URL, "http") + "/auction/" + auctionId + "/ws"
+		conn, _, err := websocket.DefaultDialer.Dial(wsURL, nil)
+		assert.Nil(t, err)
+		defer conn.Close()
+
+		publishWhenSubscribed(1)
+		conn.SetReadDeadline(time.Now().Add(time.Second))
+
+		var event stream.Event
+		assert.Nil(t, conn.ReadJSON(&event))
+		assert.Equal(t, stream.AuctionCancelled, event.Type)
+
+		_, _, err = conn.ReadMessage()
+		closeErr, ok := err.(*websocket.CloseError)
+		assert.True(t, ok)
+		assert.Equal(t, websocket.CloseNormalClosure, closeErr.Code)
+		assert.Equal(t, "auction cancelled", closeErr.Text)
+	})
+
+	t.Run("server-sent events", func(t *testing.T) {
+		assert.Eventually(t, func() bool { return hub.SubscriberCount(auctionId) == 0 },
+			time.Second, 5*time.Millisecond)
+
+		done := make(chan string)
+		go func() {
+			response, err := http.Get(server.URL + "/auction/" + auctionId + "/events")
+			if err != nil {
+				done <- err.Error()
+				return
+			}
+			defer response.Body.Close()
+
+			// A leitura só termina quando o servidor encerra a transmissão
+			body, _ := io.ReadAll(response.Body)
+			done <- string(body)
+		}()
+
+		publishWhenSubscribed(1)
+
+		select {
+		case body := <-done:
+			assert.Contains(t, body, "event:auction_cancelled")
+		case <-time.After(time.Second):
+			t.Fatal("stream did not end after the auction was cancelled")
+		}
+	})
+}
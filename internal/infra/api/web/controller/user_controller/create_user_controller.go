@@ -0,0 +1,90 @@
+package user_controller
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/configuration/rest_err"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/infra/api/web/validation"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/usecase/user_usecase"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+func (u *UserController) CreateUser(c *gin.Context) {
+	var userInputDTO user_usecase.UserInputDTO
+
+	if err := c.ShouldBindJSON(&userInputDTO); err != nil {
+		restErr := validation.ValidateErr(err)
+
+		c.JSON(restErr.Code, restErr)
+		return
+	}
+
+	userData, err := u.userUseCase.CreateUser(context.Background(), userInputDTO)
+	if err != nil {
+		restErr := rest_err.ConvertError(err)
+
+		c.JSON(restErr.Code, restErr)
+		return
+	}
+
+	c.JSON(http.StatusCreated, userData)
+}
+
+func (u *UserController) UpdateUser(c *gin.Context) {
+	userId, ok := validateUserId(c)
+	if !ok {
+		return
+	}
+
+	var userUpdateInputDTO user_usecase.UserUpdateInputDTO
+	if err := c.ShouldBindJSON(&userUpdateInputDTO); err != nil {
+		restErr := validation.ValidateErr(err)
+
+		c.JSON(restErr.Code, restErr)
+		return
+	}
+
+	userData, err := u.userUseCase.UpdateUser(context.Background(), userId, userUpdateInputDTO)
+	if err != nil {
+		restErr := rest_err.ConvertError(err)
+
+		c.JSON(restErr.Code, restErr)
+		return
+	}
+
+	c.JSON(http.StatusOK, userData)
+}
+
+func (u *UserController) DeleteUser(c *gin.Context) {
+	userId, ok := validateUserId(c)
+	if !ok {
+		return
+	}
+
+	if err := u.userUseCase.DeleteUser(context.Background(), userId); err != nil {
+		restErr := rest_err.ConvertError(err)
+
+		c.JSON(restErr.Code, restErr)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func validateUserId(c *gin.Context) (string, bool) {
+	userId := c.Param("userId")
+
+	if err := uuid.Validate(userId); err != nil {
+		errRest := rest_err.NewBadRequestError("Invalid fields", rest_err.Causes{
+			Field:   "userId",
+			Message: "Invalid UUID value",
+		})
+
+		c.JSON(errRest.Code, errRest)
+		return "", false
+	}
+
+	return userId, true
+}
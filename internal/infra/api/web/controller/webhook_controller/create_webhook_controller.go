@@ -0,0 +1,74 @@
+package webhook_controller
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/configuration/rest_err"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/infra/api/web/validation"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/usecase/webhook_usecase"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type WebhookController struct {
+	webhookUseCase webhook_usecase.WebhookUseCaseInterface
+}
+
+func NewWebhookController(webhookUseCase webhook_usecase.WebhookUseCaseInterface) *WebhookController {
+	return &WebhookController{
+		webhookUseCase: webhookUseCase,
+	}
+}
+
+func (u *WebhookController) CreateWebhook(c *gin.Context) {
+	var webhookInputDTO webhook_usecase.WebhookInputDTO
+
+	if err := c.ShouldBindJSON(&webhookInputDTO); err != nil {
+		restErr := validation.ValidateErr(err)
+
+		c.JSON(restErr.Code, restErr)
+		return
+	}
+
+	webhookData, err := u.webhookUseCase.CreateWebhook(context.Background(), webhookInputDTO)
+	if err != nil {
+		restErr := rest_err.ConvertError(err)
+
+		c.JSON(restErr.Code, restErr)
+		return
+	}
+
+	c.JSON(http.StatusCreated, webhookData)
+}
+
+func (u *WebhookController) DeleteWebhook(c *gin.Context) {
+	webhookId, ok := validateWebhookId(c)
+	if !ok {
+		return
+	}
+
+	if err := u.webhookUseCase.DeleteWebhook(context.Background(), webhookId); err != nil {
+		errRest := rest_err.ConvertError(err)
+		c.JSON(errRest.Code, errRest)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func validateWebhookId(c *gin.Context) (string, bool) {
+	webhookId := c.Param("webhookId")
+
+	if err := uuid.Validate(webhookId); err != nil {
+		errRest := rest_err.NewBadRequestError("Invalid fields", rest_err.Causes{
+			Field:   "webhookId",
+			Message: "Invalid UUID value",
+		})
+
+		c.JSON(errRest.Code, errRest)
+		return "", false
+	}
+
+	return webhookId, true
+}
@@ -0,0 +1,52 @@
+package webhook_controller
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/configuration/rest_err"
+	"github.com/gin-gonic/gin"
+)
+
+func (u *WebhookController) FindWebhooks(c *gin.Context) {
+	webhooks, err := u.webhookUseCase.FindWebhooks(context.Background())
+	if err != nil {
+		errRest := rest_err.ConvertError(err)
+		c.JSON(errRest.Code, errRest)
+		return
+	}
+
+	c.JSON(http.StatusOK, webhooks)
+}
+
+func (u *WebhookController) FindWebhookById(c *gin.Context) {
+	webhookId, ok := validateWebhookId(c)
+	if !ok {
+		return
+	}
+
+	webhookData, err := u.webhookUseCase.FindWebhookById(context.Background(), webhookId)
+	if err != nil {
+		errRest := rest_err.ConvertError(err)
+		c.JSON(errRest.Code, errRest)
+		return
+	}
+
+	c.JSON(http.StatusOK, webhookData)
+}
+
+func (u *WebhookController) FindDeliveriesByWebhookId(c *gin.Context) {
+	webhookId, ok := validateWebhookId(c)
+	if !ok {
+		return
+	}
+
+	deliveries, err := u.webhookUseCase.FindDeliveriesByWebhookId(context.Background(), webhookId)
+	if err != nil {
+		errRest := rest_err.ConvertError(err)
+		c.JSON(errRest.Code, errRest)
+		return
+	}
+
+	c.JSON(http.StatusOK, deliveries)
+}
@@ -0,0 +1,118 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/entity/api_key_entity"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/internal_error"
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+type apiKeyRepositoryMock struct {
+	api_key_entity.ApiKeyRepositoryInterface
+
+	apiKeys map[string]api_key_entity.ApiKey
+}
+
+func (m *apiKeyRepositoryMock) FindApiKeyByHash(
+	ctx context.Context, keyHash string) (*api_key_entity.ApiKey, *internal_error.InternalError) {
+	apiKey, ok := m.apiKeys[keyHash]
+	if !ok {
+		return nil, internal_error.NewNotFoundError("Api key not found")
+	}
+	return &apiKey, nil
+}
+
+func TestAuthenticateApiKeyScopes(t *testing.T) {
+	reader, readerKey, _ := api_key_entity.IssueApiKey(
+		"reader", uuid.New().String(), []string{api_key_entity.ScopeAuctionRead})
+	revoked, revokedKey, _ := api_key_entity.IssueApiKey(
+		"revoked", uuid.New().String(), []string{api_key_entity.ScopeAuctionWrite})
+	revoked.RevokedAt = time.Now()
+
+	repository := &apiKeyRepositoryMock{apiKeys: map[string]api_key_entity.ApiKey{
+		reader.KeyHash:  *reader,
+		revoked.KeyHash: *revoked,
+	}}
+	authenticator, err := NewAuthenticator(AuthConfig{HMACSecret: testSecret, ApiKeys: repository})
+	assert.Nil(t, err)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/results", authenticator.Authenticate(), RequireScopes(api_key_entity.ScopeAuctionRead),
+		func(c *gin.Context) {
+			identity, _ := GetIdentity(c)
+			c.String(http.StatusOK, identity.UserId)
+		})
+	router.POST("/auction", authenticator.Authenticate(), RequireScopes(api_key_entity.ScopeAuctionWrite),
+		func(c *gin.Context) { c.Status(http.StatusCreated) })
+
+	send := func(method, path, header, value string) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		req := httptest.NewRequest(method, path, nil)
+		req.Header.Set(header, value)
+		router.ServeHTTP(recorder, req)
+		return recorder
+	}
+
+	response := send(http.MethodGet, "/results", "X-API-Key", readerKey)
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.Equal(t, reader.UserId, response.Body.String())
+
+	assert.Equal(t, http.StatusForbidden, send(http.MethodPost, "/auction", "X-API-Key", readerKey).Code)
+	assert.Equal(t, http.StatusUnauthorized, send(http.MethodPost, "/auction", "X-API-Key", revokedKey).Code)
+	assert.Equal(t, http.StatusUnauthorized, send(http.MethodGet, "/results", "X-API-Key", "ak_unknown").Code)
+
+	// Papéis do JWT viram escopos
+	seller := signedToken(t, jwt.SigningMethodHS256, testSecret, "", validClaims(RoleSeller))
+	bidder := signedToken(t, jwt.SigningMethodHS256, testSecret, "", validClaims(RoleBidder))
+	assert.Equal(t, http.StatusCreated, send(http.MethodPost, "/auction", "Authorization", "Bearer "+seller).Code)
+	assert.Equal(t, http.StatusForbidden, send(http.MethodPost, "/auction", "Authorization", "Bearer "+bidder).Code)
+	assert.Equal(t, http.StatusOK, send(http.MethodGet, "/results", "Authorization", "Bearer "+bidder).Code)
+}
+
+func TestReadRoutesRequireScopeWhenAuthenticated(t *testing.T) {
+	reader, readerKey, _ := api_key_entity.IssueApiKey(
+		"reader", uuid.New().String(), []string{api_key_entity.ScopeAuctionRead})
+	writer, writerKey, _ := api_key_entity.IssueApiKey(
+		"writer", uuid.New().String(), []string{api_key_entity.ScopeBidWrite})
+
+	repository := &apiKeyRepositoryMock{apiKeys: map[string]api_key_entity.ApiKey{
+		reader.KeyHash: *reader,
+		writer.KeyHash: *writer,
+	}}
+	authenticator, err := NewAuthenticator(AuthConfig{HMACSecret: testSecret, ApiKeys: repository})
+	assert.Nil(t, err)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/auction", authenticator.AuthenticateOptional(),
+		RequireScopesIfAuthenticated(api_key_entity.ScopeAuctionRead),
+		func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	send := func(header, value string) int {
+		recorder := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/auction", nil)
+		if header != "" {
+			req.Header.Set(header, value)
+		}
+		router.ServeHTTP(recorder, req)
+		return recorder.Code
+	}
+
+	assert.Equal(t, http.StatusOK, send("", ""))
+	assert.Equal(t, http.StatusOK, send("X-API-Key", readerKey))
+	assert.Equal(t, http.StatusForbidden, send("X-API-Key", writerKey))
+	assert.Equal(t, http.StatusUnauthorized, send("X-API-Key", "ak_unknown"))
+	assert.Equal(t, http.StatusUnauthorized, send("Authorization", "Bearer invalid"))
+
+	bidder := signedToken(t, jwt.SigningMethodHS256, testSecret, "", validClaims(RoleBidder))
+	assert.Equal(t, http.StatusOK, send("Authorization", "Bearer "+bidder))
+}
@@ -0,0 +1,333 @@
+package middleware
+
+import (
+	"context"
+	"crypto/rsa"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/configuration/logger"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/configuration/rest_err"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/entity/api_key_entity"
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"go.uber.org/zap"
+)
+
+const (
+	RoleAdmin  = "admin"
+	RoleSeller = "seller"
+	RoleBidder = "bidder"
+)
+
+const (
+	identityKey  = "identity"
+	apiKeyHeader = "X-API-Key"
+)
+
+// Os papéis do JWT equivalem aos escopos das chaves de API
+var roleScopes = map[string][]string{
+	RoleAdmin:  {api_key_entity.ScopeAuctionRead, api_key_entity.ScopeAuctionWrite},
+	RoleSeller: {api_key_entity.ScopeAuctionRead, api_key_entity.ScopeAuctionWrite},
+	RoleBidder: {api_key_entity.ScopeAuctionRead, api_key_entity.ScopeBidWrite},
+}
+
+type identityContextKey struct{}
+
+type Identity struct {
+	UserId   string
+	Roles    []string
+	Scopes   []string
+	ApiKeyId string
+}
+
+func (i *Identity) HasRole(role string) bool {
+	for _, current := range i.Roles {
+		if current == role {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (i *Identity) HasScope(scope string) bool {
+	for _, current := range i.Scopes {
+		if current == scope {
+			return true
+		}
+	}
+
+	return false
+}
+
+type Claims struct {
+	Roles []string `json:"roles"`
+	jwt.RegisteredClaims
+}
+
+type AuthConfig struct {
+	HMACSecret []byte
+	RSAKeys    map[string]*rsa.PublicKey
+	Issuer     string
+	Audience   string
+	ApiKeys    api_key_entity.ApiKeyRepositoryInterface
+}
+
+type Authenticator struct {
+	config AuthConfig
+	parser *jwt.Parser
+}
+
+// minHMACSecretLength é o tamanho mínimo do segredo HS256 (256 bits)
+const minHMACSecretLength = 32
+
+func NewAuthenticator(config AuthConfig) (*Authenticator, error) {
+	var methods []string
+	if len(config.HMACSecret) > 0 {
+		if len(config.HMACSecret) < minHMACSecretLength {
+			return nil, errors.New("JWT_HS256_SECRET must have at least 32 bytes")
+		}
+		methods = append(methods, jwt.SigningMethodHS256.Alg())
+	}
+	if len(config.RSAKeys) > 0 {
+		methods = append(methods, jwt.SigningMethodRS256.Alg())
+	}
+
+	if len(methods) == 0 {
+		return nil, errors.New("configure JWT_HS256_SECRET or JWT_JWKS_FILE")
+	}
+
+	options := []jwt.ParserOption{
+		jwt.WithValidMethods(methods),
+		jwt.WithExpirationRequired(),
+	}
+	if config.Issuer != "" {
+		options = append(options, jwt.WithIssuer(config.Issuer))
+	}
+	if config.Audience != "" {
+		options = append(options, jwt.WithAudience(config.Audience))
+	}
+
+	return &Authenticator{
+		config: config,
+		parser: jwt.NewParser(options...),
+	}, nil
+}
+
+// NewAuthenticatorFromEnv aceita HS256 (JWT_HS256_SECRET), RS256 (JWT_JWKS_FILE)
+// ou ambos, além das chaves de API do repositório informado
+func NewAuthenticatorFromEnv(apiKeys api_key_entity.ApiKeyRepositoryInterface) (*Authenticator, error) {
+	config := AuthConfig{
+		HMACSecret: []byte(os.Getenv("JWT_HS256_SECRET")),
+		Issuer:     os.Getenv("JWT_ISSUER"),
+		Audience:   os.Getenv("JWT_AUDIENCE"),
+		ApiKeys:    apiKeys,
+	}
+
+	if jwksFile := os.Getenv("JWT_JWKS_FILE"); jwksFile != "" {
+		keys, err := LoadJWKSFile(jwksFile)
+		if err != nil {
+			return nil, err
+		}
+		config.RSAKeys = keys
+	}
+
+	return NewAuthenticator(config)
+}
+
+// Authenticate exige um bearer token ou uma chave de API (X-API-Key) válidos e
+// guarda a identidade do chamador no contexto do Gin e no da requisição.
+func (a *Authenticator) Authenticate() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		a.authenticate(c)
+	}
+}
+
+// AuthenticateOptional libera chamadas anônimas; quem envia credenciais passa
+// pela mesma validação de Authenticate.
+func (a *Authenticator) AuthenticateOptional() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.GetHeader(apiKeyHeader) == "" && c.GetHeader("Authorization") == "" {
+			c.Next()
+			return
+		}
+
+		a.authenticate(c)
+	}
+}
+
+func (a *Authenticator) authenticate(c *gin.Context) {
+	var identity *Identity
+	var err error
+	if apiKey := c.GetHeader(apiKeyHeader); apiKey != "" {
+		identity, err = a.identifyApiKey(c.Request.Context(), apiKey)
+	} else {
+		identity, err = a.identify(c.GetHeader("Authorization"))
+	}
+	if err != nil {
+		logger.Info("Request rejected by authentication", zap.String("reason", err.Error()))
+
+		restErr := rest_err.NewUnauthorizedError("Invalid or missing access token")
+		c.AbortWithStatusJSON(restErr.Code, restErr)
+		return
+	}
+
+	SetIdentity(c, identity)
+	c.Next()
+}
+
+// RequireRoles deve vir depois de Authenticate e libera qualquer um dos papéis informados
+func RequireRoles(roles ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		identity, ok := GetIdentity(c)
+		if !ok {
+			restErr := rest_err.NewUnauthorizedError("Invalid or missing access token")
+			c.AbortWithStatusJSON(restErr.Code, restErr)
+			return
+		}
+
+		for _, role := range roles {
+			if identity.HasRole(role) {
+				c.Next()
+				return
+			}
+		}
+
+		restErr := rest_err.NewForbiddenError(
+			fmt.Sprintf("This operation requires one of the roles: %s", strings.Join(roles, ", ")))
+		c.AbortWithStatusJSON(restErr.Code, restErr)
+	}
+}
+
+// RequireScopes deve vir depois de Authenticate e exige todos os escopos informados
+func RequireScopes(scopes ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		identity, ok := GetIdentity(c)
+		if !ok {
+			restErr := rest_err.NewUnauthorizedError("Invalid or missing access token")
+			c.AbortWithStatusJSON(restErr.Code, restErr)
+			return
+		}
+
+		for _, scope := range scopes {
+			if !identity.HasScope(scope) {
+				restErr := rest_err.NewForbiddenError(
+					fmt.Sprintf("This operation requires the scope %s", scope))
+				c.AbortWithStatusJSON(restErr.Code, restErr)
+				return
+			}
+		}
+
+		c.Next()
+	}
+}
+
+// RequireScopesIfAuthenticated deve vir depois de AuthenticateOptional: chamadas
+// anônimas passam, e as autenticadas precisam de todos os escopos informados
+func RequireScopesIfAuthenticated(scopes ...string) gin.HandlerFunc {
+	requireScopes := RequireScopes(scopes...)
+	return func(c *gin.Context) {
+		if _, ok := GetIdentity(c); !ok {
+			c.Next()
+			return
+		}
+
+		requireScopes(c)
+	}
+}
+
+func SetIdentity(c *gin.Context, identity *Identity) {
+	c.Set(identityKey, identity)
+	c.Request = c.Request.WithContext(context.WithValue(c.Request.Context(), identityContextKey{}, identity))
+}
+
+func GetIdentity(c *gin.Context) (*Identity, bool) {
+	value, ok := c.Get(identityKey)
+	if !ok {
+		return nil, false
+	}
+
+	identity, ok := value.(*Identity)
+	return identity, ok
+}
+
+func IdentityFromContext(ctx context.Context) (*Identity, bool) {
+	identity, ok := ctx.Value(identityContextKey{}).(*Identity)
+	return identity, ok
+}
+
+func (a *Authenticator) identify(authorization string) (*Identity, error) {
+	tokenString, found := strings.CutPrefix(authorization, "Bearer ")
+	if !found || tokenString == "" {
+		return nil, errors.New("missing bearer token")
+	}
+
+	claims := &Claims{}
+	if _, err := a.parser.ParseWithClaims(tokenString, claims, a.key); err != nil {
+		return nil, err
+	}
+
+	if claims.Subject == "" {
+		return nil, errors.New("token has no subject")
+	}
+
+	return &Identity{
+		UserId: claims.Subject,
+		Roles:  claims.Roles,
+		Scopes: scopesForRoles(claims.Roles),
+	}, nil
+}
+
+func (a *Authenticator) identifyApiKey(ctx context.Context, plainKey string) (*Identity, error) {
+	if a.config.ApiKeys == nil {
+		return nil, errors.New("api keys are not enabled")
+	}
+
+	apiKey, err := a.config.ApiKeys.FindApiKeyByHash(ctx, api_key_entity.HashApiKey(plainKey))
+	if err != nil {
+		return nil, err
+	}
+
+	if apiKey.Revoked() {
+		return nil, errors.New("api key was revoked")
+	}
+
+	return &Identity{
+		UserId:   apiKey.UserId,
+		Scopes:   apiKey.Scopes,
+		ApiKeyId: apiKey.Id,
+	}, nil
+}
+
+func scopesForRoles(roles []string) []string {
+	var scopes []string
+	seen := make(map[string]bool)
+	for _, role := range roles {
+		for _, scope := range roleScopes[role] {
+			if !seen[scope] {
+				seen[scope] = true
+				scopes = append(scopes, scope)
+			}
+		}
+	}
+
+	return scopes
+}
+
+func (a *Authenticator) key(token *jwt.Token) (interface{}, error) {
+	switch token.Method.Alg() {
+	case jwt.SigningMethodHS256.Alg():
+		return a.config.HMACSecret, nil
+	case jwt.SigningMethodRS256.Alg():
+		kid, _ := token.Header["kid"].(string)
+		if key, ok := a.config.RSAKeys[kid]; ok {
+			return key, nil
+		}
+		return nil, fmt.Errorf("unknown signing key %q", kid)
+	default:
+		return nil, fmt.Errorf("unexpected signing method %s", token.Method.Alg())
+	}
+}
@@ -0,0 +1,150 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+var testSecret = []byte("0123456789abcdef0123456789abcdef")
+
+func newTestRouter(authenticator *Authenticator) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/me", authenticator.Authenticate(), func(c *gin.Context) {
+		identity, _ := GetIdentity(c)
+		fromContext, _ := IdentityFromContext(c.Request.Context())
+		c.JSON(http.StatusOK, gin.H{"user_id": identity.UserId, "context_user_id": fromContext.UserId})
+	})
+	router.GET("/admin", authenticator.Authenticate(), RequireRoles(RoleAdmin), func(c *gin.Context) {
+		c.Status(http.StatusNoContent)
+	})
+	return router
+}
+
+func signedToken(t *testing.T, method jwt.SigningMethod, key interface{}, kid string, claims Claims) string {
+	token := jwt.NewWithClaims(method, claims)
+	if kid != "" {
+		token.Header["kid"] = kid
+	}
+
+	signed, err := token.SignedString(key)
+	assert.Nil(t, err)
+	return signed
+}
+
+func validClaims(roles ...string) Claims {
+	return Claims{
+		Roles: roles,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   "3b2f9d4e-5a39-4b7e-8d6f-2c1b0a9e8d7c",
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+		},
+	}
+}
+
+func request(router *gin.Engine, path, token string) *httptest.ResponseRecorder {
+	recorder := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	router.ServeHTTP(recorder, req)
+	return recorder
+}
+
+func TestAuthenticateHS256(t *testing.T) {
+	authenticator, err := NewAuthenticator(AuthConfig{HMACSecret: testSecret})
+	assert.Nil(t, err)
+	router := newTestRouter(authenticator)
+
+	t.Run("valid token injects identity", func(t *testing.T) {
+		token := signedToken(t, jwt.SigningMethodHS256, testSecret, "", validClaims(RoleBidder))
+		response := request(router, "/me", token)
+
+		assert.Equal(t, http.StatusOK, response.Code)
+		assert.JSONEq(t, `{
+			"user_id": "3b2f9d4e-5a39-4b7e-8d6f-2c1b0a9e8d7c",
+			"context_user_id": "3b2f9d4e-5a39-4b7e-8d6f-2c1b0a9e8d7c"
+		}`, response.Body.String())
+	})
+
+	t.Run("missing, expired or forged tokens are rejected", func(t *testing.T) {
+		expired := validClaims(RoleBidder)
+		expired.ExpiresAt = jwt.NewNumericDate(time.Now().Add(-time.Minute))
+
+		noExpiration := validClaims(RoleBidder)
+		noExpiration.ExpiresAt = nil
+
+		tokens := []string{
+			"",
+			signedToken(t, jwt.SigningMethodHS256, testSecret, "", expired),
+			signedToken(t, jwt.SigningMethodHS256, testSecret, "", noExpiration),
+			signedToken(t, jwt.SigningMethodHS256, []byte("another-secret-another-secret-00"), "", validClaims()),
+		}
+		for _, token := range tokens {
+			assert.Equal(t, http.StatusUnauthorized, request(router, "/me", token).Code)
+		}
+	})
+
+	t.Run("role guard", func(t *testing.T) {
+		bidder := signedToken(t, jwt.SigningMethodHS256, testSecret, "", validClaims(RoleBidder))
+		admin := signedToken(t, jwt.SigningMethodHS256, testSecret, "", validClaims(RoleBidder, RoleAdmin))
+
+		assert.Equal(t, http.StatusForbidden, request(router, "/admin", bidder).Code)
+		assert.Equal(t, http.StatusNoContent, request(router, "/admin", admin).Code)
+	})
+}
+
+func TestAuthenticateRS256WithJWKSFile(t *testing.T) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.Nil(t, err)
+
+	jwks, _ := json.Marshal(map[string]interface{}{
+		"keys": []map[string]string{{
+			"kty": "RSA",
+			"kid": "main",
+			"use": "sig",
+			"n":   base64.RawURLEncoding.EncodeToString(privateKey.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(privateKey.E)).Bytes()),
+		}},
+	})
+	jwksFile := filepath.Join(t.TempDir(), "jwks.json")
+	assert.Nil(t, os.WriteFile(jwksFile, jwks, 0o600))
+
+	t.Setenv("JWT_HS256_SECRET", "")
+	t.Setenv("JWT_JWKS_FILE", jwksFile)
+	authenticator, err := NewAuthenticatorFromEnv(nil)
+	assert.Nil(t, err)
+	router := newTestRouter(authenticator)
+
+	token := signedToken(t, jwt.SigningMethodRS256, privateKey, "main", validClaims(RoleSeller))
+	assert.Equal(t, http.StatusOK, request(router, "/me", token).Code)
+
+	unknownKid := signedToken(t, jwt.SigningMethodRS256, privateKey, "rotated", validClaims(RoleSeller))
+	assert.Equal(t, http.StatusUnauthorized, request(router, "/me", unknownKid).Code)
+
+	// HS256 não é aceito quando só o JWKS está configurado
+	hmacToken := signedToken(t, jwt.SigningMethodHS256, testSecret, "", validClaims(RoleSeller))
+	assert.Equal(t, http.StatusUnauthorized, request(router, "/me", hmacToken).Code)
+}
+
+func TestNewAuthenticatorRequiresKeys(t *testing.T) {
+	_, err := NewAuthenticator(AuthConfig{})
+	assert.NotNil(t, err)
+
+	_, err = NewAuthenticator(AuthConfig{HMACSecret: []byte("short-secret")})
+	assert.NotNil(t, err)
+}
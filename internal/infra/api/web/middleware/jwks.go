@@ -0,0 +1,77 @@
+package middleware
+
+import (
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+)
+
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+}
+
+type jsonWebKeySet struct {
+	Keys []jsonWebKey `json:"keys"`
+}
+
+// LoadJWKSFile lê as chaves públicas RSA de um arquivo JWKS, indexadas pelo kid
+func LoadJWKSFile(path string) (map[string]*rsa.PublicKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var keySet jsonWebKeySet
+	if err := json.Unmarshal(data, &keySet); err != nil {
+		return nil, fmt.Errorf("invalid JWKS file: %w", err)
+	}
+
+	keys := make(map[string]*rsa.PublicKey)
+	for _, key := range keySet.Keys {
+		if key.Kty != "RSA" || (key.Use != "" && key.Use != "sig") {
+			continue
+		}
+
+		publicKey, err := key.rsaPublicKey()
+		if err != nil {
+			return nil, fmt.Errorf("invalid JWKS key %q: %w", key.Kid, err)
+		}
+		keys[key.Kid] = publicKey
+	}
+
+	if len(keys) == 0 {
+		return nil, errors.New("JWKS file has no RSA signing keys")
+	}
+
+	return keys, nil
+}
+
+func (k jsonWebKey) rsaPublicKey() (*rsa.PublicKey, error) {
+	modulus, err := base64.RawURLEncoding.DecodeString(k.N)
+	if err != nil {
+		return nil, err
+	}
+
+	exponent, err := base64.RawURLEncoding.DecodeString(k.E)
+	if err != nil {
+		return nil, err
+	}
+
+	e := new(big.Int).SetBytes(exponent)
+	if !e.IsInt64() || e.Int64() < 3 {
+		return nil, errors.New("invalid exponent")
+	}
+
+	return &rsa.PublicKey{
+		N: new(big.Int).SetBytes(modulus),
+		E: int(e.Int64()),
+	}, nil
+}
@@ -0,0 +1,214 @@
+package api_key
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/configuration/logger"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/entity/api_key_entity"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/internal_error"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type ApiKeyEntityMongo struct {
+	Id        string   `bson:"_id"`
+	Name      string   `bson:"name"`
+	UserId    string   `bson:"user_id"`
+	Prefix    string   `bson:"prefix"`
+	KeyHash   string   `bson:"key_hash"`
+	Scopes    []string `bson:"scopes"`
+	CreatedAt int64    `bson:"created_at"`
+	RevokedAt int64    `bson:"revoked_at,omitempty"`
+}
+
+type cachedApiKey struct {
+	apiKey    api_key_entity.ApiKey
+	expiresAt time.Time
+}
+
+// ApiKeyRepository mantém as chaves consultadas em cache pelo hash, já que
+// cada requisição autenticada por chave a procura. A revogação feita por este
+// repositório remove a chave do cache; as demais são percebidas quando a entrada expira.
+type ApiKeyRepository struct {
+	Collection *mongo.Collection
+
+	apiKeyCache      map[string]cachedApiKey
+	apiKeyCacheMutex *sync.Mutex
+	cacheTTL         time.Duration
+}
+
+func NewApiKeyRepository(database *mongo.Database) *ApiKeyRepository {
+	return &ApiKeyRepository{
+		Collection:       database.Collection("api_keys"),
+		apiKeyCache:      make(map[string]cachedApiKey),
+		apiKeyCacheMutex: &sync.Mutex{},
+		cacheTTL:         getApiKeyCacheTTL(),
+	}
+}
+
+func (ar *ApiKeyRepository) CreateIndexes(ctx context.Context) error {
+	_, err := ar.Collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "key_hash", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+
+	return err
+}
+
+func (ar *ApiKeyRepository) CreateApiKey(
+	ctx context.Context, apiKey *api_key_entity.ApiKey) *internal_error.InternalError {
+	apiKeyMongo := &ApiKeyEntityMongo{
+		Id:        apiKey.Id,
+		Name:      apiKey.Name,
+		UserId:    apiKey.UserId,
+		Prefix:    apiKey.Prefix,
+		KeyHash:   apiKey.KeyHash,
+		Scopes:    apiKey.Scopes,
+		CreatedAt: apiKey.CreatedAt.Unix(),
+	}
+
+	if _, err := ar.Collection.InsertOne(ctx, apiKeyMongo); err != nil {
+		logger.Error("Error trying to insert api key", err)
+		return internal_error.NewInternalServerError("Error trying to insert api key")
+	}
+
+	return nil
+}
+
+func (ar *ApiKeyRepository) FindApiKeys(
+	ctx context.Context) ([]api_key_entity.ApiKey, *internal_error.InternalError) {
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}})
+	cursor, err := ar.Collection.Find(ctx, bson.M{}, opts)
+	if err != nil {
+		logger.Error("Error trying to find api keys", err)
+		return nil, internal_error.NewInternalServerError("Error trying to find api keys")
+	}
+	defer cursor.Close(ctx)
+
+	var apiKeysMongo []ApiKeyEntityMongo
+	if err := cursor.All(ctx, &apiKeysMongo); err != nil {
+		logger.Error("Error trying to decode api keys", err)
+		return nil, internal_error.NewInternalServerError("Error trying to decode api keys")
+	}
+
+	apiKeys := make([]api_key_entity.ApiKey, 0, len(apiKeysMongo))
+	for _, apiKeyMongo := range apiKeysMongo {
+		apiKeys = append(apiKeys, apiKeyMongo.toEntity())
+	}
+
+	return apiKeys, nil
+}
+
+func (ar *ApiKeyRepository) FindApiKeyByHash(
+	ctx context.Context, keyHash string) (*api_key_entity.ApiKey, *internal_error.InternalError) {
+	if apiKey, ok := ar.cachedApiKey(keyHash); ok {
+		return apiKey, nil
+	}
+
+	var apiKeyMongo ApiKeyEntityMongo
+	if err := ar.Collection.FindOne(ctx, bson.M{"key_hash": keyHash}).Decode(&apiKeyMongo); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, internal_error.NewNotFoundError("Api key not found")
+		}
+
+		logger.Error("Error trying to find api key", err)
+		return nil, internal_error.NewInternalServerError("Error trying to find api key")
+	}
+
+	apiKey := apiKeyMongo.toEntity()
+	ar.cacheApiKey(apiKey)
+
+	return &apiKey, nil
+}
+
+func (ar *ApiKeyRepository) RevokeApiKey(ctx context.Context, id string) *internal_error.InternalError {
+	filter := bson.M{"_id": id, "revoked_at": bson.M{"$exists": false}}
+	update := bson.M{"$set": bson.M{"revoked_at": time.Now().Unix()}}
+
+	result, err := ar.Collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		logger.Error("Error trying to revoke api key", err)
+		return internal_error.NewInternalServerError("Error trying to revoke api key")
+	}
+
+	if result.MatchedCount == 0 {
+		return internal_error.NewNotFoundError(
+			fmt.Sprintf("Active api key not found with this id = %s", id))
+	}
+
+	ar.evictApiKey(id)
+
+	return nil
+}
+
+func (am *ApiKeyEntityMongo) toEntity() api_key_entity.ApiKey {
+	apiKey := api_key_entity.ApiKey{
+		Id:        am.Id,
+		Name:      am.Name,
+		UserId:    am.UserId,
+		Prefix:    am.Prefix,
+		KeyHash:   am.KeyHash,
+		Scopes:    am.Scopes,
+		CreatedAt: time.Unix(am.CreatedAt, 0),
+	}
+	if am.RevokedAt > 0 {
+		apiKey.RevokedAt = time.Unix(am.RevokedAt, 0)
+	}
+
+	return apiKey
+}
+
+func (ar *ApiKeyRepository) cachedApiKey(keyHash string) (*api_key_entity.ApiKey, bool) {
+	ar.apiKeyCacheMutex.Lock()
+	defer ar.apiKeyCacheMutex.Unlock()
+
+	cached, ok := ar.apiKeyCache[keyHash]
+	if !ok || time.Now().After(cached.expiresAt) {
+		delete(ar.apiKeyCache, keyHash)
+		return nil, false
+	}
+
+	apiKey := cached.apiKey
+	return &apiKey, true
+}
+
+func (ar *ApiKeyRepository) cacheApiKey(apiKey api_key_entity.ApiKey) {
+	if ar.cacheTTL <= 0 {
+		return
+	}
+
+	ar.apiKeyCacheMutex.Lock()
+	defer ar.apiKeyCacheMutex.Unlock()
+
+	ar.apiKeyCache[apiKey.KeyHash] = cachedApiKey{
+		apiKey:    apiKey,
+		expiresAt: time.Now().Add(ar.cacheTTL),
+	}
+}
+
+func (ar *ApiKeyRepository) evictApiKey(id string) {
+	ar.apiKeyCacheMutex.Lock()
+	defer ar.apiKeyCacheMutex.Unlock()
+
+	for keyHash, cached := range ar.apiKeyCache {
+		if cached.apiKey.Id == id {
+			delete(ar.apiKeyCache, keyHash)
+		}
+	}
+}
+
+func getApiKeyCacheTTL() time.Duration {
+	cacheTTL := os.Getenv("API_KEY_CACHE_TTL")
+	duration, err := time.ParseDuration(cacheTTL)
+	if err != nil {
+		return 30 * time.Second
+	}
+
+	return duration
+}
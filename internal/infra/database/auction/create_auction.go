@@ -6,8 +6,12 @@ import (
 	"sync"
 	"time"
 
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/configuration/database/mongodb"
 	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/configuration/logger"
 	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/entity/auction_entity"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/entity/money_entity"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/infra/database/outbox"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/infra/scheduler"
 	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/internal_error"
 
 	"go.mongodb.org/mongo-driver/bson"
@@ -17,36 +21,119 @@ import (
 
 type AuctionEntityMongo struct {
 	Id          string                          `bson:"_id"`
+	SellerId    string                          `bson:"seller_id,omitempty"`
 	ProductName string                          `bson:"product_name"`
+	CategoryId  string                          `bson:"category_id,omitempty"`
 	Category    string                          `bson:"category"`
 	Description string                          `bson:"description"`
 	Condition   auction_entity.ProductCondition `bson:"condition"`
 	Status      auction_entity.AuctionStatus    `bson:"status"`
 	Timestamp   int64                           `bson:"timestamp"`
+	StartsAt    int64                           `bson:"starts_at,omitempty"`
+	EndTime     int64                           `bson:"end_time"`
+
+	// Valores em unidades menores da moeda; veja MigrateLegacyAmounts
+	Currency      string                       `bson:"currency"`
+	StartingPrice int64                        `bson:"starting_price"`
+	MinIncrement  int64                        `bson:"min_increment"`
+	IncrementType auction_entity.IncrementType `bson:"increment_type"`
+	ReservePrice  int64                        `bson:"reserve_price"`
+	BuyNowPrice   int64                        `bson:"buy_now_price,omitempty"`
+
+	Type          auction_entity.AuctionType `bson:"type,omitempty"`
+	PriceSchedule *PriceScheduleMongo        `bson:"price_schedule,omitempty"`
+
+	HighestBid int64 `bson:"highest_bid"`
+	BidCount   int64 `bson:"bid_count"`
+
+	Images []AuctionImageMongo `bson:"images,omitempty"`
+}
+
+// PriceScheduleMongo guarda a queda de preço dos leilões holandeses, com o
+// intervalo em segundos.
+type PriceScheduleMongo struct {
+	FloorPrice int64 `bson:"floor_price"`
+	Decrement  int64 `bson:"decrement"`
+	Interval   int64 `bson:"interval"`
 }
+
+type AuctionClosedHandler func(ctx context.Context, auctionId string)
+
+type SoldChecker func(ctx context.Context, auctionEntity *auction_entity.Auction) (bool, *internal_error.InternalError)
+
 type AuctionRepository struct {
 	Collection *mongo.Collection
+	Outbox     *outbox.OutboxRepository
+	Scheduler  *scheduler.Scheduler
 	mu         sync.RWMutex
+
+	closedHandlers []AuctionClosedHandler
+	soldChecker    SoldChecker
 }
 
 func NewAuctionRepository(database *mongo.Database) *AuctionRepository {
 	return &AuctionRepository{
 		Collection: database.Collection("auctions"),
+		Outbox:     outbox.NewOutboxRepository(database),
+		Scheduler:  scheduler.NewScheduler(context.Background()),
 		mu:         sync.RWMutex{},
 	}
 }
 
+// CreateIndexes cria os índices da listagem do vendedor, do filtro por
+// categoria, da busca textual e de cada ordenação de GET /auction, com e sem
+// o filtro de status.
+func (ar *AuctionRepository) CreateIndexes(ctx context.Context) *internal_error.InternalError {
+	indexes := []mongo.IndexModel{
+		{Keys: append(bson.D{{Key: "seller_id", Value: 1}}, auctionSorts[auction_entity.SortNewest].order()...)},
+		{Keys: bson.D{{Key: "category_id", Value: 1}, {Key: "status", Value: 1}}},
+		textIndex(),
+	}
+	for _, spec := range auctionSorts {
+		indexes = append(indexes,
+			mongo.IndexModel{Keys: spec.order()},
+			mongo.IndexModel{Keys: append(bson.D{{Key: "status", Value: 1}}, spec.order()...)},
+		)
+	}
+
+	_, err := ar.Collection.Indexes().CreateMany(ctx, indexes)
+	if err != nil {
+		logger.Error("Error trying to create auction indexes", err)
+		return internal_error.NewInternalServerError("Error trying to create auction indexes")
+	}
+
+	return nil
+}
+
 func (ar *AuctionRepository) CreateAuction(
 	ctx context.Context,
 	auctionEntity *auction_entity.Auction) *internal_error.InternalError {
+	if auctionEntity.EndTime.IsZero() {
+		auctionEntity.EndTime = auctionEntity.OpensAt().Add(getAuctionDuration())
+	}
+
 	auctionEntityMongo := &AuctionEntityMongo{
 		Id:          auctionEntity.Id,
+		SellerId:    auctionEntity.SellerId,
 		ProductName: auctionEntity.ProductName,
+		CategoryId:  auctionEntity.CategoryId,
 		Category:    auctionEntity.Category,
 		Description: auctionEntity.Description,
 		Condition:   auctionEntity.Condition,
 		Status:      auctionEntity.Status,
 		Timestamp:   auctionEntity.Timestamp.Unix(),
+		StartsAt:    unixOrZero(auctionEntity.StartsAt),
+		EndTime:     auctionEntity.EndTime.Unix(),
+
+		Currency:      auctionEntity.Currency,
+		StartingPrice: auctionEntity.StartingPrice.Units,
+		MinIncrement:  auctionEntity.MinIncrement,
+		IncrementType: auctionEntity.IncrementType,
+		ReservePrice:  auctionEntity.ReservePrice.Units,
+		BuyNowPrice:   auctionEntity.BuyNowPrice.Units,
+
+		Type:          auctionEntity.Type,
+		PriceSchedule: toPriceScheduleMongo(auctionEntity),
 	}
 	_, err := ar.Collection.InsertOne(ctx, auctionEntityMongo)
 	if err != nil {
@@ -54,8 +141,14 @@ func (ar *AuctionRepository) CreateAuction(
 		return internal_error.NewInternalServerError("Error trying to insert auction")
 	}
 
-	// Iniciar goroutine para fechamento automático
-	go ar.startAutoCloseRoutine(ctx, auctionEntity.Id, auctionEntity.Timestamp)
+	// Leilões agendados só passam a ter fechamento automático depois de abertos
+	if auctionEntity.Status == auction_entity.Scheduled {
+		ar.scheduleActivation(auctionEntity.Id, auctionEntity.StartsAt)
+		return nil
+	}
+
+	// Agendar fechamento automático
+	ar.scheduleAutoClose(auctionEntity.Id, auctionEntity.EndTime)
 
 	return nil
 }
@@ -65,16 +158,111 @@ func (ar *AuctionRepository) UpdateAuctionStatus(
 	id string,
 	status auction_entity.AuctionStatus) *internal_error.InternalError {
 
+	if !status.Valid() {
+		return internal_error.NewBadRequestError("Invalid auction status")
+	}
+
+	updated, err := ar.updateStatus(ctx, id, status, bson.M{}, nil)
+	if err != nil {
+		return err
+	}
+
+	if !updated {
+		current, err := ar.FindAuctionById(ctx, id)
+		if err != nil {
+			return err
+		}
+		return auction_entity.NewIllegalTransitionError(current.Status, status)
+	}
+
+	return nil
+}
+
+// CancelAuction cancela o leilão, gravando na mesma transação o que apply
+// gravar (a anulação dos lances). Com withoutBids, o cancelamento só vale se
+// nenhum lance foi gravado: a condição fica no próprio filtro, então um lance
+// gravado ao mesmo tempo não passa despercebido.
+func (ar *AuctionRepository) CancelAuction(
+	ctx context.Context,
+	id string,
+	withoutBids bool,
+	apply func(ctx context.Context) error) *internal_error.InternalError {
+	filter := bson.M{}
+	if withoutBids {
+		filter["bid_count"] = 0
+	}
+
+	updated, err := ar.updateStatus(ctx, id, auction_entity.Cancelled, filter, apply)
+	if err != nil {
+		return err
+	}
+
+	if !updated {
+		current, err := ar.FindAuctionById(ctx, id)
+		if err != nil {
+			return err
+		}
+		if withoutBids && current.BidCount > 0 && current.Status.CanTransitionTo(auction_entity.Cancelled) {
+			return internal_error.NewBadRequestError("Auction already has bids")
+		}
+		return auction_entity.NewIllegalTransitionError(current.Status, auction_entity.Cancelled)
+	}
+
+	return nil
+}
+
+// updateStatus aplica a transição se o status gravado puder levar ao novo
+// status e o documento atender às condições extras do filtro. O que apply
+// gravar entra na mesma transação.
+func (ar *AuctionRepository) updateStatus(
+	ctx context.Context,
+	id string,
+	status auction_entity.AuctionStatus,
+	filter bson.M,
+	apply func(ctx context.Context) error) (bool, *internal_error.InternalError) {
 	ar.mu.Lock()
 	defer ar.mu.Unlock()
 
-	filter := bson.M{"_id": id}
+	filter["_id"] = id
+	filter["status"] = bson.M{"$in": auction_entity.PreviousStatuses(status)}
 	update := bson.M{"$set": bson.M{"status": status}}
 
-	_, err := ar.Collection.UpdateOne(ctx, filter, update)
+	updated := false
+	err := mongodb.WithTransaction(ctx, ar.Collection.Database().Client(), func(ctx context.Context) error {
+		result, err := ar.Collection.UpdateOne(ctx, filter, update)
+		if err != nil {
+			return err
+		}
+
+		updated = result.ModifiedCount > 0
+		if !updated {
+			return nil
+		}
+
+		if apply != nil {
+			if err := apply(ctx); err != nil {
+				return err
+			}
+		}
+
+		if status != auction_entity.Completed && status != auction_entity.Unsold {
+			return nil
+		}
+
+		return ar.saveClosedMessage(ctx, id, status, time.Now())
+	})
 	if err != nil {
 		logger.Error("Error trying to update auction status", err)
-		return internal_error.NewInternalServerError("Error trying to update auction status")
+		return false, internal_error.NewInternalServerError("Error trying to update auction status")
+	}
+
+	if !updated {
+		return false, nil
+	}
+
+	if status.Final() {
+		ar.Scheduler.Cancel(id)
+		ar.Scheduler.Cancel(activationKey(id))
 	}
 
 	logger.Info("Auction status updated successfully",
@@ -82,37 +270,259 @@ func (ar *AuctionRepository) UpdateAuctionStatus(
 		zap.Int("status", int(status)),
 	)
 
-	return nil
+	return true, nil
 }
 
-func (ar *AuctionRepository) startAutoCloseRoutine(ctx context.Context, auctionId string, timestamp time.Time) {
-	// Calcular tempo de expiração
-	duration := getAuctionDuration()
-	expirationTime := timestamp.Add(duration)
+// RecoverActiveAuctions reagenda os leilões ativos e agendados que continuam
+// no banco, fechando imediatamente os que expiraram enquanto a aplicação
+// estava parada. Aberturas atrasadas são feitas na hora.
+func (ar *AuctionRepository) RecoverActiveAuctions(ctx context.Context) *internal_error.InternalError {
+	filter := bson.M{"status": bson.M{"$in": bson.A{auction_entity.Active, auction_entity.Scheduled}}}
+	cursor, err := ar.Collection.Find(ctx, filter)
+	if err != nil {
+		logger.Error("Error trying to find active auctions", err)
+		return internal_error.NewInternalServerError("Error trying to find active auctions")
+	}
+	defer cursor.Close(ctx)
 
-	// Ticker para checagens periódicas
-	checkInterval := getAuctionCheckInterval()
-	ticker := time.NewTicker(checkInterval)
-	defer ticker.Stop()
+	var auctionsMongo []AuctionEntityMongo
+	if err := cursor.All(ctx, &auctionsMongo); err != nil {
+		logger.Error("Error decoding active auctions", err)
+		return internal_error.NewInternalServerError("Error decoding active auctions")
+	}
 
-	logger.Info("Starting auto-close routine for auction",
+	now := time.Now()
+	closed := 0
+	scheduled := 0
+	for _, auctionMongo := range auctionsMongo {
+		if auctionMongo.Status == auction_entity.Scheduled {
+			ar.scheduleActivation(auctionMongo.Id, time.Unix(auctionMongo.StartsAt, 0))
+			scheduled++
+			continue
+		}
+
+		expirationTime := auctionMongo.endTime()
+		if expirationTime.After(now) {
+			ar.scheduleAutoClose(auctionMongo.Id, expirationTime)
+			continue
+		}
+
+		ar.closeExpiredAuction(ctx, auctionMongo.Id)
+		closed++
+	}
+
+	logger.Info("Active auctions recovered",
+		zap.Int("scheduled", len(auctionsMongo)-closed-scheduled),
+		zap.Int("closed", closed),
+		zap.Int("pending_start", scheduled),
+	)
+
+	return nil
+}
+
+func (ar *AuctionRepository) scheduleAutoClose(auctionId string, expirationTime time.Time) {
+	ar.Scheduler.Schedule(auctionId, expirationTime, func(ctx context.Context) {
+		ar.closeExpiredAuction(ctx, auctionId)
+	})
+
+	logger.Info("Auto-close scheduled for auction",
 		zap.String("auction_id", auctionId),
 		zap.Time("expiration_time", expirationTime),
-		zap.Duration("check_interval", checkInterval),
 	)
+}
 
-	for range ticker.C {
-		if time.Now().After(expirationTime) {
-			// Fechar leilão
-			if err := ar.UpdateAuctionStatus(ctx, auctionId, auction_entity.Completed); err != nil {
-				logger.Error("Error closing auction automatically", err)
-			} else {
-				logger.Info("Auction closed automatically",
-					zap.String("auction_id", auctionId),
-				)
-			}
-			return
+func (ar *AuctionRepository) closeExpiredAuction(ctx context.Context, auctionId string) {
+	now := time.Now()
+
+	auctionEntity, internalErr := ar.FindAuctionById(ctx, auctionId)
+	if internalErr != nil {
+		if internalErr.Err != "not_found" {
+			ar.scheduleAutoClose(auctionId, now.Add(getAuctionCheckInterval()))
+		}
+		return
+	}
+
+	// Sem saber se houve vencedor, o leilão continua aberto e a verificação
+	// é refeita após o intervalo
+	status, internalErr := ar.closingStatus(ctx, auctionEntity)
+	if internalErr != nil {
+		logger.Error("Error trying to check auction winner on close", internalErr,
+			zap.String("auction_id", auctionId))
+		ar.scheduleAutoClose(auctionId, now.Add(getAuctionCheckInterval()))
+		return
+	}
+
+	ar.mu.Lock()
+	// Só fecha se o término gravado já passou; prorrogações feitas depois do
+	// agendamento continuam valendo. O status foi decidido fora da trava, então
+	// um lance gravado depois da decisão impede o fechamento e ele é refeito
+	filter := bson.M{
+		"_id":       auctionId,
+		"status":    auction_entity.Active,
+		"bid_count": auctionEntity.BidCount,
+		"$or": bson.A{
+			bson.M{"end_time": bson.M{"$lte": now.Unix()}},
+			bson.M{"end_time": bson.M{"$exists": false}},
+		},
+	}
+	update := bson.M{"$set": bson.M{"status": status}}
+
+	// O aviso no outbox é gravado na mesma transação do fechamento
+	closed := false
+	err := mongodb.WithTransaction(ctx, ar.Collection.Database().Client(), func(ctx context.Context) error {
+		result, err := ar.Collection.UpdateOne(ctx, filter, update)
+		if err != nil {
+			return err
+		}
+
+		closed = result.ModifiedCount > 0
+		if !closed {
+			return nil
+		}
+
+		return ar.saveClosedMessage(ctx, auctionId, status, now)
+	})
+	ar.mu.Unlock()
+
+	if err != nil {
+		// Nova tentativa após o intervalo de verificação
+		logger.Error("Error closing auction automatically", err)
+		ar.scheduleAutoClose(auctionId, now.Add(getAuctionCheckInterval()))
+		return
+	}
+
+	if !closed {
+		ar.rescheduleAutoClose(ctx, auctionId)
+		return
+	}
+
+	logger.Info("Auction closed automatically",
+		zap.String("auction_id", auctionId),
+		zap.Stringer("status", status),
+	)
+
+	for _, handler := range ar.closedHandlers {
+		handler(ctx, auctionId)
+	}
+}
+
+// OnAuctionClosed registra quem deve ser avisado a cada fechamento automático.
+// Deve ser chamado durante a inicialização, antes de recuperar os leilões ativos.
+func (ar *AuctionRepository) OnAuctionClosed(handler AuctionClosedHandler) {
+	ar.closedHandlers = append(ar.closedHandlers, handler)
+}
+
+// CheckSold define como descobrir, no fechamento, se o leilão teve um vencedor.
+// Sem verificador, todo leilão encerrado é considerado concluído.
+func (ar *AuctionRepository) CheckSold(checker SoldChecker) {
+	ar.soldChecker = checker
+}
+
+func (ar *AuctionRepository) closingStatus(
+	ctx context.Context,
+	auctionEntity *auction_entity.Auction) (auction_entity.AuctionStatus, *internal_error.InternalError) {
+	if ar.soldChecker == nil {
+		return auction_entity.Completed, nil
+	}
+
+	sold, err := ar.soldChecker(ctx, auctionEntity)
+	if err != nil {
+		return 0, err
+	}
+
+	if !sold {
+		return auction_entity.Unsold, nil
+	}
+
+	return auction_entity.Completed, nil
+}
+
+func (ar *AuctionRepository) rescheduleAutoClose(ctx context.Context, auctionId string) {
+	auctionEntity, err := ar.FindAuctionById(ctx, auctionId)
+	if err != nil {
+		if err.Err != "not_found" {
+			ar.scheduleAutoClose(auctionId, time.Now().Add(getAuctionCheckInterval()))
 		}
+		return
+	}
+
+	if auctionEntity.Status == auction_entity.Active {
+		ar.scheduleAutoClose(auctionId, auctionEntity.EndTime)
+	}
+}
+
+// Leilões gravados antes do campo end_time usam a duração padrão
+func (am *AuctionEntityMongo) endTime() time.Time {
+	if am.EndTime == 0 {
+		return time.Unix(am.Timestamp, 0).Add(getAuctionDuration())
+	}
+
+	return time.Unix(am.EndTime, 0)
+}
+
+// Leilões gravados antes do campo currency estão em reais
+func (am *AuctionEntityMongo) currency() string {
+	if am.Currency == "" {
+		return money_entity.DefaultCurrency
+	}
+
+	return am.Currency
+}
+
+func toPriceScheduleMongo(auctionEntity *auction_entity.Auction) *PriceScheduleMongo {
+	if auctionEntity.Type != auction_entity.DutchAuction {
+		return nil
+	}
+
+	return &PriceScheduleMongo{
+		FloorPrice: auctionEntity.PriceSchedule.FloorPrice.Units,
+		Decrement:  auctionEntity.PriceSchedule.Decrement,
+		Interval:   int64(auctionEntity.PriceSchedule.Interval / time.Second),
+	}
+}
+
+func (am *AuctionEntityMongo) priceSchedule(currency string) auction_entity.PriceSchedule {
+	if am.PriceSchedule == nil {
+		return auction_entity.PriceSchedule{}
+	}
+
+	return auction_entity.PriceSchedule{
+		FloorPrice: money_entity.New(am.PriceSchedule.FloorPrice, currency),
+		Decrement:  am.PriceSchedule.Decrement,
+		Interval:   time.Duration(am.PriceSchedule.Interval) * time.Second,
+	}
+}
+
+func (am *AuctionEntityMongo) toEntity() *auction_entity.Auction {
+	currency := am.currency()
+
+	return &auction_entity.Auction{
+		Id:          am.Id,
+		SellerId:    am.SellerId,
+		ProductName: am.ProductName,
+		CategoryId:  am.CategoryId,
+		Category:    am.Category,
+		Description: am.Description,
+		Condition:   am.Condition,
+		Status:      am.Status,
+		Timestamp:   time.Unix(am.Timestamp, 0),
+		StartsAt:    timeOrZero(am.StartsAt),
+		EndTime:     am.endTime(),
+
+		Currency:      currency,
+		StartingPrice: money_entity.New(am.StartingPrice, currency),
+		MinIncrement:  am.MinIncrement,
+		IncrementType: am.IncrementType,
+		ReservePrice:  money_entity.New(am.ReservePrice, currency),
+		BuyNowPrice:   money_entity.New(am.BuyNowPrice, currency),
+
+		Type:          am.Type,
+		PriceSchedule: am.priceSchedule(currency),
+
+		HighestBidAmount: money_entity.New(am.HighestBid, currency),
+		BidCount:         am.BidCount,
+
+		Images: toAuctionImages(am.Images),
 	}
 }
 
@@ -8,11 +8,16 @@ import (
 	"time"
 
 	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/entity/auction_entity"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/entity/category_entity"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/infra/database/category"
 	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
+const testSellerId = "5a6f1b4e-8d7c-4f3a-9b2e-1c0d9e8f7a6b"
+
 // Configura o banco de teste
 func setupTestDatabase(t *testing.T) (*mongo.Database, func()) {
 	ctx := context.Background()
@@ -20,7 +25,7 @@ func setupTestDatabase(t *testing.T) (*mongo.Database, func()) {
 	// Configura as variáveis de ambiente para teste
 	mongoURL := os.Getenv("MONGODB_URL")
 	if mongoURL == "" {
-		mongoURL = "mongodb://localhost:27018" // Porta padrão para testes
+		mongoURL = "mongodb://localhost:27018/?directConnection=true" // Porta padrão para testes
 	}
 	os.Setenv("MONGODB_URL", mongoURL)
 	os.Setenv("MONGODB_DB", fmt.Sprintf("test_auctions_%d", time.Now().UnixNano()))
@@ -79,6 +84,7 @@ func TestAutoCloseIntegration(t *testing.T) {
 	t.Run("auction auto closes after duration", func(t *testing.T) {
 		// 1. Cria o leilão
 		auction, err := auction_entity.CreateAuction(
+			testSellerId,
 			"Test Product",
 			"Test Category",
 			"Test Description for Integration Test",
@@ -125,6 +131,7 @@ func TestAutoCloseIntegration(t *testing.T) {
 		auctions := make([]*auction_entity.Auction, 3)
 		for i := 0; i < 3; i++ {
 			auction, err := auction_entity.CreateAuction(
+				testSellerId,
 				fmt.Sprintf("Test Product %d", i+1),
 				"Test Category",
 				fmt.Sprintf("Test Description %d", i+1),
@@ -161,6 +168,7 @@ func TestAutoCloseIntegration(t *testing.T) {
 
 		// Cria o leilão
 		auction, err := auction_entity.CreateAuction(
+			testSellerId,
 			"Bid Test Product",
 			"Test Category",
 			"Test Description for Bid Validation",
@@ -293,6 +301,7 @@ func createConcurrentAuctions(t *testing.T, repo *AuctionRepository, ctx context
 	for i := 0; i < numAuctions; i++ {
 		go func(index int) {
 			auction, err := auction_entity.CreateAuction(
+				testSellerId,
 				fmt.Sprintf("Concurrent Product %d", index+1),
 				"Test Category",
 				fmt.Sprintf("Concurrent Description %d", index+1),
@@ -426,6 +435,7 @@ func TestAutoClosePerformance(t *testing.T) {
 		// Cria muitos leilões rapidamente
 		for i := 0; i < numAuctions; i++ {
 			auction, err := auction_entity.CreateAuction(
+				testSellerId,
 				fmt.Sprintf("Performance Product %d", i+1),
 				"Test Category",
 				fmt.Sprintf("Performance Description %d", i+1),
@@ -448,11 +458,16 @@ func TestAutoClosePerformance(t *testing.T) {
 		time.Sleep(2 * time.Second)
 
 		// Verifica se todos foram fechados
-		auctions, err := repo.FindAuctions(ctx, auction_entity.Completed, "", "")
+		completed := auction_entity.Completed
+		page, err := repo.FindAuctions(ctx, auction_entity.AuctionQuery{
+			Status: &completed,
+			Sort:   auction_entity.SortNewest,
+			Limit:  auction_entity.MaxPageLimit,
+		})
 		if err != nil {
 			t.Fatalf("Unexpected error: %v", err)
 		}
-		closedCount := len(auctions)
+		closedCount := len(page.Auctions)
 
 		t.Logf("✅ Performance test completed: %d auctions processed", closedCount)
 		assert.True(t, closedCount > 0, "At least some auctions should be closed")
@@ -484,6 +499,7 @@ func TestAutoCloseErrorHandling(t *testing.T) {
 
 		// Cria o leilão
 		auction, err := auction_entity.CreateAuction(
+			testSellerId,
 			"Error Test Product",
 			"Test Category",
 			"Test Description for Error Handling",
@@ -512,3 +528,321 @@ func TestAutoCloseErrorHandling(t *testing.T) {
 		t.Logf("✅ Error handling test: Auction created with default duration due to invalid env var")
 	})
 }
+
+// Testa a recuperação de leilões ativos após reinício da aplicação
+func TestRecoverActiveAuctionsIntegration(t *testing.T) {
+	// Pula se não estiver executando testes de integração
+	if testing.Short() {
+		t.Skip("Skipping integration test")
+	}
+
+	os.Setenv("AUCTION_DURATION", "1s")
+	defer os.Unsetenv("AUCTION_DURATION")
+
+	// Configura o banco de teste
+	database, cleanup := setupTestDatabase(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	// Simula leilões criados antes do reinício: um já expirado e um ainda em andamento
+	expired := AuctionEntityMongo{
+		Id:          "expired-auction",
+		ProductName: "Expired Product",
+		Category:    "Test Category",
+		Description: "Auction created before restart",
+		Condition:   auction_entity.New,
+		Status:      auction_entity.Active,
+		Timestamp:   time.Now().Add(-time.Hour).Unix(),
+	}
+	running := expired
+	running.Id = "running-auction"
+	running.Timestamp = time.Now().Unix()
+
+	if _, err := database.Collection("auctions").InsertMany(ctx, []interface{}{expired, running}); err != nil {
+		t.Fatalf("Failed to insert auctions: %v", err)
+	}
+
+	// Novo repositório representa a aplicação reiniciada
+	repo := NewAuctionRepository(database)
+	if err := repo.RecoverActiveAuctions(ctx); err != nil {
+		t.Fatalf("Failed to recover active auctions: %v", err)
+	}
+
+	foundExpired, err := repo.FindAuctionById(ctx, expired.Id)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	assert.Equal(t, auction_entity.Completed, foundExpired.Status, "Expired auction should be closed on recovery")
+
+	foundRunning, err := repo.FindAuctionById(ctx, running.Id)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	assert.Equal(t, auction_entity.Active, foundRunning.Status, "Running auction should stay Active")
+
+	time.Sleep(2 * time.Second)
+
+	foundRunning, err = repo.FindAuctionById(ctx, running.Id)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	assert.Equal(t, auction_entity.Completed, foundRunning.Status, "Recovered auction should be closed by the scheduler")
+}
+
+// Testa a paginação por chave percorrendo todas as páginas
+func TestFindAuctionsPaginationIntegration(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test")
+	}
+
+	database, cleanup := setupTestDatabase(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	repo := NewAuctionRepository(database)
+	if err := repo.CreateIndexes(ctx); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// Mesmo término em pares para exercitar o desempate pelo _id
+	now := time.Now()
+	for i := 0; i < 7; i++ {
+		auction, err := auction_entity.CreateAuction(
+			testSellerId,
+			fmt.Sprintf("Paged Product %d", i+1),
+			"Test Category",
+			"Test Description for pagination",
+			auction_entity.New,
+		)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if err := auction.SetEndTime(now.Add(time.Duration(i/2+1) * time.Hour)); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if err := repo.CreateAuction(ctx, auction); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+
+	seen := make(map[string]bool)
+	var lastEndTime time.Time
+	query := auction_entity.AuctionQuery{Sort: auction_entity.SortEndingSoonest, Limit: 3}
+	for pages := 0; ; pages++ {
+		page, err := repo.FindAuctions(ctx, query)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		for _, auction := range page.Auctions {
+			assert.False(t, seen[auction.Id], "auction repeated across pages")
+			assert.False(t, auction.EndTime.Before(lastEndTime), "auctions out of order")
+			seen[auction.Id] = true
+			lastEndTime = auction.EndTime
+		}
+
+		if page.NextPageToken == "" {
+			assert.Equal(t, 2, pages)
+			break
+		}
+		query.PageToken = page.NextPageToken
+	}
+
+	assert.Len(t, seen, 7)
+}
+
+// Leilões sem end_time recebem o término na migração e entram na paginação
+func TestMigrateLegacyEndTimesIntegration(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test")
+	}
+
+	database, cleanup := setupTestDatabase(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	repo := NewAuctionRepository(database)
+
+	for i := 0; i < 3; i++ {
+		auction, err := auction_entity.CreateAuction(
+			testSellerId, fmt.Sprintf("Legacy Product %d", i+1), "Test Category",
+			"Legacy auction description", auction_entity.New)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if err := repo.CreateAuction(ctx, auction); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+
+	if _, err := repo.Collection.UpdateMany(ctx, bson.M{}, bson.M{"$unset": bson.M{"end_time": ""}}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if err := repo.MigrateLegacyEndTimes(ctx); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	seen := make(map[string]bool)
+	query := auction_entity.AuctionQuery{Sort: auction_entity.SortEndingSoonest, Limit: 1}
+	for {
+		page, err := repo.FindAuctions(ctx, query)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		for _, auction := range page.Auctions {
+			seen[auction.Id] = true
+		}
+
+		if page.NextPageToken == "" {
+			break
+		}
+		query.PageToken = page.NextPageToken
+	}
+
+	assert.Len(t, seen, 3)
+}
+
+// Leilões anteriores à árvore passam a apontar para a categoria de mesmo slug
+func TestMigrateLegacyCategoriesIntegration(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test")
+	}
+
+	database, cleanup := setupTestDatabase(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	repo := NewAuctionRepository(database)
+	categoryRepository := category.NewCategoryRepository(database)
+
+	phones, err := category_entity.CreateCategory("Celulares", "", "")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := categoryRepository.CreateCategory(ctx, phones); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	var auctions []*auction_entity.Auction
+	for _, name := range []string{"celulares", "Móveis Antigos"} {
+		auction, err := auction_entity.CreateAuction(
+			testSellerId, "Legacy Product", name, "Legacy auction description", auction_entity.New)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if err := repo.CreateAuction(ctx, auction); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		auctions = append(auctions, auction)
+	}
+
+	if err := repo.MigrateLegacyCategories(ctx, categoryRepository); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	migrated, internalErr := repo.FindAuctionById(ctx, auctions[0].Id)
+	assert.Nil(t, internalErr)
+	assert.Equal(t, phones.Id, migrated.CategoryId)
+	assert.Equal(t, "Celulares", migrated.Category)
+
+	created, internalErr := repo.FindAuctionById(ctx, auctions[1].Id)
+	assert.Nil(t, internalErr)
+	assert.NotEmpty(t, created.CategoryId)
+	assert.Equal(t, "Móveis Antigos", created.Category)
+
+	assert.Nil(t, repo.RenameCategory(ctx, phones.Id, "Smartphones"))
+	renamed, internalErr := repo.FindAuctionById(ctx, auctions[0].Id)
+	assert.Nil(t, internalErr)
+	assert.Equal(t, "Smartphones", renamed.Category)
+}
+
+// Testa a busca textual com o índice de texto e a ordenação por relevância
+func TestSearchAuctionsIntegration(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test")
+	}
+
+	database, cleanup := setupTestDatabase(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	repo := NewAuctionRepository(database)
+	if err := repo.CreateIndexes(ctx); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	products := []struct{ name, description string }{
+		{"Guitarra Fender", "Instrumento em ótimo estado"},
+		{"Amplificador", "Acompanha cabo para guitarra"},
+		{"Bicicleta", "Aro 29 com marchas"},
+	}
+	for _, product := range products {
+		auction, err := auction_entity.CreateAuction(
+			testSellerId, product.name, "Música", product.description, auction_entity.Used)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if err := repo.CreateAuction(ctx, auction); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+
+	results, err := repo.SearchAuctions(ctx, auction_entity.AuctionSearchQuery{Text: "guitarra", Limit: 10})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	assert.Len(t, results, 2)
+	assert.Equal(t, "Guitarra Fender", results[0].Auction.ProductName)
+	assert.Greater(t, results[0].Score, results[1].Score)
+}
+
+// Testa que a compra imediata e o fechamento automático não encerram o leilão duas vezes
+func TestCompleteAuctionIntegration(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test")
+	}
+
+	database, cleanup := setupTestDatabase(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	repo := NewAuctionRepository(database)
+
+	auction, err := auction_entity.CreateAuction(
+		"seller-id", "Buy Now Product", "Test Category", "Auction completed by buy now", auction_entity.New)
+	if err != nil {
+		t.Fatalf("Failed to create auction entity: %v", err)
+	}
+	if err := auction.SetEndTime(time.Now().Add(time.Second)); err != nil {
+		t.Fatalf("Failed to set end time: %v", err)
+	}
+	if err := repo.CreateAuction(ctx, auction); err != nil {
+		t.Fatalf("Failed to create auction: %v", err)
+	}
+
+	applied := 0
+	apply := func(ctx context.Context) error {
+		applied++
+		return nil
+	}
+
+	assert.Nil(t, repo.CompleteAuction(ctx, auction.Id, apply))
+
+	internalErr := repo.CompleteAuction(ctx, auction.Id, apply)
+	assert.NotNil(t, internalErr)
+	assert.Equal(t, "bad_request", internalErr.Err)
+	assert.Equal(t, 1, applied)
+
+	// O fechamento automático foi cancelado e não altera o leilão concluído
+	time.Sleep(2 * time.Second)
+
+	found, internalErr := repo.FindAuctionById(ctx, auction.Id)
+	if internalErr != nil {
+		t.Fatalf("Unexpected error: %v", internalErr)
+	}
+	assert.Equal(t, auction_entity.Completed, found.Status)
+}
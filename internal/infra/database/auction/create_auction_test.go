@@ -130,3 +130,28 @@ func TestStartAutoCloseRoutine(t *testing.T) {
 		assert.Equal(t, 50*time.Millisecond, interval)
 	})
 }
+
+func TestAuctionEntityMongoEndTime(t *testing.T) {
+	timestamp := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+
+	t.Run("stored end time is the source of truth", func(t *testing.T) {
+		os.Setenv("AUCTION_DURATION", "10s")
+		defer os.Unsetenv("AUCTION_DURATION")
+
+		auctionMongo := AuctionEntityMongo{
+			Timestamp: timestamp.Unix(),
+			EndTime:   timestamp.Add(time.Hour).Unix(),
+		}
+
+		assert.True(t, timestamp.Add(time.Hour).Equal(auctionMongo.toEntity().EndTime))
+	})
+
+	t.Run("legacy auction without end time uses default duration", func(t *testing.T) {
+		os.Setenv("AUCTION_DURATION", "10s")
+		defer os.Unsetenv("AUCTION_DURATION")
+
+		auctionMongo := AuctionEntityMongo{Timestamp: timestamp.Unix()}
+
+		assert.True(t, timestamp.Add(10*time.Second).Equal(auctionMongo.toEntity().EndTime))
+	})
+}
@@ -2,14 +2,17 @@ package auction
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"time"
+	"regexp"
 
 	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/configuration/logger"
 	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/entity/auction_entity"
 	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/internal_error"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 func (ar *AuctionRepository) FindAuctionById(
@@ -18,41 +21,57 @@ func (ar *AuctionRepository) FindAuctionById(
 
 	var auctionEntityMongo AuctionEntityMongo
 	if err := ar.Collection.FindOne(ctx, filter).Decode(&auctionEntityMongo); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			logger.Error(fmt.Sprintf("Auction not found with this id = %s", id), err)
+			return nil, internal_error.NewNotFoundError(
+				fmt.Sprintf("Auction not found with this id = %s", id))
+		}
+
 		logger.Error(fmt.Sprintf("Error trying to find auction by id = %s", id), err)
 		return nil, internal_error.NewInternalServerError("Error trying to find auction by id")
 	}
 
-	return &auction_entity.Auction{
-		Id:          auctionEntityMongo.Id,
-		ProductName: auctionEntityMongo.ProductName,
-		Category:    auctionEntityMongo.Category,
-		Description: auctionEntityMongo.Description,
-		Condition:   auctionEntityMongo.Condition,
-		Status:      auctionEntityMongo.Status,
-		Timestamp:   time.Unix(auctionEntityMongo.Timestamp, 0),
-	}, nil
+	return auctionEntityMongo.toEntity(), nil
 }
 
-func (repo *AuctionRepository) FindAuctions(
+// FindAuctions pagina por chave (keyset): cada página continua a partir do
+// último item da anterior, usando o _id como desempate, sem skip.
+func (ar *AuctionRepository) FindAuctions(
 	ctx context.Context,
-	status auction_entity.AuctionStatus,
-	category string,
-	productName string) ([]auction_entity.Auction, *internal_error.InternalError) {
+	query auction_entity.AuctionQuery) (*auction_entity.AuctionPage, *internal_error.InternalError) {
+	spec := auctionSorts[query.Sort]
+
 	filter := bson.M{}
 
-	if status != 0 {
-		filter["status"] = status
+	if query.SellerId != "" {
+		filter["seller_id"] = query.SellerId
+	}
+
+	if query.Status != nil {
+		filter["status"] = *query.Status
 	}
 
-	if category != "" {
-		filter["category"] = category
+	if len(query.CategoryIds) > 0 {
+		filter["category_id"] = bson.M{"$in": query.CategoryIds}
 	}
 
-	if productName != "" {
-		filter["productName"] = primitive.Regex{Pattern: productName, Options: "i"}
+	if query.ProductName != "" {
+		filter["product_name"] = primitive.Regex{Pattern: regexp.QuoteMeta(query.ProductName), Options: "i"}
 	}
 
-	cursor, err := repo.Collection.Find(ctx, filter)
+	if query.PageToken != "" {
+		token, err := decodePageToken(query.PageToken, query.Sort)
+		if err != nil {
+			return nil, err
+		}
+		filter["$or"] = spec.after(token)
+	}
+
+	opts := options.Find().
+		SetSort(spec.order()).
+		SetLimit(int64(query.Limit) + 1)
+
+	cursor, err := ar.Collection.Find(ctx, filter, opts)
 	if err != nil {
 		logger.Error("Error finding auctions", err)
 		return nil, internal_error.NewInternalServerError("Error finding auctions")
@@ -65,18 +84,28 @@ func (repo *AuctionRepository) FindAuctions(
 		return nil, internal_error.NewInternalServerError("Error decoding auctions")
 	}
 
-	var auctionsEntity []auction_entity.Auction
+	page := &auction_entity.AuctionPage{Auctions: []auction_entity.Auction{}}
+	if len(auctionsMongo) > query.Limit {
+		auctionsMongo = auctionsMongo[:query.Limit]
+		page.NextPageToken = encodePageToken(query.Sort, spec, auctionsMongo[len(auctionsMongo)-1])
+	}
+
 	for _, auction := range auctionsMongo {
-		auctionsEntity = append(auctionsEntity, auction_entity.Auction{
-			Id:          auction.Id,
-			ProductName: auction.ProductName,
-			Category:    auction.Category,
-			Status:      auction.Status,
-			Description: auction.Description,
-			Condition:   auction.Condition,
-			Timestamp:   time.Unix(auction.Timestamp, 0),
-		})
+		page.Auctions = append(page.Auctions, *auction.toEntity())
+	}
+
+	return page, nil
+}
+
+// CountAuctionsByCategory informa quantos leilões referenciam a categoria,
+// impedindo que ela seja removida enquanto estiver em uso.
+func (ar *AuctionRepository) CountAuctionsByCategory(
+	ctx context.Context, categoryId string) (int64, *internal_error.InternalError) {
+	count, err := ar.Collection.CountDocuments(ctx, bson.M{"category_id": categoryId})
+	if err != nil {
+		logger.Error("Error counting category auctions", err)
+		return 0, internal_error.NewInternalServerError("Error counting category auctions")
 	}
 
-	return auctionsEntity, nil
+	return count, nil
 }
@@ -0,0 +1,156 @@
+package auction
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/configuration/logger"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/entity/auction_entity"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/internal_error"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.uber.org/zap"
+)
+
+type AuctionImageMongo struct {
+	Id           string `bson:"_id"`
+	ContentType  string `bson:"content_type"`
+	Size         int64  `bson:"size"`
+	Width        int    `bson:"width"`
+	Height       int    `bson:"height"`
+	Key          string `bson:"key"`
+	URL          string `bson:"url"`
+	ThumbnailKey string `bson:"thumbnail_key"`
+	ThumbnailURL string `bson:"thumbnail_url"`
+	CreatedAt    int64  `bson:"created_at"`
+}
+
+// AddAuctionImage anexa a foto em uma única operação condicional, para que
+// envios simultâneos não ultrapassem o limite de fotos do leilão e um lance
+// gravado ao mesmo tempo impeça a alteração.
+func (ar *AuctionRepository) AddAuctionImage(
+	ctx context.Context,
+	auctionId string,
+	auctionImage auction_entity.AuctionImage) *internal_error.InternalError {
+	filter := bson.M{
+		"_id":       auctionId,
+		"status":    bson.M{"$in": auction_entity.PreviousStatuses(auction_entity.Cancelled)},
+		"bid_count": 0,
+		fmt.Sprintf("images.%d", auction_entity.MaxImagesPerAuction-1): bson.M{"$exists": false},
+	}
+	update := bson.M{"$push": bson.M{"images": toAuctionImageMongo(auctionImage)}}
+
+	result, err := ar.Collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		logger.Error("Error trying to add auction image", err)
+		return internal_error.NewInternalServerError("Error trying to add auction image")
+	}
+
+	if result.MatchedCount == 0 {
+		auction, err := ar.FindAuctionById(ctx, auctionId)
+		if err != nil {
+			return err
+		}
+
+		if err := imagesLocked(auction); err != nil {
+			return err
+		}
+
+		return internal_error.NewBadRequestError(
+			fmt.Sprintf("Auction already has %d images", auction_entity.MaxImagesPerAuction))
+	}
+
+	logger.Info("Auction image added",
+		zap.String("auction_id", auctionId),
+		zap.String("image_id", auctionImage.Id),
+	)
+
+	return nil
+}
+
+func (ar *AuctionRepository) RemoveAuctionImage(
+	ctx context.Context,
+	auctionId, imageId string) *internal_error.InternalError {
+	filter := bson.M{
+		"_id":       auctionId,
+		"status":    bson.M{"$in": auction_entity.PreviousStatuses(auction_entity.Cancelled)},
+		"bid_count": 0,
+	}
+	update := bson.M{"$pull": bson.M{"images": bson.M{"_id": imageId}}}
+
+	result, err := ar.Collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		logger.Error("Error trying to remove auction image", err)
+		return internal_error.NewInternalServerError("Error trying to remove auction image")
+	}
+
+	if result.MatchedCount == 0 {
+		auction, err := ar.FindAuctionById(ctx, auctionId)
+		if err != nil {
+			return err
+		}
+
+		if err := imagesLocked(auction); err != nil {
+			return err
+		}
+	}
+
+	if result.ModifiedCount == 0 {
+		return internal_error.NewNotFoundError(
+			fmt.Sprintf("Image not found with this id = %s", imageId))
+	}
+
+	return nil
+}
+
+// imagesLocked explica por que as fotos de um leilão não podem mais mudar
+func imagesLocked(auction *auction_entity.Auction) *internal_error.InternalError {
+	if auction.Status.Final() {
+		return internal_error.NewBadRequestError("Auction can no longer be changed")
+	}
+
+	if auction.BidCount > 0 {
+		return internal_error.NewBadRequestError("Auction already has bids")
+	}
+
+	return nil
+}
+
+func toAuctionImageMongo(auctionImage auction_entity.AuctionImage) AuctionImageMongo {
+	return AuctionImageMongo{
+		Id:           auctionImage.Id,
+		ContentType:  auctionImage.ContentType,
+		Size:         auctionImage.Size,
+		Width:        auctionImage.Width,
+		Height:       auctionImage.Height,
+		Key:          auctionImage.Key,
+		URL:          auctionImage.URL,
+		ThumbnailKey: auctionImage.ThumbnailKey,
+		ThumbnailURL: auctionImage.ThumbnailURL,
+		CreatedAt:    auctionImage.CreatedAt.Unix(),
+	}
+}
+
+func toAuctionImages(imagesMongo []AuctionImageMongo) []auction_entity.AuctionImage {
+	if len(imagesMongo) == 0 {
+		return nil
+	}
+
+	images := make([]auction_entity.AuctionImage, 0, len(imagesMongo))
+	for _, image := range imagesMongo {
+		images = append(images, auction_entity.AuctionImage{
+			Id:           image.Id,
+			ContentType:  image.ContentType,
+			Size:         image.Size,
+			Width:        image.Width,
+			Height:       image.Height,
+			Key:          image.Key,
+			URL:          image.URL,
+			ThumbnailKey: image.ThumbnailKey,
+			ThumbnailURL: image.ThumbnailURL,
+			CreatedAt:    time.Unix(image.CreatedAt, 0),
+		})
+	}
+
+	return images
+}
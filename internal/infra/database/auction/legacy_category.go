@@ -0,0 +1,117 @@
+package auction
+
+import (
+	"context"
+	"strings"
+
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/configuration/logger"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/entity/category_entity"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/internal_error"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.uber.org/zap"
+)
+
+// MigrateLegacyCategories associa a uma categoria da árvore os leilões
+// gravados antes dela, que só têm o nome livre em category. O nome é
+// comparado pelo slug; nomes sem categoria correspondente viram categorias
+// raiz. Também reaplica o nome atual de cada categoria aos seus leilões, o que
+// completa renomeações interrompidas. Pode rodar a cada inicialização.
+func (ar *AuctionRepository) MigrateLegacyCategories(
+	ctx context.Context,
+	categoryRepository category_entity.CategoryRepositoryInterface) *internal_error.InternalError {
+	legacy := bson.M{"category_id": bson.M{"$exists": false}, "category": bson.M{"$nin": bson.A{"", nil}}}
+
+	names, err := ar.Collection.Distinct(ctx, "category", legacy)
+	if err != nil {
+		logger.Error("Error trying to find legacy auction categories", err)
+		return internal_error.NewInternalServerError("Error trying to migrate legacy categories")
+	}
+
+	categories, internalErr := categoryRepository.FindCategories(ctx)
+	if internalErr != nil {
+		return internalErr
+	}
+
+	var migrated int64
+	for _, value := range names {
+		name, ok := value.(string)
+		if !ok {
+			continue
+		}
+
+		category, internalErr := ar.legacyCategory(ctx, categoryRepository, &categories, name)
+		if internalErr != nil {
+			return internalErr
+		}
+		if category == nil {
+			logger.Info("Legacy auction category left unmapped", zap.String("category", name))
+			continue
+		}
+
+		filter := bson.M{"category_id": bson.M{"$exists": false}, "category": name}
+		update := bson.M{"$set": bson.M{"category_id": category.Id, "category": category.Name}}
+		result, err := ar.Collection.UpdateMany(ctx, filter, update)
+		if err != nil {
+			logger.Error("Error trying to migrate legacy categories", err)
+			return internal_error.NewInternalServerError("Error trying to migrate legacy categories")
+		}
+		migrated += result.ModifiedCount
+	}
+
+	for _, category := range categories {
+		if err := ar.RenameCategory(ctx, category.Id, category.Name); err != nil {
+			return err
+		}
+	}
+
+	if migrated > 0 {
+		logger.Info("Legacy auction categories migrated", zap.Int64("auctions", migrated))
+	}
+
+	return nil
+}
+
+// legacyCategory encontra a categoria de mesmo slug ou a cria como raiz.
+// Devolve nil quando o nome não forma uma categoria válida.
+func (ar *AuctionRepository) legacyCategory(
+	ctx context.Context,
+	categoryRepository category_entity.CategoryRepositoryInterface,
+	categories *[]category_entity.Category,
+	name string) (*category_entity.Category, *internal_error.InternalError) {
+	slug := category_entity.Slugify(name)
+	if existing := findCategoryBySlug(*categories, slug); existing != nil {
+		return existing, nil
+	}
+
+	category, internalErr := category_entity.CreateCategory(strings.TrimSpace(name), slug, "")
+	if internalErr != nil {
+		return nil, nil
+	}
+
+	if internalErr := categoryRepository.CreateCategory(ctx, category); internalErr != nil {
+		if internalErr.Err != "bad_request" {
+			return nil, internalErr
+		}
+
+		// Outra instância criou o mesmo slug ao mesmo tempo
+		current, internalErr := categoryRepository.FindCategories(ctx)
+		if internalErr != nil {
+			return nil, internalErr
+		}
+		*categories = current
+		return findCategoryBySlug(current, slug), nil
+	}
+
+	*categories = append(*categories, *category)
+	return category, nil
+}
+
+func findCategoryBySlug(categories []category_entity.Category, slug string) *category_entity.Category {
+	for i := range categories {
+		if categories[i].Slug == slug {
+			return &categories[i]
+		}
+	}
+
+	return nil
+}
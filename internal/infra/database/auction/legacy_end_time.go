@@ -0,0 +1,33 @@
+package auction
+
+import (
+	"context"
+
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/configuration/logger"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/internal_error"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.uber.org/zap"
+)
+
+// MigrateLegacyEndTimes grava o término dos leilões anteriores ao campo
+// end_time, com a mesma duração padrão usada na leitura deles. Sem o campo,
+// esses leilões ficavam fora da paginação por ending_soonest, que compara
+// end_time no cursor. Pode rodar a cada inicialização.
+func (ar *AuctionRepository) MigrateLegacyEndTimes(ctx context.Context) *internal_error.InternalError {
+	filter := bson.M{"end_time": bson.M{"$in": bson.A{nil, 0}}}
+	update := bson.A{bson.M{"$set": bson.M{"end_time": bson.M{"$toLong": bson.M{
+		"$add": bson.A{"$timestamp", int64(getAuctionDuration().Seconds())},
+	}}}}}
+
+	result, err := ar.Collection.UpdateMany(ctx, filter, update)
+	if err != nil {
+		logger.Error("Error trying to migrate legacy auction end times", err)
+		return internal_error.NewInternalServerError("Error trying to migrate legacy auction end times")
+	}
+
+	if result.ModifiedCount > 0 {
+		logger.Info("Legacy auction end times migrated", zap.Int64("auctions", result.ModifiedCount))
+	}
+
+	return nil
+}
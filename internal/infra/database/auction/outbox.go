@@ -0,0 +1,34 @@
+package auction
+
+import (
+	"context"
+	"time"
+
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/entity/auction_entity"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/entity/outbox_entity"
+)
+
+type auctionClosedPayload struct {
+	AuctionId string                       `json:"auction_id"`
+	Status    auction_entity.AuctionStatus `json:"status"`
+	ClosedAt  time.Time                    `json:"closed_at"`
+}
+
+// saveClosedMessage grava o aviso de fechamento no outbox usando a mesma
+// sessão da atualização de status
+func (ar *AuctionRepository) saveClosedMessage(
+	ctx context.Context,
+	auctionId string,
+	status auction_entity.AuctionStatus,
+	closedAt time.Time) error {
+	message, err := outbox_entity.NewMessage(outbox_entity.AuctionClosedEvent, auctionId, auctionClosedPayload{
+		AuctionId: auctionId,
+		Status:    status,
+		ClosedAt:  closedAt,
+	})
+	if err != nil {
+		return err
+	}
+
+	return ar.Outbox.SaveMessages(ctx, *message)
+}
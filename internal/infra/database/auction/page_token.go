@@ -0,0 +1,87 @@
+package auction
+
+import (
+	"encoding/base64"
+	"encoding/json"
+
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/entity/auction_entity"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/internal_error"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// sortSpec descreve uma ordenação da listagem. Só timestamp é imutável:
+// highest_bid, bid_count e end_time mudam com os lances, então um leilão que
+// troca de posição entre duas páginas pode ser pulado ou entregue de novo.
+type sortSpec struct {
+	field     string
+	direction int
+	value     func(am *AuctionEntityMongo) float64
+}
+
+var auctionSorts = map[auction_entity.AuctionSort]sortSpec{
+	auction_entity.SortNewest: {
+		field: "timestamp", direction: -1,
+		value: func(am *AuctionEntityMongo) float64 { return float64(am.Timestamp) },
+	},
+	auction_entity.SortEndingSoonest: {
+		field: "end_time", direction: 1,
+		value: func(am *AuctionEntityMongo) float64 { return float64(am.endTime().Unix()) },
+	},
+	auction_entity.SortHighestBid: {
+		field: "highest_bid", direction: -1,
+		value: func(am *AuctionEntityMongo) float64 { return float64(am.HighestBid) },
+	},
+	auction_entity.SortMostBids: {
+		field: "bid_count", direction: -1,
+		value: func(am *AuctionEntityMongo) float64 { return float64(am.BidCount) },
+	},
+}
+
+// pageToken guarda a posição do último item entregue
+type pageToken struct {
+	Sort  auction_entity.AuctionSort `json:"s"`
+	Value float64                    `json:"v"`
+	Id    string                     `json:"id"`
+}
+
+func (s sortSpec) order() bson.D {
+	return bson.D{{Key: s.field, Value: s.direction}, {Key: "_id", Value: s.direction}}
+}
+
+// after seleciona os documentos posteriores ao token na ordem da listagem
+func (s sortSpec) after(token *pageToken) bson.A {
+	operator := "$gt"
+	if s.direction < 0 {
+		operator = "$lt"
+	}
+
+	return bson.A{
+		bson.M{s.field: bson.M{operator: token.Value}},
+		bson.M{s.field: token.Value, "_id": bson.M{operator: token.Id}},
+	}
+}
+
+func encodePageToken(sort auction_entity.AuctionSort, spec sortSpec, last AuctionEntityMongo) string {
+	data, _ := json.Marshal(pageToken{Sort: sort, Value: spec.value(&last), Id: last.Id})
+
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodePageToken(
+	encoded string, sort auction_entity.AuctionSort) (*pageToken, *internal_error.InternalError) {
+	data, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, internal_error.NewBadRequestError("Invalid page_token")
+	}
+
+	var token pageToken
+	if err := json.Unmarshal(data, &token); err != nil || token.Id == "" {
+		return nil, internal_error.NewBadRequestError("Invalid page_token")
+	}
+
+	if token.Sort != sort {
+		return nil, internal_error.NewBadRequestError("page_token belongs to a different sort")
+	}
+
+	return &token, nil
+}
@@ -0,0 +1,57 @@
+package auction
+
+import (
+	"testing"
+
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/entity/auction_entity"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestPageTokenRoundTrip(t *testing.T) {
+	spec := auctionSorts[auction_entity.SortHighestBid]
+	encoded := encodePageToken(auction_entity.SortHighestBid, spec,
+		AuctionEntityMongo{Id: "auction-id", HighestBid: 15050})
+
+	token, err := decodePageToken(encoded, auction_entity.SortHighestBid)
+	assert.Nil(t, err)
+	assert.Equal(t, float64(15050), token.Value)
+	assert.Equal(t, "auction-id", token.Id)
+
+	assert.Equal(t, bson.A{
+		bson.M{"highest_bid": bson.M{"$lt": float64(15050)}},
+		bson.M{"highest_bid": float64(15050), "_id": bson.M{"$lt": "auction-id"}},
+	}, spec.after(token))
+
+	_, err = decodePageToken(encoded, auction_entity.SortNewest)
+	assert.Equal(t, "bad_request", err.Err)
+
+	_, err = decodePageToken("not a token", auction_entity.SortHighestBid)
+	assert.Equal(t, "bad_request", err.Err)
+}
+
+func TestSortOrderIncludesTieBreaker(t *testing.T) {
+	assert.Equal(t, bson.D{{Key: "end_time", Value: 1}, {Key: "_id", Value: 1}},
+		auctionSorts[auction_entity.SortEndingSoonest].order())
+	assert.Equal(t, bson.A{
+		bson.M{"end_time": bson.M{"$gt": float64(10)}},
+		bson.M{"end_time": float64(10), "_id": bson.M{"$gt": "a"}},
+	}, auctionSorts[auction_entity.SortEndingSoonest].after(&pageToken{Value: 10, Id: "a"}))
+}
+
+func TestEndingSoonestTokenForLegacyAuction(t *testing.T) {
+	t.Setenv("AUCTION_DURATION", "10m")
+	spec := auctionSorts[auction_entity.SortEndingSoonest]
+
+	// Sem end_time, o cursor usa o término que a migração grava no documento
+	legacy := AuctionEntityMongo{Id: "legacy-id", Timestamp: 1700000000}
+	token, err := decodePageToken(encodePageToken(auction_entity.SortEndingSoonest, spec, legacy),
+		auction_entity.SortEndingSoonest)
+	assert.Nil(t, err)
+	assert.Equal(t, float64(1700000600), token.Value)
+
+	assert.Equal(t, bson.A{
+		bson.M{"end_time": bson.M{"$gt": float64(1700000600)}},
+		bson.M{"end_time": float64(1700000600), "_id": bson.M{"$gt": "legacy-id"}},
+	}, spec.after(token))
+}
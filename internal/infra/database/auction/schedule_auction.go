@@ -0,0 +1,68 @@
+package auction
+
+import (
+	"context"
+	"time"
+
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/configuration/logger"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/entity/auction_entity"
+	"go.uber.org/zap"
+)
+
+// activationKey separa a abertura do fechamento do mesmo leilão no scheduler
+func activationKey(auctionId string) string {
+	return "auction_start:" + auctionId
+}
+
+func (ar *AuctionRepository) scheduleActivation(auctionId string, startsAt time.Time) {
+	ar.Scheduler.Schedule(activationKey(auctionId), startsAt, func(ctx context.Context) {
+		ar.activateScheduledAuction(ctx, auctionId)
+	})
+
+	logger.Info("Activation scheduled for auction",
+		zap.String("auction_id", auctionId),
+		zap.Time("starts_at", startsAt),
+	)
+}
+
+// activateScheduledAuction abre o leilão e agenda seu fechamento. Leilões
+// cancelados antes da abertura são ignorados pela máquina de estados.
+func (ar *AuctionRepository) activateScheduledAuction(ctx context.Context, auctionId string) {
+	if err := ar.UpdateAuctionStatus(ctx, auctionId, auction_entity.Active); err != nil {
+		if err.Err == "internal_server_error" {
+			ar.scheduleActivation(auctionId, time.Now().Add(getAuctionCheckInterval()))
+			return
+		}
+
+		logger.Error("Scheduled auction was not activated", err, zap.String("auction_id", auctionId))
+		return
+	}
+
+	auctionEntity, err := ar.FindAuctionById(ctx, auctionId)
+	if err != nil {
+		ar.scheduleAutoClose(auctionId, time.Now().Add(getAuctionCheckInterval()))
+		return
+	}
+
+	ar.scheduleAutoClose(auctionId, auctionEntity.EndTime)
+
+	logger.Info("Scheduled auction activated",
+		zap.String("auction_id", auctionId),
+	)
+}
+
+func unixOrZero(value time.Time) int64 {
+	if value.IsZero() {
+		return 0
+	}
+
+	return value.Unix()
+}
+
+func timeOrZero(value int64) time.Time {
+	if value == 0 {
+		return time.Time{}
+	}
+
+	return time.Unix(value, 0)
+}
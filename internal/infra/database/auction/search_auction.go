@@ -0,0 +1,75 @@
+package auction
+
+import (
+	"context"
+
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/configuration/logger"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/entity/auction_entity"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/internal_error"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type auctionSearchResultMongo struct {
+	AuctionEntityMongo `bson:",inline"`
+	Score              float64 `bson:"score"`
+}
+
+// textIndex é o único índice de texto da coleção; a relevância segue os
+// pesos definidos na entidade.
+func textIndex() mongo.IndexModel {
+	return mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "product_name", Value: "text"},
+			{Key: "category", Value: "text"},
+			{Key: "description", Value: "text"},
+		},
+		Options: options.Index().
+			SetName("auction_text").
+			SetDefaultLanguage("portuguese").
+			SetWeights(bson.D{
+				{Key: "product_name", Value: auction_entity.ProductNameSearchWeight},
+				{Key: "category", Value: auction_entity.CategorySearchWeight},
+				{Key: "description", Value: auction_entity.DescriptionSearchWeight},
+			}),
+	}
+}
+
+func (ar *AuctionRepository) SearchAuctions(
+	ctx context.Context,
+	query auction_entity.AuctionSearchQuery) ([]auction_entity.AuctionSearchResult, *internal_error.InternalError) {
+	filter := bson.M{"$text": bson.M{"$search": query.Text}}
+	if query.Status != nil {
+		filter["status"] = *query.Status
+	}
+
+	score := bson.M{"$meta": "textScore"}
+	opts := options.Find().
+		SetProjection(bson.M{"score": score}).
+		SetSort(bson.D{{Key: "score", Value: score}, {Key: "_id", Value: 1}}).
+		SetLimit(int64(query.Limit))
+
+	cursor, err := ar.Collection.Find(ctx, filter, opts)
+	if err != nil {
+		logger.Error("Error searching auctions", err)
+		return nil, internal_error.NewInternalServerError("Error searching auctions")
+	}
+	defer cursor.Close(ctx)
+
+	var resultsMongo []auctionSearchResultMongo
+	if err := cursor.All(ctx, &resultsMongo); err != nil {
+		logger.Error("Error decoding auction search results", err)
+		return nil, internal_error.NewInternalServerError("Error decoding auction search results")
+	}
+
+	results := make([]auction_entity.AuctionSearchResult, 0, len(resultsMongo))
+	for _, result := range resultsMongo {
+		results = append(results, auction_entity.AuctionSearchResult{
+			Auction: *result.toEntity(),
+			Score:   result.Score,
+		})
+	}
+
+	return results, nil
+}
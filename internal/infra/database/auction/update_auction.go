@@ -0,0 +1,183 @@
+package auction
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/configuration/database/mongodb"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/configuration/logger"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/entity/auction_entity"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/internal_error"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.uber.org/zap"
+)
+
+// UpdateAuctionEndTime prorroga um leilão ativo. Roda dentro da transação que
+// grava o lance responsável pela prorrogação e falha com ErrAuctionNotOpen se
+// o leilão já não estiver ativo; depois do commit, quem chama deve avisar com
+// AuctionEndTimeExtended.
+func (ar *AuctionRepository) UpdateAuctionEndTime(ctx context.Context, id string, endTime time.Time) error {
+	filter := bson.M{"_id": id, "status": auction_entity.Active}
+	update := bson.M{"$set": bson.M{"end_time": endTime.Unix()}}
+
+	result, err := ar.Collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return ErrAuctionNotOpen
+	}
+
+	return nil
+}
+
+// AuctionEndTimeExtended reagenda o fechamento de um leilão prorrogado.
+func (ar *AuctionRepository) AuctionEndTimeExtended(id string, endTime time.Time) {
+	ar.scheduleAutoClose(id, endTime)
+
+	logger.Info("Auction end time extended",
+		zap.String("auction_id", id),
+		zap.Time("end_time", endTime),
+	)
+}
+
+// CompleteAuction encerra um leilão ativo antes do término, gravando na mesma
+// transação o que apply gravar (o lance da compra imediata). Usa a mesma trava
+// e o mesmo filtro de status do fechamento automático, então um leilão nunca é
+// encerrado duas vezes. Avisar o fechamento fica com quem chama.
+func (ar *AuctionRepository) CompleteAuction(
+	ctx context.Context,
+	id string,
+	apply func(ctx context.Context) error) *internal_error.InternalError {
+	now := time.Now()
+
+	ar.mu.Lock()
+	filter := bson.M{"_id": id, "status": auction_entity.Active, "end_time": bson.M{"$gt": now.Unix()}}
+	update := bson.M{"$set": bson.M{"status": auction_entity.Completed}}
+
+	completed := false
+	err := mongodb.WithTransaction(ctx, ar.Collection.Database().Client(), func(ctx context.Context) error {
+		result, err := ar.Collection.UpdateOne(ctx, filter, update)
+		if err != nil {
+			return err
+		}
+
+		completed = result.ModifiedCount > 0
+		if !completed {
+			return nil
+		}
+
+		if err := apply(ctx); err != nil {
+			return err
+		}
+
+		return ar.saveClosedMessage(ctx, id, auction_entity.Completed, now)
+	})
+	ar.mu.Unlock()
+
+	if err != nil {
+		logger.Error("Error trying to complete auction", err)
+		return internal_error.NewInternalServerError("Error trying to complete auction")
+	}
+
+	if !completed {
+		return internal_error.NewBadRequestError("Auction is not active")
+	}
+
+	ar.Scheduler.Cancel(id)
+
+	logger.Info("Auction completed before its end time", zap.String("auction_id", id))
+
+	return nil
+}
+
+// UpdateAuction grava os dados descritivos de um leilão ainda não encerrado e
+// sem lances. As duas condições ficam no filtro, então um lance gravado ao
+// mesmo tempo impede a alteração.
+func (ar *AuctionRepository) UpdateAuction(
+	ctx context.Context,
+	auctionEntity *auction_entity.Auction) *internal_error.InternalError {
+	// Qualquer status que ainda pode ser cancelado não é final
+	filter := bson.M{
+		"_id":       auctionEntity.Id,
+		"status":    bson.M{"$in": auction_entity.PreviousStatuses(auction_entity.Cancelled)},
+		"bid_count": 0,
+	}
+	update := bson.M{"$set": bson.M{
+		"category_id": auctionEntity.CategoryId,
+		"category":    auctionEntity.Category,
+		"description": auctionEntity.Description,
+	}}
+
+	result, err := ar.Collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		logger.Error("Error trying to update auction", err)
+		return internal_error.NewInternalServerError("Error trying to update auction")
+	}
+
+	if result.MatchedCount == 0 {
+		current, err := ar.FindAuctionById(ctx, auctionEntity.Id)
+		if err != nil {
+			return err
+		}
+		if current.BidCount > 0 && !current.Status.Final() {
+			return internal_error.NewBadRequestError("Auction already has bids")
+		}
+		return internal_error.NewBadRequestError("Auction can no longer be changed")
+	}
+
+	return nil
+}
+
+// ErrAuctionNotOpen indica que o status gravado do leilão não aceita mais lances
+var ErrAuctionNotOpen = errors.New("auction does not accept bids in its current status")
+
+// IncrementBidSummary soma lances recém-gravados ao resumo usado nas
+// ordenações por maior lance e por quantidade de lances. O resumo só é
+// alterado se o status gravado for o esperado, o que impede lances em um
+// leilão cancelado por outra instância.
+func (ar *AuctionRepository) IncrementBidSummary(
+	ctx context.Context,
+	id string,
+	status auction_entity.AuctionStatus,
+	bidCount int64,
+	highestAmount int64) error {
+	result, err := ar.Collection.UpdateOne(ctx, bson.M{"_id": id, "status": status}, bson.M{
+		"$inc": bson.M{"bid_count": bidCount},
+		"$max": bson.M{"highest_bid": highestAmount},
+	})
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return ErrAuctionNotOpen
+	}
+
+	return nil
+}
+
+func (ar *AuctionRepository) ResetBidSummary(ctx context.Context, id string) error {
+	_, err := ar.Collection.UpdateOne(ctx, bson.M{"_id": id}, bson.M{
+		"$set": bson.M{"bid_count": 0, "highest_bid": 0},
+	})
+
+	return err
+}
+
+// RenameCategory copia o nome atual da categoria para os leilões que a
+// referenciam, mantendo a exibição e a busca textual em dia.
+func (ar *AuctionRepository) RenameCategory(
+	ctx context.Context, categoryId, name string) *internal_error.InternalError {
+	filter := bson.M{"category_id": categoryId, "category": bson.M{"$ne": name}}
+	update := bson.M{"$set": bson.M{"category": name}}
+
+	if _, err := ar.Collection.UpdateMany(ctx, filter, update); err != nil {
+		logger.Error("Error trying to rename auction category", err, zap.String("category_id", categoryId))
+		return internal_error.NewInternalServerError("Error trying to rename auction category")
+	}
+
+	return nil
+}
@@ -0,0 +1,44 @@
+package bid
+
+import "sync"
+
+// auctionLocks serializa as decisões sobre lances de um mesmo leilão sem
+// bloquear os demais. Cada trava é descartada quando ninguém mais a usa.
+type auctionLocks struct {
+	mu    sync.Mutex
+	locks map[string]*auctionLock
+}
+
+type auctionLock struct {
+	mu   sync.Mutex
+	refs int
+}
+
+func newAuctionLocks() *auctionLocks {
+	return &auctionLocks{locks: make(map[string]*auctionLock)}
+}
+
+// lock trava o leilão e devolve a função que libera a trava.
+func (al *auctionLocks) lock(auctionId string) func() {
+	al.mu.Lock()
+	current, ok := al.locks[auctionId]
+	if !ok {
+		current = &auctionLock{}
+		al.locks[auctionId] = current
+	}
+	current.refs++
+	al.mu.Unlock()
+
+	current.mu.Lock()
+
+	return func() {
+		current.mu.Unlock()
+
+		al.mu.Lock()
+		current.refs--
+		if current.refs == 0 {
+			delete(al.locks, auctionId)
+		}
+		al.mu.Unlock()
+	}
+}
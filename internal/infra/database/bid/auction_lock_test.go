@@ -0,0 +1,54 @@
+package bid
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuctionLocks(t *testing.T) {
+	locks := newAuctionLocks()
+
+	unlock := locks.lock("auction-1")
+
+	// Outro leilão não espera pela trava do primeiro
+	otherDone := make(chan struct{})
+	go func() {
+		locks.lock("auction-2")()
+		close(otherDone)
+	}()
+	select {
+	case <-otherDone:
+	case <-time.After(time.Second):
+		t.Fatal("lock on another auction should not block")
+	}
+
+	// O mesmo leilão espera a liberação
+	sameDone := make(chan struct{})
+	go func() {
+		locks.lock("auction-1")()
+		close(sameDone)
+	}()
+	select {
+	case <-sameDone:
+		t.Fatal("lock on the same auction should block")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	unlock()
+	<-sameDone
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			locks.lock("auction-1")()
+		}()
+	}
+	wg.Wait()
+
+	assert.Empty(t, locks.locks, "unused locks are discarded")
+}
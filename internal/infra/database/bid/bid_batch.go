@@ -0,0 +1,143 @@
+package bid
+
+import (
+	"context"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/configuration/database/mongodb"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/configuration/logger"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultMaxBatchSize        = 5
+	defaultBatchInsertInterval = 10 * time.Millisecond
+	// Quem deu o lance espera o lote, então a espera tem um teto
+	maxBatchInsertInterval = time.Second
+	batchWriteTimeout      = 30 * time.Second
+)
+
+// bidWrite é a gravação de um lance já aceito, à espera do lote
+type bidWrite struct {
+	apply func(ctx context.Context) error
+	done  chan error
+}
+
+// bidBatcher agrupa em uma única transação as gravações de lances aceitos em
+// leilões diferentes. A aceitação continua sendo decidida na hora, sob a trava
+// do leilão; quem grava espera o resultado do próprio lote antes de responder.
+type bidBatcher struct {
+	client              *mongo.Client
+	writes              chan bidWrite
+	maxBatchSize        int
+	batchInsertInterval time.Duration
+}
+
+func newBidBatcher(client *mongo.Client) *bidBatcher {
+	maxBatchSize := getMaxBatchSize()
+	batcher := &bidBatcher{
+		client:              client,
+		writes:              make(chan bidWrite, maxBatchSize),
+		maxBatchSize:        maxBatchSize,
+		batchInsertInterval: getBatchInsertInterval(),
+	}
+
+	go batcher.run()
+
+	return batcher
+}
+
+// write entrega a gravação ao lote e espera a transação terminar. A espera
+// não acompanha o contexto da requisição: a trava do leilão só pode ser
+// liberada depois que o resultado da gravação é conhecido.
+func (bb *bidBatcher) write(apply func(ctx context.Context) error) error {
+	done := make(chan error, 1)
+	bb.writes <- bidWrite{apply: apply, done: done}
+
+	return <-done
+}
+
+func (bb *bidBatcher) run() {
+	for first := range bb.writes {
+		bb.flush(bb.collect(first))
+	}
+}
+
+// collect junta à primeira gravação as que chegarem até o lote encher ou o
+// intervalo acabar.
+func (bb *bidBatcher) collect(first bidWrite) []bidWrite {
+	batch := []bidWrite{first}
+
+	timer := time.NewTimer(bb.batchInsertInterval)
+	defer timer.Stop()
+
+	for len(batch) < bb.maxBatchSize {
+		select {
+		case write := <-bb.writes:
+			batch = append(batch, write)
+		case <-timer.C:
+			return batch
+		}
+	}
+
+	return batch
+}
+
+// flush grava o lote em uma transação. Se ela falhar, cada gravação é refeita
+// sozinha, para que um leilão encerrado não derrube os lances dos demais.
+func (bb *bidBatcher) flush(batch []bidWrite) {
+	err := bb.commit(batch)
+	if err == nil || len(batch) == 1 {
+		for _, write := range batch {
+			write.done <- err
+		}
+		return
+	}
+
+	logger.Info("Bid batch failed, writing bids one by one", zap.Int("size", len(batch)))
+	for _, write := range batch {
+		write.done <- bb.commit([]bidWrite{write})
+	}
+}
+
+func (bb *bidBatcher) commit(batch []bidWrite) error {
+	ctx, cancel := context.WithTimeout(context.Background(), batchWriteTimeout)
+	defer cancel()
+
+	return mongodb.WithTransaction(ctx, bb.client, func(ctx context.Context) error {
+		for _, write := range batch {
+			if err := write.apply(ctx); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func getMaxBatchSize() int {
+	value, err := strconv.Atoi(os.Getenv("MAX_BATCH_SIZE"))
+	if err != nil || value < 1 {
+		return defaultMaxBatchSize
+	}
+
+	return value
+}
+
+func getBatchInsertInterval() time.Duration {
+	value := os.Getenv("BATCH_INSERT_INTERVAL")
+	duration, err := time.ParseDuration(value)
+	if err != nil || duration < 0 {
+		return defaultBatchInsertInterval
+	}
+
+	if duration > maxBatchInsertInterval {
+		logger.Info("BATCH_INSERT_INTERVAL is above the limit, using the limit",
+			zap.String("value", value), zap.Duration("limit", maxBatchInsertInterval))
+		return maxBatchInsertInterval
+	}
+
+	return duration
+}
@@ -0,0 +1,28 @@
+package bid
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBidBatcherCollect(t *testing.T) {
+	batcher := &bidBatcher{
+		writes:              make(chan bidWrite, 5),
+		maxBatchSize:        3,
+		batchInsertInterval: time.Hour,
+	}
+
+	// O lote fecha ao atingir o tamanho máximo, sem esperar o intervalo
+	for i := 0; i < 4; i++ {
+		batcher.writes <- bidWrite{}
+	}
+	assert.Len(t, batcher.collect(bidWrite{}), 3)
+	assert.Len(t, batcher.writes, 2)
+
+	// Sem novas gravações, o lote fecha quando o intervalo acaba
+	batcher.batchInsertInterval = 10 * time.Millisecond
+	assert.Len(t, batcher.collect(bidWrite{}), 3)
+	assert.Len(t, batcher.collect(bidWrite{}), 1)
+}
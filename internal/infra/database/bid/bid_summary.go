@@ -0,0 +1,90 @@
+package bid
+
+import (
+	"context"
+
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/configuration/logger"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/entity/bid_entity"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/internal_error"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.uber.org/zap"
+)
+
+// bidSummary acumula, por leilão, os lances de uma mesma gravação
+type bidSummary struct {
+	count   int64
+	highest int64
+}
+
+func (s *bidSummary) add(bid bid_entity.Bid) *bidSummary {
+	if s == nil {
+		s = &bidSummary{}
+	}
+
+	s.count++
+	if bid.Amount.Units > s.highest {
+		s.highest = bid.Amount.Units
+	}
+
+	return s
+}
+
+// BackfillBidSummary preenche highest_bid e bid_count nos leilões gravados
+// antes desses campos existirem, a partir dos lances válidos já gravados.
+func (bd *BidRepository) BackfillBidSummary(ctx context.Context) *internal_error.InternalError {
+	auctions := bd.AuctionRepository.Collection
+	missing := bson.M{"bid_count": bson.M{"$exists": false}}
+
+	count, err := auctions.CountDocuments(ctx, missing)
+	if err != nil {
+		logger.Error("Error trying to count auctions without bid summary", err)
+		return internal_error.NewInternalServerError("Error trying to backfill bid summary")
+	}
+	if count == 0 {
+		return nil
+	}
+
+	cursor, err := bd.Collection.Aggregate(ctx, bson.A{
+		bson.M{"$match": bson.M{"void": bson.M{"$ne": true}}},
+		bson.M{"$group": bson.M{
+			"_id":         "$auction_id",
+			"bid_count":   bson.M{"$sum": 1},
+			"highest_bid": bson.M{"$max": "$amount"},
+		}},
+	})
+	if err != nil {
+		logger.Error("Error trying to aggregate bid summary", err)
+		return internal_error.NewInternalServerError("Error trying to backfill bid summary")
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var summary struct {
+			AuctionId  string `bson:"_id"`
+			BidCount   int64  `bson:"bid_count"`
+			HighestBid int64  `bson:"highest_bid"`
+		}
+		if err := cursor.Decode(&summary); err != nil {
+			logger.Error("Error trying to decode bid summary", err)
+			return internal_error.NewInternalServerError("Error trying to backfill bid summary")
+		}
+
+		filter := bson.M{"_id": summary.AuctionId, "bid_count": bson.M{"$exists": false}}
+		update := bson.M{"$set": bson.M{"bid_count": summary.BidCount, "highest_bid": summary.HighestBid}}
+		if _, err := auctions.UpdateOne(ctx, filter, update); err != nil {
+			logger.Error("Error trying to backfill bid summary", err)
+			return internal_error.NewInternalServerError("Error trying to backfill bid summary")
+		}
+	}
+
+	// Leilões que nunca receberam lances
+	if _, err := auctions.UpdateMany(ctx, missing,
+		bson.M{"$set": bson.M{"bid_count": 0, "highest_bid": 0}}); err != nil {
+		logger.Error("Error trying to backfill bid summary", err)
+		return internal_error.NewInternalServerError("Error trying to backfill bid summary")
+	}
+
+	logger.Info("Auction bid summary backfilled", zap.Int64("auctions", count))
+
+	return nil
+}
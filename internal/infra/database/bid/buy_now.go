@@ -0,0 +1,125 @@
+package bid
+
+import (
+	"context"
+	"time"
+
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/entity/auction_entity"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/entity/bid_entity"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/entity/money_entity"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/internal_error"
+)
+
+// BuyNow encerra o leilão com o usuário como vencedor, no preço de compra
+// imediata ou, nos leilões holandeses, no preço corrente. A decisão usa a
+// mesma trava dos lances, então nenhum lance é aceito entre a verificação e o
+// fechamento; o lance da compra é gravado na transação que encerra o leilão.
+func (bd *BidRepository) BuyNow(
+	ctx context.Context, userId, auctionId string) (*bid_entity.BidResult, *internal_error.InternalError) {
+	unlock := bd.auctionLocks.lock(auctionId)
+	defer unlock()
+
+	auctionEntity, err := bd.findAuction(ctx, auctionId)
+	if err != nil {
+		if err.Err == "not_found" {
+			return bid_entity.NewRejectedBidResult(bid_entity.AuctionNotFound, err.Message), nil
+		}
+		return nil, err
+	}
+
+	if auctionEntity.Type != auction_entity.DutchAuction && !auctionEntity.HasBuyNow() {
+		return bid_entity.NewRejectedBidResult(
+			bid_entity.BuyNowUnavailable, "Auction has no buy now price"), nil
+	}
+
+	now := time.Now()
+	if rejection := checkAuctionOpen(auctionEntity, now); rejection != nil {
+		return rejection, nil
+	}
+
+	var bidEntity *bid_entity.Bid
+	if auctionEntity.Type == auction_entity.DutchAuction {
+		bidEntity, err = bid_entity.CreateDutchPurchaseBid(userId, auctionId, auctionEntity.CurrentPrice(now))
+	} else {
+		if rejection, err := bd.checkBuyNowAvailable(ctx, auctionEntity, userId); rejection != nil || err != nil {
+			return rejection, err
+		}
+		bidEntity, err = bid_entity.CreateBuyNowBid(userId, auctionId, auctionEntity.BuyNowPrice)
+	}
+	if err != nil {
+		return nil, err
+	}
+	bidEntity.Timestamp = now
+
+	// O lance é gravado depois que a transação marca o leilão como concluído
+	insert, err := bd.prepareInsert([]bid_entity.Bid{*bidEntity}, auction_entity.Completed)
+	if err != nil {
+		return nil, err
+	}
+
+	err = bd.AuctionRepository.CompleteAuction(ctx, auctionId, insert)
+	bd.forgetAuction(auctionId)
+	if err != nil {
+		if err.Err == "bad_request" {
+			// O fechamento automático chegou primeiro
+			return bid_entity.NewRejectedBidResult(
+				bid_entity.AuctionClosed, "Auction is already closed"), nil
+		}
+		return nil, err
+	}
+
+	return bid_entity.NewAcceptedBidResult([]bid_entity.Bid{*bidEntity}, true), nil
+}
+
+// checkBuyNowAvailable compara com o limite o maior valor já comprometido no
+// leilão: o maior lance ou, se for maior, o máximo de quem lidera a disputa
+// automática, que subiria até lá contra novos lances.
+func (bd *BidRepository) checkBuyNowAvailable(
+	ctx context.Context,
+	auctionEntity *auction_entity.Auction,
+	userId string) (*bid_entity.BidResult, *internal_error.InternalError) {
+	highestBid, err := bd.findHighestBid(ctx, auctionEntity.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	maxBids, err := bd.findMaxBids(ctx, auctionEntity.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	maxBids, err = bd.eligibleMaxBids(ctx, maxBids, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	highestAmount := money_entity.New(0, auctionEntity.Currency)
+	if highestBid != nil {
+		highestAmount = highestBid.Amount
+	}
+
+	leadingMaxBid := leadingMaxBid(maxBids)
+	if leadingMaxBid != nil && leadingMaxBid.MaxAmount.GreaterThan(highestAmount) {
+		highestAmount = leadingMaxBid.MaxAmount
+	}
+
+	hasBids := highestBid != nil || leadingMaxBid != nil
+	if !auctionEntity.BuyNowAvailable(highestAmount, hasBids, bd.buyNowThreshold) {
+		return bid_entity.NewRejectedBidResult(
+			bid_entity.BuyNowUnavailable, "Buy now is no longer available for this auction"), nil
+	}
+
+	return nil, nil
+}
+
+// leadingMaxBid é o maior máximo do leilão
+func leadingMaxBid(maxBids []bid_entity.MaxBid) *bid_entity.MaxBid {
+	var leading *bid_entity.MaxBid
+	for i := range maxBids {
+		if leading == nil || maxBids[i].MaxAmount.GreaterThan(leading.MaxAmount) {
+			leading = &maxBids[i]
+		}
+	}
+
+	return leading
+}
@@ -2,6 +2,8 @@ package bid
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"os"
 	"sync"
 	"time"
@@ -9,113 +11,477 @@ import (
 	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/configuration/logger"
 	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/entity/auction_entity"
 	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/entity/bid_entity"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/entity/money_entity"
 	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/infra/database/auction"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/infra/database/outbox"
 	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/internal_error"
 
+	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
 )
 
+// BidderChecker informa se o usuário ainda pode ter lances automáticos
+type BidderChecker func(ctx context.Context, userId string) (bool, *internal_error.InternalError)
+
 type BidEntityMongo struct {
-	Id        string  `bson:"_id"`
-	UserId    string  `bson:"user_id"`
-	AuctionId string  `bson:"auction_id"`
-	Amount    float64 `bson:"amount"`
-	Timestamp int64   `bson:"timestamp"`
+	Id            string `bson:"_id"`
+	UserId        string `bson:"user_id"`
+	AuctionId     string `bson:"auction_id"`
+	Amount        int64  `bson:"amount"`
+	Currency      string `bson:"currency"`
+	Timestamp     int64  `bson:"timestamp"` // nanossegundos
+	Automatic     bool   `bson:"automatic"`
+	BuyNow        bool   `bson:"buy_now,omitempty"`
+	Void          bool   `bson:"void,omitempty"`
+	DutchPurchase bool   `bson:"dutch_purchase,omitempty"`
+}
+
+func (bm *BidEntityMongo) amount() money_entity.Money {
+	return money_entity.New(bm.Amount, bm.Currency)
+}
+
+func (bm *BidEntityMongo) timestamp() time.Time {
+	return time.Unix(0, bm.Timestamp)
 }
 
 type BidRepository struct {
-	Collection            *mongo.Collection
-	AuctionRepository     *auction.AuctionRepository
-	auctionInterval       time.Duration
-	auctionStatusMap      map[string]auction_entity.AuctionStatus
-	auctionEndTimeMap     map[string]time.Time
-	auctionStatusMapMutex *sync.Mutex
-	auctionEndTimeMutex   *sync.Mutex
+	Collection          *mongo.Collection
+	MaxBidCollection    *mongo.Collection
+	AuctionRepository   *auction.AuctionRepository
+	Outbox              *outbox.OutboxRepository
+	auctionMap          map[string]auction_entity.Auction
+	auctionEndTimeMap   map[string]time.Time
+	maxBidMap           map[string][]bid_entity.MaxBid
+	auctionMapMutex     *sync.Mutex
+	auctionEndTimeMutex *sync.Mutex
+	maxBidMutex         *sync.Mutex
+	auctionLocks        *auctionLocks
+	batcher             *bidBatcher
+	bidderChecker       BidderChecker
+
+	softCloseWindow    time.Duration
+	softCloseExtension time.Duration
+
+	// Pontos-base do preço de compra imediata acima dos quais ela é desativada
+	buyNowThreshold int64
 }
 
 func NewBidRepository(database *mongo.Database, auctionRepository *auction.AuctionRepository) *BidRepository {
 	return &BidRepository{
-		auctionInterval:       getAuctionInterval(),
-		auctionStatusMap:      make(map[string]auction_entity.AuctionStatus),
-		auctionEndTimeMap:     make(map[string]time.Time),
-		auctionStatusMapMutex: &sync.Mutex{},
-		auctionEndTimeMutex:   &sync.Mutex{},
-		Collection:            database.Collection("bids"),
-		AuctionRepository:     auctionRepository,
+		auctionMap:          make(map[string]auction_entity.Auction),
+		auctionEndTimeMap:   make(map[string]time.Time),
+		maxBidMap:           make(map[string][]bid_entity.MaxBid),
+		auctionMapMutex:     &sync.Mutex{},
+		auctionEndTimeMutex: &sync.Mutex{},
+		maxBidMutex:         &sync.Mutex{},
+		auctionLocks:        newAuctionLocks(),
+		batcher:             newBidBatcher(database.Client()),
+		softCloseWindow:     getSoftCloseWindow(),
+		softCloseExtension:  getSoftCloseExtension(),
+		buyNowThreshold:     getBuyNowThreshold(),
+		Collection:          database.Collection("bids"),
+		MaxBidCollection:    database.Collection("max_bids"),
+		AuctionRepository:   auctionRepository,
+		Outbox:              auctionRepository.Outbox,
+	}
+}
+
+// CreateIndexes cria o índice da consulta de lances de um leilão, já na ordem
+// de maior lance e desempate pelo mais antigo, e o dos máximos por leilão.
+func (bd *BidRepository) CreateIndexes(ctx context.Context) *internal_error.InternalError {
+	_, err := bd.Collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "auction_id", Value: 1}, {Key: "amount", Value: -1}, {Key: "timestamp", Value: 1}},
+	})
+	if err != nil {
+		logger.Error("Error trying to create bid indexes", err)
+		return internal_error.NewInternalServerError("Error trying to create bid indexes")
+	}
+
+	_, err = bd.MaxBidCollection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "auction_id", Value: 1}},
+	})
+	if err != nil {
+		logger.Error("Error trying to create max bid indexes", err)
+		return internal_error.NewInternalServerError("Error trying to create max bid indexes")
 	}
+
+	return nil
 }
 
-func (bd *BidRepository) CreateBid(
+// AcceptBid valida o lance contra o estado atual do leilão e grava os lances
+// aceitos, junto com o máximo informado, antes de responder. As decisões de
+// um mesmo leilão são serializadas; leilões diferentes não esperam um pelo
+// outro e têm as gravações agrupadas em lote.
+func (bd *BidRepository) AcceptBid(
 	ctx context.Context,
-	bidEntities []bid_entity.Bid) *internal_error.InternalError {
-	var wg sync.WaitGroup
-	for _, bid := range bidEntities {
-		wg.Add(1)
-		go func(bidValue bid_entity.Bid) {
-			defer wg.Done()
-
-			bd.auctionStatusMapMutex.Lock()
-			auctionStatus, okStatus := bd.auctionStatusMap[bidValue.AuctionId]
-			bd.auctionStatusMapMutex.Unlock()
-
-			bd.auctionEndTimeMutex.Lock()
-			auctionEndTime, okEndTime := bd.auctionEndTimeMap[bidValue.AuctionId]
-			bd.auctionEndTimeMutex.Unlock()
-
-			bidEntityMongo := &BidEntityMongo{
-				Id:        bidValue.Id,
-				UserId:    bidValue.UserId,
-				AuctionId: bidValue.AuctionId,
-				Amount:    bidValue.Amount,
-				Timestamp: bidValue.Timestamp.Unix(),
-			}
+	bidEntity *bid_entity.Bid) (*bid_entity.BidResult, *internal_error.InternalError) {
+	unlock := bd.auctionLocks.lock(bidEntity.AuctionId)
+	defer unlock()
+
+	auctionEntity, err := bd.findAuction(ctx, bidEntity.AuctionId)
+	if err != nil {
+		if err.Err == "not_found" {
+			return bid_entity.NewRejectedBidResult(bid_entity.AuctionNotFound, err.Message), nil
+		}
+		return nil, err
+	}
 
-			if okEndTime && okStatus {
-				now := time.Now()
-				if auctionStatus == auction_entity.Completed || now.After(auctionEndTime) {
-					return
-				}
+	if auctionEntity.Type == auction_entity.DutchAuction {
+		return bid_entity.NewRejectedBidResult(bid_entity.BidsNotAccepted,
+			"Dutch auctions are won by buying at the current price"), nil
+	}
 
-				if _, err := bd.Collection.InsertOne(ctx, bidEntityMongo); err != nil {
-					logger.Error("Error trying to insert bid", err)
-					return
-				}
+	if bidEntity.Currency() != auctionEntity.Currency {
+		return bid_entity.NewRejectedBidResult(bid_entity.CurrencyMismatch,
+			fmt.Sprintf("Auction only accepts bids in %s", auctionEntity.Currency)), nil
+	}
 
-				return
-			}
+	if rejection := checkAuctionOpen(auctionEntity, bidEntity.Timestamp); rejection != nil {
+		return rejection, nil
+	}
+
+	highestBid, err := bd.findHighestBid(ctx, bidEntity.AuctionId)
+	if err != nil {
+		return nil, err
+	}
+
+	maxBids, err := bd.findMaxBids(ctx, bidEntity.AuctionId)
+	if err != nil {
+		return nil, err
+	}
+
+	minimumBid := func(highestAmount money_entity.Money) money_entity.Money {
+		return auctionEntity.MinimumBid(highestAmount, true)
+	}
+
+	var placedBids []bid_entity.Bid
+	leading := highestBid != nil && highestBid.UserId == bidEntity.UserId
+	maxBidOnly := leading && bidEntity.Amount.IsZero()
+	if maxBidOnly {
+		// O líder apenas ajusta o próprio máximo
+		if bidEntity.MaxAmount.LessThan(highestBid.Amount) {
+			return bid_entity.NewRejectedBidResult(bid_entity.AmountTooLow,
+				fmt.Sprintf("MaxAmount must be at least your current bid of %s", highestBid.Amount.Format())), nil
+		}
+	} else {
+		highestAmount := money_entity.New(0, auctionEntity.Currency)
+		if highestBid != nil {
+			highestAmount = highestBid.Amount
+		}
+
+		if bidEntity.Amount.IsZero() {
+			bidEntity.Amount = auctionEntity.MinimumBid(highestAmount, highestBid != nil)
+			bidEntity.Automatic = true
+		}
+
+		if !auctionEntity.AcceptsBidAmount(bidEntity.Amount, highestAmount, highestBid != nil) ||
+			(!bidEntity.MaxAmount.IsZero() && bidEntity.MaxAmount.LessThan(bidEntity.Amount)) {
+			return bid_entity.NewRejectedBidResult(bid_entity.AmountTooLow,
+				amountTooLowMessage(auctionEntity, highestBid)), nil
+		}
+
+		placedBids = append(placedBids, *bidEntity)
+		highestBid = bidEntity
+	}
+
+	var maxBid *bid_entity.MaxBid
+	if !bidEntity.MaxAmount.IsZero() {
+		maxBid = &bid_entity.MaxBid{
+			UserId:    bidEntity.UserId,
+			AuctionId: bidEntity.AuctionId,
+			MaxAmount: bidEntity.MaxAmount,
+			Timestamp: bidEntity.Timestamp,
+		}
+		maxBids = withMaxBid(maxBids, *maxBid)
+	}
+
+	proxyMaxBids, err := bd.eligibleMaxBids(ctx, maxBids, bidEntity.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	generatedBids := bid_entity.ResolveProxyBids(*highestBid, proxyMaxBids, minimumBid)
+	placedBids = append(placedBids, generatedBids...)
+	if len(generatedBids) > 0 {
+		highestBid = &generatedBids[len(generatedBids)-1]
+	}
+
+	// A prorrogação é gravada na mesma transação dos lances
+	var extendedEndTime time.Time
+	if len(placedBids) > 0 {
+		extendedEndTime = bd.softCloseEndTime(auctionEntity, bidEntity.Timestamp)
+	}
+
+	if err := bd.saveBids(ctx, placedBids, maxBid, extendedEndTime); err != nil {
+		if err.Err == "bad_request" {
+			// O leilão foi cancelado ou encerrado por outro caminho
+			bd.forgetAuction(bidEntity.AuctionId)
+			return bid_entity.NewRejectedBidResult(
+				bid_entity.AuctionClosed, "Auction is already closed"), nil
+		}
+		return nil, err
+	}
+
+	if !extendedEndTime.IsZero() {
+		bd.auctionEndTimeMutex.Lock()
+		bd.auctionEndTimeMap[bidEntity.AuctionId] = extendedEndTime
+		bd.auctionEndTimeMutex.Unlock()
+
+		bd.AuctionRepository.AuctionEndTimeExtended(bidEntity.AuctionId, extendedEndTime)
+	}
+
+	if maxBid != nil {
+		bd.maxBidMutex.Lock()
+		bd.maxBidMap[bidEntity.AuctionId] = maxBids
+		bd.maxBidMutex.Unlock()
+	}
+
+	bidResult := bid_entity.NewAcceptedBidResult(placedBids, highestBid.UserId == bidEntity.UserId)
+	if maxBidOnly {
+		bidResult = bid_entity.NewMaxBidUpdatedResult(placedBids, highestBid.UserId == bidEntity.UserId)
+	}
+	bidResult.ExtendedEndTime = extendedEndTime
+
+	return bidResult, nil
+}
+
+// checkAuctionOpen retorna a rejeição quando o leilão não aceita lances no momento informado
+func checkAuctionOpen(auctionEntity *auction_entity.Auction, bidTime time.Time) *bid_entity.BidResult {
+	switch {
+	case auctionEntity.Status == auction_entity.Scheduled || auctionEntity.Status == auction_entity.Draft:
+		return bid_entity.NewRejectedBidResult(
+			bid_entity.AuctionNotOpen, "Auction is not open for bids yet")
+	case auctionEntity.Status == auction_entity.Cancelled:
+		return bid_entity.NewRejectedBidResult(
+			bid_entity.AuctionClosed, "Auction was cancelled")
+	case auctionEntity.Status != auction_entity.Active || bidTime.After(auctionEntity.EndTime):
+		return bid_entity.NewRejectedBidResult(
+			bid_entity.AuctionClosed, "Auction is already closed")
+	}
+
+	return nil
+}
 
-			auctionEntity, err := bd.AuctionRepository.FindAuctionById(ctx, bidValue.AuctionId)
-			if err != nil {
-				logger.Error("Error trying to find auction by id", err)
-				return
+// softCloseEndTime aplica o soft close: lances aceitos na janela final
+// prorrogam o término do leilão. Retorna o novo término ou zero quando não há
+// prorrogação.
+func (bd *BidRepository) softCloseEndTime(auctionEntity *auction_entity.Auction, bidTime time.Time) time.Time {
+	if bd.softCloseWindow <= 0 || bd.softCloseExtension <= 0 ||
+		auctionEntity.EndTime.Sub(bidTime) > bd.softCloseWindow {
+		return time.Time{}
+	}
+
+	return auctionEntity.EndTime.Add(bd.softCloseExtension)
+}
+
+// saveBids grava os lances aceitos, o novo máximo do usuário e a prorrogação
+// do leilão (quando endTime não é zero) na mesma transação, junto com o lote.
+func (bd *BidRepository) saveBids(
+	ctx context.Context,
+	bidEntities []bid_entity.Bid,
+	maxBid *bid_entity.MaxBid,
+	endTime time.Time) *internal_error.InternalError {
+	if len(bidEntities) == 0 && maxBid == nil {
+		return nil
+	}
+
+	var insert func(ctx context.Context) error
+	if len(bidEntities) > 0 {
+		var internalErr *internal_error.InternalError
+		if insert, internalErr = bd.prepareInsert(bidEntities, auction_entity.Active); internalErr != nil {
+			return internalErr
+		}
+	}
+
+	err := bd.batcher.write(func(ctx context.Context) error {
+		if !endTime.IsZero() {
+			if err := bd.AuctionRepository.UpdateAuctionEndTime(ctx, bidEntities[0].AuctionId, endTime); err != nil {
+				return err
 			}
-			if auctionEntity.Status == auction_entity.Completed {
-				return
+		}
+
+		if maxBid != nil {
+			if err := bd.saveMaxBid(ctx, *maxBid); err != nil {
+				return err
 			}
+		}
+
+		if insert == nil {
+			return nil
+		}
+		return insert(ctx)
+	})
+	if errors.Is(err, auction.ErrAuctionNotOpen) {
+		return internal_error.NewBadRequestError("Auction is not active")
+	}
+	if err != nil {
+		logger.Error("Error trying to insert bids", err)
+		return internal_error.NewInternalServerError("Error trying to insert bids")
+	}
 
-			bd.auctionStatusMapMutex.Lock()
-			bd.auctionStatusMap[bidValue.AuctionId] = auctionEntity.Status
-			bd.auctionStatusMapMutex.Unlock()
+	return nil
+}
 
-			bd.auctionEndTimeMutex.Lock()
-			bd.auctionEndTimeMap[bidValue.AuctionId] = auctionEntity.Timestamp.Add(bd.auctionInterval)
-			bd.auctionEndTimeMutex.Unlock()
+// prepareInsert monta a gravação dos lances junto com o resumo do leilão e
+// os avisos do outbox, para ser executada dentro de uma transação. A gravação
+// falha com auction.ErrAuctionNotOpen se o status gravado do leilão não for status.
+func (bd *BidRepository) prepareInsert(
+	bidEntities []bid_entity.Bid,
+	status auction_entity.AuctionStatus) (func(ctx context.Context) error, *internal_error.InternalError) {
+	bidsMongo := make([]interface{}, 0, len(bidEntities))
+	summaries := make(map[string]*bidSummary)
+	for _, bidValue := range bidEntities {
+		summaries[bidValue.AuctionId] = summaries[bidValue.AuctionId].add(bidValue)
 
-			if _, err := bd.Collection.InsertOne(ctx, bidEntityMongo); err != nil {
-				logger.Error("Error trying to insert bid", err)
-				return
+		bidsMongo = append(bidsMongo, &BidEntityMongo{
+			Id:            bidValue.Id,
+			UserId:        bidValue.UserId,
+			AuctionId:     bidValue.AuctionId,
+			Amount:        bidValue.Amount.Units,
+			Currency:      bidValue.Amount.Currency,
+			Timestamp:     bidValue.Timestamp.UnixNano(),
+			Automatic:     bidValue.Automatic,
+			BuyNow:        bidValue.BuyNow,
+			DutchPurchase: bidValue.DutchPurchase,
+		})
+	}
+
+	messages, internalErr := bidPlacedMessages(bidEntities)
+	if internalErr != nil {
+		return nil, internalErr
+	}
+
+	return func(ctx context.Context) error {
+		opts := options.InsertMany().SetOrdered(false)
+		if _, err := bd.Collection.InsertMany(ctx, bidsMongo, opts); err != nil {
+			return err
+		}
+
+		for auctionId, summary := range summaries {
+			if err := bd.AuctionRepository.IncrementBidSummary(
+				ctx, auctionId, status, summary.count, summary.highest); err != nil {
+				return err
 			}
-		}(bid)
+		}
+
+		return bd.Outbox.SaveMessages(ctx, messages...)
+	}, nil
+}
+
+// findAuction usa o cache local do leilão; o horário de término fica em um
+// mapa próprio porque pode mudar depois do carregamento. Leilões que ainda
+// não abriram são sempre relidos, já que a abertura acontece no agendador.
+func (bd *BidRepository) findAuction(
+	ctx context.Context,
+	auctionId string) (*auction_entity.Auction, *internal_error.InternalError) {
+	bd.auctionMapMutex.Lock()
+	auctionEntity, okAuction := bd.auctionMap[auctionId]
+	bd.auctionMapMutex.Unlock()
+
+	bd.auctionEndTimeMutex.Lock()
+	auctionEndTime, okEndTime := bd.auctionEndTimeMap[auctionId]
+	bd.auctionEndTimeMutex.Unlock()
+
+	notOpen := auctionEntity.Status == auction_entity.Scheduled || auctionEntity.Status == auction_entity.Draft
+	if okAuction && okEndTime && !notOpen {
+		auctionEntity.EndTime = auctionEndTime
+		return &auctionEntity, nil
 	}
-	wg.Wait()
-	return nil
+
+	foundAuction, err := bd.AuctionRepository.FindAuctionById(ctx, auctionId)
+	if err != nil {
+		return nil, err
+	}
+
+	bd.auctionMapMutex.Lock()
+	bd.auctionMap[auctionId] = *foundAuction
+	bd.auctionMapMutex.Unlock()
+
+	bd.auctionEndTimeMutex.Lock()
+	bd.auctionEndTimeMap[auctionId] = foundAuction.EndTime
+	bd.auctionEndTimeMutex.Unlock()
+
+	return foundAuction, nil
+}
+
+// forgetAuction descarta o leilão do cache local para que o próximo lance
+// leia o estado atualizado do banco.
+func (bd *BidRepository) forgetAuction(auctionId string) {
+	bd.auctionMapMutex.Lock()
+	delete(bd.auctionMap, auctionId)
+	bd.auctionMapMutex.Unlock()
+
+	bd.auctionEndTimeMutex.Lock()
+	delete(bd.auctionEndTimeMap, auctionId)
+	bd.auctionEndTimeMutex.Unlock()
+}
+
+func (bd *BidRepository) findHighestBid(
+	ctx context.Context, auctionId string) (*bid_entity.Bid, *internal_error.InternalError) {
+	winningBid, err := bd.FindWinningBidByAuctionId(ctx, auctionId)
+	if err != nil {
+		if err.Err == "not_found" {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return winningBid, nil
+}
+
+func amountTooLowMessage(auctionEntity *auction_entity.Auction, highestBid *bid_entity.Bid) string {
+	if highestBid == nil {
+		return fmt.Sprintf("Amount must be at least the starting price of %s",
+			auctionEntity.MinimumBid(money_entity.New(0, auctionEntity.Currency), false).Format())
+	}
+
+	minimumBid := auctionEntity.MinimumBid(highestBid.Amount, true)
+	return fmt.Sprintf("Amount must be at least %s to outbid the current highest bid of %s",
+		minimumBid.Format(), highestBid.Amount.Format())
+}
+
+func getSoftCloseWindow() time.Duration {
+	softCloseWindow := os.Getenv("AUCTION_SOFT_CLOSE_WINDOW")
+	duration, err := time.ParseDuration(softCloseWindow)
+	if err != nil {
+		return 0
+	}
+
+	return duration
+}
+
+// getBuyNowThreshold lê AUCTION_BUY_NOW_THRESHOLD, um percentual do preço de
+// compra imediata. Sem configuração ou com um valor inválido, o primeiro lance
+// já desativa a compra.
+func getBuyNowThreshold() int64 {
+	value := os.Getenv("AUCTION_BUY_NOW_THRESHOLD")
+	if value == "" {
+		logger.Info("AUCTION_BUY_NOW_THRESHOLD is not set, the first bid disables buy now")
+		return 0
+	}
+
+	threshold, err := money_entity.ParseDecimal(value, 2)
+	if err != nil {
+		logger.Error("Invalid AUCTION_BUY_NOW_THRESHOLD, the first bid disables buy now", err)
+		return 0
+	}
+
+	if threshold > 10000 {
+		logger.Info("AUCTION_BUY_NOW_THRESHOLD is above 100, using 100",
+			zap.String("value", value))
+		return 10000
+	}
+
+	return threshold
 }
 
-func getAuctionInterval() time.Duration {
-	auctionInterval := os.Getenv("AUCTION_INTERVAL")
-	duration, err := time.ParseDuration(auctionInterval)
+func getSoftCloseExtension() time.Duration {
+	softCloseExtension := os.Getenv("AUCTION_SOFT_CLOSE_EXTENSION")
+	duration, err := time.ParseDuration(softCloseExtension)
 	if err != nil {
-		return time.Minute * 5
+		return 0
 	}
 
 	return duration
@@ -0,0 +1,114 @@
+package bid
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/entity/auction_entity"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/entity/bid_entity"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/entity/money_entity"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/internal_error"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetSoftCloseConfiguration(t *testing.T) {
+	tests := []struct {
+		name              string
+		windowEnv         string
+		extensionEnv      string
+		expectedWindow    time.Duration
+		expectedExtension time.Duration
+	}{
+		{
+			name:              "valid soft close configuration",
+			windowEnv:         "30s",
+			extensionEnv:      "1m",
+			expectedWindow:    30 * time.Second,
+			expectedExtension: time.Minute,
+		},
+		{
+			name:              "invalid values disable soft close",
+			windowEnv:         "invalid",
+			extensionEnv:      "",
+			expectedWindow:    0,
+			expectedExtension: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			os.Setenv("AUCTION_SOFT_CLOSE_WINDOW", tt.windowEnv)
+			os.Setenv("AUCTION_SOFT_CLOSE_EXTENSION", tt.extensionEnv)
+			defer func() {
+				os.Unsetenv("AUCTION_SOFT_CLOSE_WINDOW")
+				os.Unsetenv("AUCTION_SOFT_CLOSE_EXTENSION")
+			}()
+
+			assert.Equal(t, tt.expectedWindow, getSoftCloseWindow())
+			assert.Equal(t, tt.expectedExtension, getSoftCloseExtension())
+		})
+	}
+}
+
+func TestSoftCloseEndTime(t *testing.T) {
+	bidRepository := &BidRepository{
+		softCloseWindow:    30 * time.Second,
+		softCloseExtension: time.Minute,
+	}
+	now := time.Now()
+	auctionEntity := &auction_entity.Auction{
+		Id:      "auction-id",
+		EndTime: now.Add(time.Hour),
+	}
+
+	assert.True(t, bidRepository.softCloseEndTime(auctionEntity, now).IsZero())
+
+	auctionEntity.EndTime = now.Add(10 * time.Second)
+	assert.Equal(t, now.Add(70*time.Second), bidRepository.softCloseEndTime(auctionEntity, now))
+}
+
+func TestEligibleMaxBidsSkipsInactiveUsers(t *testing.T) {
+	bidRepository := &BidRepository{}
+	bidRepository.CheckBidder(func(ctx context.Context, userId string) (bool, *internal_error.InternalError) {
+		return userId != "suspended", nil
+	})
+	maxBids := []bid_entity.MaxBid{
+		{UserId: "active", MaxAmount: money_entity.New(10000, "BRL")},
+		{UserId: "suspended", MaxAmount: money_entity.New(50000, "BRL")},
+		{UserId: "bidder", MaxAmount: money_entity.New(20000, "BRL")},
+	}
+
+	eligible, err := bidRepository.eligibleMaxBids(context.Background(), maxBids, "bidder")
+
+	assert.Nil(t, err)
+	assert.Equal(t, []bid_entity.MaxBid{maxBids[0], maxBids[2]}, eligible)
+}
+
+func TestGetBuyNowThreshold(t *testing.T) {
+	tests := map[string]int64{
+		"":        0,
+		"invalid": 0,
+		"-10":     0,
+		"75.5":    7550,
+		"150":     10000,
+	}
+
+	for value, expected := range tests {
+		os.Setenv("AUCTION_BUY_NOW_THRESHOLD", value)
+		assert.Equal(t, expected, getBuyNowThreshold(), value)
+	}
+	os.Unsetenv("AUCTION_BUY_NOW_THRESHOLD")
+}
+
+func TestLeadingMaxBid(t *testing.T) {
+	assert.Nil(t, leadingMaxBid(nil))
+
+	maxBids := []bid_entity.MaxBid{
+		{UserId: "first", MaxAmount: money_entity.New(10000, "BRL")},
+		{UserId: "leader", MaxAmount: money_entity.New(30000, "BRL")},
+		{UserId: "third", MaxAmount: money_entity.New(20000, "BRL")},
+	}
+	assert.Equal(t, "leader", leadingMaxBid(maxBids).UserId)
+}
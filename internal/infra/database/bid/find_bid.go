@@ -2,13 +2,14 @@ package bid
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"time"
 
 	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/configuration/logger"
 	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/entity/bid_entity"
 	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/internal_error"
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
@@ -35,11 +36,15 @@ func (bd *BidRepository) FindBidByAuctionId(
 	var bidEntities []bid_entity.Bid
 	for _, bidEntityMongo := range bidEntitiesMongo {
 		bidEntities = append(bidEntities, bid_entity.Bid{
-			Id:        bidEntityMongo.Id,
-			UserId:    bidEntityMongo.UserId,
-			AuctionId: bidEntityMongo.AuctionId,
-			Amount:    bidEntityMongo.Amount,
-			Timestamp: time.Unix(bidEntityMongo.Timestamp, 0),
+			Id:            bidEntityMongo.Id,
+			UserId:        bidEntityMongo.UserId,
+			AuctionId:     bidEntityMongo.AuctionId,
+			Amount:        bidEntityMongo.amount(),
+			Timestamp:     bidEntityMongo.timestamp(),
+			Automatic:     bidEntityMongo.Automatic,
+			BuyNow:        bidEntityMongo.BuyNow,
+			DutchPurchase: bidEntityMongo.DutchPurchase,
+			Void:          bidEntityMongo.Void,
 		})
 	}
 
@@ -48,20 +53,79 @@ func (bd *BidRepository) FindBidByAuctionId(
 
 func (bd *BidRepository) FindWinningBidByAuctionId(
 	ctx context.Context, auctionId string) (*bid_entity.Bid, *internal_error.InternalError) {
-	filter := bson.M{"auction_id": auctionId}
+	filter := bson.M{"auction_id": auctionId, "void": bson.M{"$ne": true}}
 
 	var bidEntityMongo BidEntityMongo
-	opts := options.FindOne().SetSort(bson.D{{Key: "amount", Value: -1}})
+	// Empates no valor são decididos pelo lance mais antigo
+	opts := options.FindOne().SetSort(bson.D{
+		{Key: "amount", Value: -1},
+		{Key: "timestamp", Value: 1},
+	})
 	if err := bd.Collection.FindOne(ctx, filter, opts).Decode(&bidEntityMongo); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, internal_error.NewNotFoundError(
+				fmt.Sprintf("No bids found for auctionId %s", auctionId))
+		}
+
 		logger.Error("Error trying to find the auction winner", err)
 		return nil, internal_error.NewInternalServerError("Error trying to find the auction winner")
 	}
 
 	return &bid_entity.Bid{
-		Id:        bidEntityMongo.Id,
-		UserId:    bidEntityMongo.UserId,
-		AuctionId: bidEntityMongo.AuctionId,
-		Amount:    bidEntityMongo.Amount,
-		Timestamp: time.Unix(bidEntityMongo.Timestamp, 0),
+		Id:            bidEntityMongo.Id,
+		UserId:        bidEntityMongo.UserId,
+		AuctionId:     bidEntityMongo.AuctionId,
+		Amount:        bidEntityMongo.amount(),
+		Timestamp:     bidEntityMongo.timestamp(),
+		Automatic:     bidEntityMongo.Automatic,
+		BuyNow:        bidEntityMongo.BuyNow,
+		DutchPurchase: bidEntityMongo.DutchPurchase,
 	}, nil
 }
+
+// FindWinningBidsByAuctionIds busca numa única consulta o lance vencedor de
+// cada leilão informado. Leilões sem lances ficam fora do mapa.
+func (bd *BidRepository) FindWinningBidsByAuctionIds(
+	ctx context.Context, auctionIds []string) (map[string]bid_entity.Bid, *internal_error.InternalError) {
+	winningBids := make(map[string]bid_entity.Bid)
+	if len(auctionIds) == 0 {
+		return winningBids, nil
+	}
+
+	// Mesma ordem de FindWinningBidByAuctionId: maior valor, depois o mais antigo
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"auction_id": bson.M{"$in": auctionIds}, "void": bson.M{"$ne": true}}}},
+		{{Key: "$sort", Value: bson.D{{Key: "amount", Value: -1}, {Key: "timestamp", Value: 1}}}},
+		{{Key: "$group", Value: bson.M{"_id": "$auction_id", "bid": bson.M{"$first": "$$ROOT"}}}},
+	}
+
+	cursor, err := bd.Collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		logger.Error("Error trying to find the auction winners", err)
+		return nil, internal_error.NewInternalServerError("Error trying to find the auction winners")
+	}
+	defer cursor.Close(ctx)
+
+	var results []struct {
+		Bid BidEntityMongo `bson:"bid"`
+	}
+	if err := cursor.All(ctx, &results); err != nil {
+		logger.Error("Error trying to decode the auction winners", err)
+		return nil, internal_error.NewInternalServerError("Error trying to find the auction winners")
+	}
+
+	for _, result := range results {
+		winningBids[result.Bid.AuctionId] = bid_entity.Bid{
+			Id:            result.Bid.Id,
+			UserId:        result.Bid.UserId,
+			AuctionId:     result.Bid.AuctionId,
+			Amount:        result.Bid.amount(),
+			Timestamp:     result.Bid.timestamp(),
+			Automatic:     result.Bid.Automatic,
+			BuyNow:        result.Bid.BuyNow,
+			DutchPurchase: result.Bid.DutchPurchase,
+		}
+	}
+
+	return winningBids, nil
+}
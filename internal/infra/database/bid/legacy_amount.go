@@ -0,0 +1,64 @@
+package bid
+
+import (
+	"context"
+
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/configuration/logger"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/entity/money_entity"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/internal_error"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.uber.org/zap"
+)
+
+// Os valores antigos eram reais em float64; em centavos, a escala é 100. O
+// incremento percentual também é multiplicado por 100, virando pontos-base.
+const legacyAmountScale = 100
+
+// MigrateLegacyAmounts converte para unidades menores os valores gravados em
+// float64 antes dos leilões declararem moeda, e marca esses documentos como
+// em reais. Documentos já convertidos não são alterados, então a migração
+// pode rodar a cada inicialização. Deve rodar antes de BackfillBidSummary.
+func (bd *BidRepository) MigrateLegacyAmounts(ctx context.Context) *internal_error.InternalError {
+	migrations := []struct {
+		collection *mongo.Collection
+		fields     []string
+	}{
+		{bd.AuctionRepository.Collection, []string{"starting_price", "min_increment", "reserve_price", "highest_bid"}},
+		{bd.Collection, []string{"amount"}},
+		{bd.MaxBidCollection, []string{"max_amount"}},
+	}
+
+	for _, migration := range migrations {
+		var migrated int64
+		for _, field := range migration.fields {
+			filter := bson.M{field: bson.M{"$type": "double"}}
+			update := bson.A{bson.M{"$set": bson.M{field: bson.M{"$toLong": bson.M{"$round": bson.A{
+				bson.M{"$multiply": bson.A{"$" + field, legacyAmountScale}}, 0,
+			}}}}}}
+
+			result, err := migration.collection.UpdateMany(ctx, filter, update)
+			if err != nil {
+				logger.Error("Error trying to migrate legacy amounts", err)
+				return internal_error.NewInternalServerError("Error trying to migrate legacy amounts")
+			}
+			migrated += result.ModifiedCount
+		}
+
+		if _, err := migration.collection.UpdateMany(ctx,
+			bson.M{"currency": bson.M{"$exists": false}},
+			bson.M{"$set": bson.M{"currency": money_entity.DefaultCurrency}}); err != nil {
+			logger.Error("Error trying to migrate legacy amounts", err)
+			return internal_error.NewInternalServerError("Error trying to migrate legacy amounts")
+		}
+
+		if migrated > 0 {
+			logger.Info("Legacy amounts migrated",
+				zap.String("collection", migration.collection.Name()),
+				zap.Int64("fields", migrated),
+			)
+		}
+	}
+
+	return nil
+}
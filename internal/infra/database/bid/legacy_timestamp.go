@@ -0,0 +1,43 @@
+package bid
+
+import (
+	"context"
+	"time"
+
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/configuration/logger"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/internal_error"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.uber.org/zap"
+)
+
+// Horários em segundos ficam abaixo deste limite até o ano 33658; em
+// nanossegundos, ele é ultrapassado poucos minutos depois de 1970
+const legacyTimestampLimit = 1e12
+
+// MigrateLegacyTimestamps converte para nanossegundos os horários gravados em
+// segundos, que empatavam lances e máximos do mesmo segundo no desempate
+// pelo mais antigo. Documentos já convertidos não são alterados.
+func (bd *BidRepository) MigrateLegacyTimestamps(ctx context.Context) *internal_error.InternalError {
+	for _, collection := range []*mongo.Collection{bd.Collection, bd.MaxBidCollection} {
+		filter := bson.M{"timestamp": bson.M{"$lt": legacyTimestampLimit}}
+		update := bson.A{bson.M{"$set": bson.M{"timestamp": bson.M{"$toLong": bson.M{
+			"$multiply": bson.A{"$timestamp", int64(time.Second)},
+		}}}}}
+
+		result, err := collection.UpdateMany(ctx, filter, update)
+		if err != nil {
+			logger.Error("Error trying to migrate legacy timestamps", err)
+			return internal_error.NewInternalServerError("Error trying to migrate legacy timestamps")
+		}
+
+		if result.ModifiedCount > 0 {
+			logger.Info("Legacy timestamps migrated",
+				zap.String("collection", collection.Name()),
+				zap.Int64("documents", result.ModifiedCount),
+			)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,126 @@
+package bid
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/configuration/logger"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/entity/bid_entity"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/entity/money_entity"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/internal_error"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type MaxBidEntityMongo struct {
+	Id        string `bson:"_id"`
+	UserId    string `bson:"user_id"`
+	AuctionId string `bson:"auction_id"`
+	MaxAmount int64  `bson:"max_amount"`
+	Currency  string `bson:"currency"`
+	Timestamp int64  `bson:"timestamp"` // nanossegundos
+}
+
+func (bd *BidRepository) findMaxBids(
+	ctx context.Context, auctionId string) ([]bid_entity.MaxBid, *internal_error.InternalError) {
+	bd.maxBidMutex.Lock()
+	maxBids, ok := bd.maxBidMap[auctionId]
+	bd.maxBidMutex.Unlock()
+
+	if ok {
+		return maxBids, nil
+	}
+
+	cursor, err := bd.MaxBidCollection.Find(ctx, bson.M{"auction_id": auctionId})
+	if err != nil {
+		logger.Error(fmt.Sprintf("Error trying to find max bids by auctionId %s", auctionId), err)
+		return nil, internal_error.NewInternalServerError("Error trying to find max bids")
+	}
+	defer cursor.Close(ctx)
+
+	var maxBidsMongo []MaxBidEntityMongo
+	if err := cursor.All(ctx, &maxBidsMongo); err != nil {
+		logger.Error(fmt.Sprintf("Error trying to decode max bids by auctionId %s", auctionId), err)
+		return nil, internal_error.NewInternalServerError("Error trying to find max bids")
+	}
+
+	maxBids = []bid_entity.MaxBid{}
+	for _, maxBidMongo := range maxBidsMongo {
+		maxBids = append(maxBids, bid_entity.MaxBid{
+			UserId:    maxBidMongo.UserId,
+			AuctionId: maxBidMongo.AuctionId,
+			MaxAmount: money_entity.New(maxBidMongo.MaxAmount, maxBidMongo.Currency),
+			Timestamp: time.Unix(0, maxBidMongo.Timestamp),
+		})
+	}
+
+	bd.maxBidMutex.Lock()
+	bd.maxBidMap[auctionId] = maxBids
+	bd.maxBidMutex.Unlock()
+
+	return maxBids, nil
+}
+
+func (bd *BidRepository) saveMaxBid(ctx context.Context, maxBid bid_entity.MaxBid) error {
+	maxBidMongo := MaxBidEntityMongo{
+		Id:        fmt.Sprintf("%s:%s", maxBid.AuctionId, maxBid.UserId),
+		UserId:    maxBid.UserId,
+		AuctionId: maxBid.AuctionId,
+		MaxAmount: maxBid.MaxAmount.Units,
+		Currency:  maxBid.MaxAmount.Currency,
+		Timestamp: maxBid.Timestamp.UnixNano(),
+	}
+
+	opts := options.Replace().SetUpsert(true)
+	_, err := bd.MaxBidCollection.ReplaceOne(ctx, bson.M{"_id": maxBidMongo.Id}, maxBidMongo, opts)
+	return err
+}
+
+// CheckBidder define como saber se o dono de um máximo gravado continua ativo.
+// Sem verificador, todos os máximos participam da disputa automática.
+func (bd *BidRepository) CheckBidder(checker BidderChecker) {
+	bd.bidderChecker = checker
+}
+
+// eligibleMaxBids descarta os máximos de usuários suspensos ou banidos. Eles
+// continuam gravados e voltam a valer se o usuário for reativado.
+func (bd *BidRepository) eligibleMaxBids(
+	ctx context.Context,
+	maxBids []bid_entity.MaxBid,
+	bidderId string) ([]bid_entity.MaxBid, *internal_error.InternalError) {
+	if bd.bidderChecker == nil {
+		return maxBids, nil
+	}
+
+	var eligible []bid_entity.MaxBid
+	for _, maxBid := range maxBids {
+		// Quem está dando o lance já foi verificado pelo caso de uso
+		if maxBid.UserId != bidderId {
+			active, err := bd.bidderChecker(ctx, maxBid.UserId)
+			if err != nil {
+				return nil, err
+			}
+			if !active {
+				continue
+			}
+		}
+
+		eligible = append(eligible, maxBid)
+	}
+
+	return eligible, nil
+}
+
+// withMaxBid devolve uma nova lista de máximos do leilão com o máximo do
+// usuário substituído
+func withMaxBid(maxBids []bid_entity.MaxBid, maxBid bid_entity.MaxBid) []bid_entity.MaxBid {
+	var updated []bid_entity.MaxBid
+	for _, current := range maxBids {
+		if current.UserId != maxBid.UserId {
+			updated = append(updated, current)
+		}
+	}
+
+	return append(updated, maxBid)
+}
@@ -0,0 +1,46 @@
+package bid
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/entity/bid_entity"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/entity/outbox_entity"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/internal_error"
+)
+
+type bidPlacedPayload struct {
+	Id            string      `json:"id"`
+	UserId        string      `json:"user_id"`
+	AuctionId     string      `json:"auction_id"`
+	Amount        json.Number `json:"amount"`
+	Currency      string      `json:"currency"`
+	Timestamp     time.Time   `json:"timestamp"`
+	Automatic     bool        `json:"automatic"`
+	BuyNow        bool        `json:"buy_now,omitempty"`
+	DutchPurchase bool        `json:"dutch_purchase,omitempty"`
+}
+
+func bidPlacedMessages(bidEntities []bid_entity.Bid) ([]outbox_entity.Message, *internal_error.InternalError) {
+	messages := make([]outbox_entity.Message, 0, len(bidEntities))
+	for _, bidValue := range bidEntities {
+		message, err := outbox_entity.NewMessage(outbox_entity.BidPlacedEvent, bidValue.Id, bidPlacedPayload{
+			Id:            bidValue.Id,
+			UserId:        bidValue.UserId,
+			AuctionId:     bidValue.AuctionId,
+			Amount:        json.Number(bidValue.Amount.String()),
+			Currency:      bidValue.Amount.Currency,
+			Timestamp:     bidValue.Timestamp,
+			Automatic:     bidValue.Automatic,
+			BuyNow:        bidValue.BuyNow,
+			DutchPurchase: bidValue.DutchPurchase,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		messages = append(messages, *message)
+	}
+
+	return messages, nil
+}
@@ -0,0 +1,54 @@
+package bid
+
+import (
+	"context"
+
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/configuration/logger"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/internal_error"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.uber.org/zap"
+)
+
+// CancelAuction cancela o leilão e, na mesma transação, anula seus lances e
+// descarta os lances automáticos pendentes. Usa a mesma trava dos lances; um
+// lance de outra instância é barrado pelo status gravado. Com withoutBids, o
+// cancelamento só vale se o leilão não tiver lances.
+func (bd *BidRepository) CancelAuction(
+	ctx context.Context, auctionId string, withoutBids bool) *internal_error.InternalError {
+	unlock := bd.auctionLocks.lock(auctionId)
+	defer unlock()
+
+	var voided int64
+	err := bd.AuctionRepository.CancelAuction(ctx, auctionId, withoutBids, func(ctx context.Context) error {
+		result, err := bd.Collection.UpdateMany(ctx,
+			bson.M{"auction_id": auctionId},
+			bson.M{"$set": bson.M{"void": true}})
+		if err != nil {
+			return err
+		}
+		voided = result.ModifiedCount
+
+		if err := bd.AuctionRepository.ResetBidSummary(ctx, auctionId); err != nil {
+			return err
+		}
+
+		_, err = bd.MaxBidCollection.DeleteMany(ctx, bson.M{"auction_id": auctionId})
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	bd.maxBidMutex.Lock()
+	delete(bd.maxBidMap, auctionId)
+	bd.maxBidMutex.Unlock()
+
+	bd.forgetAuction(auctionId)
+
+	logger.Info("Auction cancelled and its bids voided",
+		zap.String("auction_id", auctionId),
+		zap.Int64("bids", voided),
+	)
+
+	return nil
+}
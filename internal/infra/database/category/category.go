@@ -0,0 +1,166 @@
+package category
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/configuration/logger"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/entity/category_entity"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/internal_error"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type CategoryEntityMongo struct {
+	Id        string `bson:"_id"`
+	Name      string `bson:"name"`
+	Slug      string `bson:"slug"`
+	ParentId  string `bson:"parent_id,omitempty"`
+	CreatedAt int64  `bson:"created_at"`
+}
+
+type CategoryRepository struct {
+	Collection *mongo.Collection
+}
+
+func NewCategoryRepository(database *mongo.Database) *CategoryRepository {
+	return &CategoryRepository{
+		Collection: database.Collection("categories"),
+	}
+}
+
+// CreateIndexes garante a unicidade do slug, usado nas URLs e nos filtros.
+func (cr *CategoryRepository) CreateIndexes(ctx context.Context) error {
+	_, err := cr.Collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "slug", Value: 1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key: "parent_id", Value: 1}}},
+	})
+
+	return err
+}
+
+func (cr *CategoryRepository) CreateCategory(
+	ctx context.Context, categoryEntity *category_entity.Category) *internal_error.InternalError {
+	if _, err := cr.Collection.InsertOne(ctx, toCategoryEntityMongo(categoryEntity)); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return internal_error.NewBadRequestError("Category slug is already in use")
+		}
+
+		logger.Error("Error trying to insert category", err)
+		return internal_error.NewInternalServerError("Error trying to insert category")
+	}
+
+	return nil
+}
+
+// FindCategories devolve a árvore inteira; ela é pequena o bastante para ser
+// montada em memória por quem consulta.
+func (cr *CategoryRepository) FindCategories(
+	ctx context.Context) ([]category_entity.Category, *internal_error.InternalError) {
+	opts := options.Find().SetSort(bson.D{{Key: "name", Value: 1}})
+
+	cursor, err := cr.Collection.Find(ctx, bson.M{}, opts)
+	if err != nil {
+		logger.Error("Error finding categories", err)
+		return nil, internal_error.NewInternalServerError("Error finding categories")
+	}
+	defer cursor.Close(ctx)
+
+	var categoriesMongo []CategoryEntityMongo
+	if err := cursor.All(ctx, &categoriesMongo); err != nil {
+		logger.Error("Error decoding categories", err)
+		return nil, internal_error.NewInternalServerError("Error decoding categories")
+	}
+
+	categories := make([]category_entity.Category, 0, len(categoriesMongo))
+	for _, category := range categoriesMongo {
+		categories = append(categories, category.toEntity())
+	}
+
+	return categories, nil
+}
+
+func (cr *CategoryRepository) FindCategoryById(
+	ctx context.Context, id string) (*category_entity.Category, *internal_error.InternalError) {
+	filter := bson.M{"_id": id}
+
+	var categoryEntityMongo CategoryEntityMongo
+	if err := cr.Collection.FindOne(ctx, filter).Decode(&categoryEntityMongo); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, internal_error.NewNotFoundError(
+				fmt.Sprintf("Category not found with this id = %s", id))
+		}
+
+		logger.Error("Error trying to find category by id", err)
+		return nil, internal_error.NewInternalServerError("Error trying to find category by id")
+	}
+
+	categoryEntity := categoryEntityMongo.toEntity()
+	return &categoryEntity, nil
+}
+
+func (cr *CategoryRepository) UpdateCategory(
+	ctx context.Context, categoryEntity *category_entity.Category) *internal_error.InternalError {
+	filter := bson.M{"_id": categoryEntity.Id}
+	update := bson.M{"$set": bson.M{
+		"name":      categoryEntity.Name,
+		"slug":      categoryEntity.Slug,
+		"parent_id": categoryEntity.ParentId,
+	}}
+
+	result, err := cr.Collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return internal_error.NewBadRequestError("Category slug is already in use")
+		}
+
+		logger.Error("Error trying to update category", err)
+		return internal_error.NewInternalServerError("Error trying to update category")
+	}
+
+	if result.MatchedCount == 0 {
+		return internal_error.NewNotFoundError(
+			fmt.Sprintf("Category not found with this id = %s", categoryEntity.Id))
+	}
+
+	return nil
+}
+
+func (cr *CategoryRepository) DeleteCategory(
+	ctx context.Context, id string) *internal_error.InternalError {
+	result, err := cr.Collection.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		logger.Error("Error trying to delete category", err)
+		return internal_error.NewInternalServerError("Error trying to delete category")
+	}
+
+	if result.DeletedCount == 0 {
+		return internal_error.NewNotFoundError(
+			fmt.Sprintf("Category not found with this id = %s", id))
+	}
+
+	return nil
+}
+
+func toCategoryEntityMongo(categoryEntity *category_entity.Category) *CategoryEntityMongo {
+	return &CategoryEntityMongo{
+		Id:        categoryEntity.Id,
+		Name:      categoryEntity.Name,
+		Slug:      categoryEntity.Slug,
+		ParentId:  categoryEntity.ParentId,
+		CreatedAt: categoryEntity.CreatedAt.Unix(),
+	}
+}
+
+func (cm *CategoryEntityMongo) toEntity() category_entity.Category {
+	return category_entity.Category{
+		Id:        cm.Id,
+		Name:      cm.Name,
+		Slug:      cm.Slug,
+		ParentId:  cm.ParentId,
+		CreatedAt: time.Unix(cm.CreatedAt, 0),
+	}
+}
@@ -0,0 +1,152 @@
+package outbox
+
+import (
+	"context"
+	"time"
+
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/configuration/logger"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/entity/outbox_entity"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/internal_error"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type MessageEntityMongo struct {
+	Id            string                      `bson:"_id"`
+	EventType     string                      `bson:"event_type"`
+	AggregateId   string                      `bson:"aggregate_id"`
+	Payload       string                      `bson:"payload"`
+	Status        outbox_entity.MessageStatus `bson:"status"`
+	Attempts      int                         `bson:"attempts"`
+	LastError     string                      `bson:"last_error,omitempty"`
+	CreatedAt     int64                       `bson:"created_at"`
+	NextAttemptAt int64                       `bson:"next_attempt_at"`
+	DeliveredAt   int64                       `bson:"delivered_at,omitempty"`
+}
+
+type OutboxRepository struct {
+	Collection *mongo.Collection
+}
+
+func NewOutboxRepository(database *mongo.Database) *OutboxRepository {
+	return &OutboxRepository{
+		Collection: database.Collection("outbox"),
+	}
+}
+
+// SaveMessages deve receber o mesmo contexto (sessão) da alteração que gerou
+// as mensagens. Mensagens já gravadas com o mesmo Id são mantidas como estão:
+// a gravação é um upsert que só insere, porque um erro de chave duplicada
+// abortaria a transação em andamento.
+func (or *OutboxRepository) SaveMessages(ctx context.Context, messages ...outbox_entity.Message) error {
+	if len(messages) == 0 {
+		return nil
+	}
+
+	models := make([]mongo.WriteModel, 0, len(messages))
+	for _, message := range messages {
+		// O _id vem do filtro do upsert
+		models = append(models, mongo.NewUpdateOneModel().
+			SetFilter(bson.M{"_id": message.Id}).
+			SetUpdate(bson.M{"$setOnInsert": bson.M{
+				"event_type":      message.EventType,
+				"aggregate_id":    message.AggregateId,
+				"payload":         string(message.Payload),
+				"status":          message.Status,
+				"attempts":        0,
+				"created_at":      message.CreatedAt.UnixMilli(),
+				"next_attempt_at": message.NextAttemptAt.UnixMilli(),
+			}}).
+			SetUpsert(true))
+	}
+
+	_, err := or.Collection.BulkWrite(ctx, models, options.BulkWrite().SetOrdered(false))
+	return err
+}
+
+func (or *OutboxRepository) FindPendingMessages(
+	ctx context.Context,
+	now time.Time,
+	limit int64) ([]outbox_entity.Message, *internal_error.InternalError) {
+	filter := bson.M{
+		"status":          outbox_entity.Pending,
+		"next_attempt_at": bson.M{"$lte": now.UnixMilli()},
+	}
+	opts := options.Find().
+		SetSort(bson.D{{Key: "created_at", Value: 1}}).
+		SetLimit(limit)
+
+	cursor, err := or.Collection.Find(ctx, filter, opts)
+	if err != nil {
+		logger.Error("Error trying to find pending outbox messages", err)
+		return nil, internal_error.NewInternalServerError("Error trying to find pending outbox messages")
+	}
+	defer cursor.Close(ctx)
+
+	var messagesMongo []MessageEntityMongo
+	if err := cursor.All(ctx, &messagesMongo); err != nil {
+		logger.Error("Error trying to decode outbox messages", err)
+		return nil, internal_error.NewInternalServerError("Error trying to decode outbox messages")
+	}
+
+	messages := make([]outbox_entity.Message, 0, len(messagesMongo))
+	for _, messageMongo := range messagesMongo {
+		messages = append(messages, messageMongo.toEntity())
+	}
+
+	return messages, nil
+}
+
+func (or *OutboxRepository) MarkDelivered(ctx context.Context, id string) *internal_error.InternalError {
+	filter := bson.M{"_id": id}
+	update := bson.M{"$set": bson.M{
+		"status":       outbox_entity.Delivered,
+		"delivered_at": time.Now().UnixMilli(),
+	}}
+
+	if _, err := or.Collection.UpdateOne(ctx, filter, update); err != nil {
+		logger.Error("Error trying to mark outbox message as delivered", err)
+		return internal_error.NewInternalServerError("Error trying to mark outbox message as delivered")
+	}
+
+	return nil
+}
+
+func (or *OutboxRepository) UpdateFailedMessage(
+	ctx context.Context, message outbox_entity.Message) *internal_error.InternalError {
+	filter := bson.M{"_id": message.Id}
+	update := bson.M{"$set": bson.M{
+		"status":          message.Status,
+		"attempts":        message.Attempts,
+		"last_error":      message.LastError,
+		"next_attempt_at": message.NextAttemptAt.UnixMilli(),
+	}}
+
+	if _, err := or.Collection.UpdateOne(ctx, filter, update); err != nil {
+		logger.Error("Error trying to update outbox message", err)
+		return internal_error.NewInternalServerError("Error trying to update outbox message")
+	}
+
+	return nil
+}
+
+func (mm *MessageEntityMongo) toEntity() outbox_entity.Message {
+	message := outbox_entity.Message{
+		Id:            mm.Id,
+		EventType:     mm.EventType,
+		AggregateId:   mm.AggregateId,
+		Payload:       []byte(mm.Payload),
+		Status:        mm.Status,
+		Attempts:      mm.Attempts,
+		LastError:     mm.LastError,
+		CreatedAt:     time.UnixMilli(mm.CreatedAt),
+		NextAttemptAt: time.UnixMilli(mm.NextAttemptAt),
+	}
+	if mm.DeliveredAt > 0 {
+		message.DeliveredAt = time.UnixMilli(mm.DeliveredAt)
+	}
+
+	return message
+}
@@ -0,0 +1,149 @@
+package user
+
+import (
+	"context"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/configuration/logger"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/entity/user_entity"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/internal_error"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type UserEntityMongo struct {
+	Id          string                 `bson:"_id"`
+	Name        string                 `bson:"name"`
+	Email       string                 `bson:"email,omitempty"`
+	DisplayName string                 `bson:"display_name"`
+	Status      user_entity.UserStatus `bson:"status"`
+	CreatedAt   int64                  `bson:"created_at"`
+}
+
+type cachedUser struct {
+	user      user_entity.User
+	expiresAt time.Time
+}
+
+// UserRepository mantém os usuários consultados em cache, já que cada lance
+// verifica o autor. Alterações feitas por este repositório atualizam o cache;
+// as demais são percebidas quando a entrada expira.
+type UserRepository struct {
+	Collection *mongo.Collection
+
+	userCache      map[string]cachedUser
+	userCacheMutex *sync.Mutex
+	cacheTTL       time.Duration
+}
+
+func NewUserRepository(database *mongo.Database) *UserRepository {
+	return &UserRepository{
+		Collection:     database.Collection("users"),
+		userCache:      make(map[string]cachedUser),
+		userCacheMutex: &sync.Mutex{},
+		cacheTTL:       getUserCacheTTL(),
+	}
+}
+
+// CreateIndexes garante a unicidade do email. Usuários antigos, sem email,
+// ficam fora do índice.
+func (ur *UserRepository) CreateIndexes(ctx context.Context) error {
+	_, err := ur.Collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "email", Value: 1}},
+		Options: options.Index().
+			SetUnique(true).
+			SetPartialFilterExpression(bson.M{"email": bson.M{"$type": "string"}}),
+	})
+
+	return err
+}
+
+func (ur *UserRepository) CreateUser(
+	ctx context.Context, userEntity *user_entity.User) *internal_error.InternalError {
+	if _, err := ur.Collection.InsertOne(ctx, toUserEntityMongo(userEntity)); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return internal_error.NewConflictError("Email is already registered")
+		}
+
+		logger.Error("Error trying to insert user", err)
+		return internal_error.NewInternalServerError("Error trying to insert user")
+	}
+
+	ur.cacheUser(userEntity)
+
+	return nil
+}
+
+func toUserEntityMongo(userEntity *user_entity.User) *UserEntityMongo {
+	return &UserEntityMongo{
+		Id:          userEntity.Id,
+		Name:        userEntity.Name,
+		Email:       userEntity.Email,
+		DisplayName: userEntity.DisplayName,
+		Status:      userEntity.Status,
+		CreatedAt:   userEntity.CreatedAt.Unix(),
+	}
+}
+
+func (um *UserEntityMongo) toEntity() *user_entity.User {
+	userEntity := &user_entity.User{
+		Id:          um.Id,
+		Name:        um.Name,
+		Email:       um.Email,
+		DisplayName: um.DisplayName,
+		Status:      um.Status,
+	}
+	if um.CreatedAt > 0 {
+		userEntity.CreatedAt = time.Unix(um.CreatedAt, 0)
+	}
+
+	return userEntity
+}
+
+func (ur *UserRepository) cachedUser(userId string) (*user_entity.User, bool) {
+	ur.userCacheMutex.Lock()
+	defer ur.userCacheMutex.Unlock()
+
+	cached, ok := ur.userCache[userId]
+	if !ok || time.Now().After(cached.expiresAt) {
+		delete(ur.userCache, userId)
+		return nil, false
+	}
+
+	userEntity := cached.user
+	return &userEntity, true
+}
+
+func (ur *UserRepository) cacheUser(userEntity *user_entity.User) {
+	if ur.cacheTTL <= 0 {
+		return
+	}
+
+	ur.userCacheMutex.Lock()
+	defer ur.userCacheMutex.Unlock()
+
+	ur.userCache[userEntity.Id] = cachedUser{
+		user:      *userEntity,
+		expiresAt: time.Now().Add(ur.cacheTTL),
+	}
+}
+
+func (ur *UserRepository) evictUser(userId string) {
+	ur.userCacheMutex.Lock()
+	defer ur.userCacheMutex.Unlock()
+
+	delete(ur.userCache, userId)
+}
+
+func getUserCacheTTL() time.Duration {
+	cacheTTL := os.Getenv("USER_CACHE_TTL")
+	duration, err := time.ParseDuration(cacheTTL)
+	if err != nil {
+		return 30 * time.Second
+	}
+
+	return duration
+}
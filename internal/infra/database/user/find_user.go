@@ -12,23 +12,12 @@ import (
 	"go.mongodb.org/mongo-driver/mongo"
 )
 
-type UserEntityMongo struct {
-	Id   string `bson:"_id"`
-	Name string `bson:"name"`
-}
-
-type UserRepository struct {
-	Collection *mongo.Collection
-}
-
-func NewUserRepository(database *mongo.Database) *UserRepository {
-	return &UserRepository{
-		Collection: database.Collection("users"),
-	}
-}
-
 func (ur *UserRepository) FindUserById(
 	ctx context.Context, userId string) (*user_entity.User, *internal_error.InternalError) {
+	if userEntity, ok := ur.cachedUser(userId); ok {
+		return userEntity, nil
+	}
+
 	filter := bson.M{"_id": userId}
 
 	var userEntityMongo UserEntityMongo
@@ -44,10 +33,8 @@ func (ur *UserRepository) FindUserById(
 		return nil, internal_error.NewInternalServerError("Error trying to find user by userId")
 	}
 
-	userEntity := &user_entity.User{
-		Id:   userEntityMongo.Id,
-		Name: userEntityMongo.Name,
-	}
+	userEntity := userEntityMongo.toEntity()
+	ur.cacheUser(userEntity)
 
 	return userEntity, nil
 }
@@ -0,0 +1,63 @@
+package user
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/configuration/logger"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/entity/user_entity"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/internal_error"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func (ur *UserRepository) UpdateUser(
+	ctx context.Context, userEntity *user_entity.User) *internal_error.InternalError {
+	filter := bson.M{"_id": userEntity.Id}
+	update := bson.M{"$set": bson.M{
+		"name":         userEntity.Name,
+		"email":        userEntity.Email,
+		"display_name": userEntity.DisplayName,
+		"status":       userEntity.Status,
+	}}
+
+	result, err := ur.Collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return internal_error.NewConflictError("Email is already registered")
+		}
+
+		logger.Error("Error trying to update user", err)
+		return internal_error.NewInternalServerError("Error trying to update user")
+	}
+
+	if result.MatchedCount == 0 {
+		ur.evictUser(userEntity.Id)
+		return internal_error.NewNotFoundError(
+			fmt.Sprintf("User not found with this id = %s", userEntity.Id))
+	}
+
+	ur.cacheUser(userEntity)
+
+	return nil
+}
+
+// DeleteUser tira o usuário do cache só depois da remoção, para que uma
+// leitura concorrente não o devolva ao cache antes do documento sumir.
+func (ur *UserRepository) DeleteUser(
+	ctx context.Context, userId string) *internal_error.InternalError {
+	defer ur.evictUser(userId)
+
+	result, err := ur.Collection.DeleteOne(ctx, bson.M{"_id": userId})
+	if err != nil {
+		logger.Error("Error trying to delete user", err)
+		return internal_error.NewInternalServerError("Error trying to delete user")
+	}
+
+	if result.DeletedCount == 0 {
+		return internal_error.NewNotFoundError(
+			fmt.Sprintf("User not found with this id = %s", userId))
+	}
+
+	return nil
+}
@@ -0,0 +1,125 @@
+package webhook
+
+import (
+	"context"
+	"time"
+
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/configuration/logger"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/entity/webhook_entity"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/internal_error"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const deliveryLogLimit = 100
+
+type DeliveryEntityMongo struct {
+	Id             string                        `bson:"_id"`
+	WebhookId      string                        `bson:"webhook_id"`
+	EventType      string                        `bson:"event_type"`
+	AuctionId      string                        `bson:"auction_id"`
+	Payload        string                        `bson:"payload"`
+	Status         webhook_entity.DeliveryStatus `bson:"status"`
+	Attempts       int                           `bson:"attempts"`
+	ResponseStatus int                           `bson:"response_status,omitempty"`
+	LastError      string                        `bson:"last_error,omitempty"`
+	CreatedAt      int64                         `bson:"created_at"`
+	NextAttemptAt  int64                         `bson:"next_attempt_at"`
+}
+
+func newDeliveryEntityMongo(delivery *webhook_entity.Delivery) *DeliveryEntityMongo {
+	return &DeliveryEntityMongo{
+		Id:             delivery.Id,
+		WebhookId:      delivery.WebhookId,
+		EventType:      delivery.EventType,
+		AuctionId:      delivery.AuctionId,
+		Payload:        string(delivery.Payload),
+		Status:         delivery.Status,
+		Attempts:       delivery.Attempts,
+		ResponseStatus: delivery.ResponseStatus,
+		LastError:      delivery.LastError,
+		CreatedAt:      delivery.CreatedAt.UnixMilli(),
+		NextAttemptAt:  delivery.NextAttemptAt.UnixMilli(),
+	}
+}
+
+// CreateDelivery grava a entrega apenas se ela ainda não existir. Devolve
+// false quando uma notificação repetida encontra a entrega já criada.
+func (wr *WebhookRepository) CreateDelivery(
+	ctx context.Context, delivery *webhook_entity.Delivery) (bool, *internal_error.InternalError) {
+	if _, err := wr.DeliveryCollection.InsertOne(ctx, newDeliveryEntityMongo(delivery)); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return false, nil
+		}
+
+		logger.Error("Error trying to create webhook delivery", err)
+		return false, internal_error.NewInternalServerError("Error trying to create webhook delivery")
+	}
+
+	return true, nil
+}
+
+func (wr *WebhookRepository) SaveDelivery(
+	ctx context.Context, delivery *webhook_entity.Delivery) *internal_error.InternalError {
+	deliveryMongo := newDeliveryEntityMongo(delivery)
+
+	opts := options.Replace().SetUpsert(true)
+	if _, err := wr.DeliveryCollection.ReplaceOne(ctx, bson.M{"_id": delivery.Id}, deliveryMongo, opts); err != nil {
+		logger.Error("Error trying to save webhook delivery", err)
+		return internal_error.NewInternalServerError("Error trying to save webhook delivery")
+	}
+
+	return nil
+}
+
+func (wr *WebhookRepository) FindDeliveriesByWebhookId(
+	ctx context.Context, webhookId string) ([]webhook_entity.Delivery, *internal_error.InternalError) {
+	opts := options.Find().
+		SetSort(bson.D{{Key: "created_at", Value: -1}}).
+		SetLimit(deliveryLogLimit)
+
+	return wr.findDeliveries(ctx, bson.M{"webhook_id": webhookId}, opts)
+}
+
+func (wr *WebhookRepository) FindPendingDeliveries(
+	ctx context.Context) ([]webhook_entity.Delivery, *internal_error.InternalError) {
+	return wr.findDeliveries(ctx, bson.M{"status": webhook_entity.DeliveryPending}, options.Find())
+}
+
+func (wr *WebhookRepository) findDeliveries(
+	ctx context.Context,
+	filter bson.M,
+	opts *options.FindOptions) ([]webhook_entity.Delivery, *internal_error.InternalError) {
+	cursor, err := wr.DeliveryCollection.Find(ctx, filter, opts)
+	if err != nil {
+		logger.Error("Error trying to find webhook deliveries", err)
+		return nil, internal_error.NewInternalServerError("Error trying to find webhook deliveries")
+	}
+	defer cursor.Close(ctx)
+
+	var deliveriesMongo []DeliveryEntityMongo
+	if err := cursor.All(ctx, &deliveriesMongo); err != nil {
+		logger.Error("Error trying to decode webhook deliveries", err)
+		return nil, internal_error.NewInternalServerError("Error trying to decode webhook deliveries")
+	}
+
+	deliveries := make([]webhook_entity.Delivery, 0, len(deliveriesMongo))
+	for _, deliveryMongo := range deliveriesMongo {
+		deliveries = append(deliveries, webhook_entity.Delivery{
+			Id:             deliveryMongo.Id,
+			WebhookId:      deliveryMongo.WebhookId,
+			EventType:      deliveryMongo.EventType,
+			AuctionId:      deliveryMongo.AuctionId,
+			Payload:        []byte(deliveryMongo.Payload),
+			Status:         deliveryMongo.Status,
+			Attempts:       deliveryMongo.Attempts,
+			ResponseStatus: deliveryMongo.ResponseStatus,
+			LastError:      deliveryMongo.LastError,
+			CreatedAt:      time.UnixMilli(deliveryMongo.CreatedAt),
+			NextAttemptAt:  time.UnixMilli(deliveryMongo.NextAttemptAt),
+		})
+	}
+
+	return deliveries, nil
+}
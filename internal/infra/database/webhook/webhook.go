@@ -0,0 +1,129 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/configuration/logger"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/entity/webhook_entity"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/internal_error"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type WebhookEntityMongo struct {
+	Id         string   `bson:"_id"`
+	URL        string   `bson:"url"`
+	Secret     string   `bson:"secret"`
+	EventTypes []string `bson:"event_types"`
+	CreatedAt  int64    `bson:"created_at"`
+}
+
+type WebhookRepository struct {
+	Collection         *mongo.Collection
+	DeliveryCollection *mongo.Collection
+}
+
+func NewWebhookRepository(database *mongo.Database) *WebhookRepository {
+	return &WebhookRepository{
+		Collection:         database.Collection("webhooks"),
+		DeliveryCollection: database.Collection("webhook_deliveries"),
+	}
+}
+
+func (wr *WebhookRepository) CreateWebhook(
+	ctx context.Context, webhookEntity *webhook_entity.Webhook) *internal_error.InternalError {
+	webhookEntityMongo := &WebhookEntityMongo{
+		Id:         webhookEntity.Id,
+		URL:        webhookEntity.URL,
+		Secret:     webhookEntity.Secret,
+		EventTypes: webhookEntity.EventTypes,
+		CreatedAt:  webhookEntity.CreatedAt.Unix(),
+	}
+
+	if _, err := wr.Collection.InsertOne(ctx, webhookEntityMongo); err != nil {
+		logger.Error("Error trying to insert webhook", err)
+		return internal_error.NewInternalServerError("Error trying to insert webhook")
+	}
+
+	return nil
+}
+
+func (wr *WebhookRepository) FindWebhooks(
+	ctx context.Context) ([]webhook_entity.Webhook, *internal_error.InternalError) {
+	return wr.findWebhooks(ctx, bson.M{})
+}
+
+func (wr *WebhookRepository) FindWebhooksByEventType(
+	ctx context.Context, eventType string) ([]webhook_entity.Webhook, *internal_error.InternalError) {
+	return wr.findWebhooks(ctx, bson.M{"event_types": eventType})
+}
+
+func (wr *WebhookRepository) FindWebhookById(
+	ctx context.Context, id string) (*webhook_entity.Webhook, *internal_error.InternalError) {
+	filter := bson.M{"_id": id}
+
+	var webhookEntityMongo WebhookEntityMongo
+	if err := wr.Collection.FindOne(ctx, filter).Decode(&webhookEntityMongo); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, internal_error.NewNotFoundError(
+				fmt.Sprintf("Webhook not found with this id = %s", id))
+		}
+
+		logger.Error("Error trying to find webhook by id", err)
+		return nil, internal_error.NewInternalServerError("Error trying to find webhook by id")
+	}
+
+	webhookEntity := webhookEntityMongo.toEntity()
+	return &webhookEntity, nil
+}
+
+func (wr *WebhookRepository) DeleteWebhook(ctx context.Context, id string) *internal_error.InternalError {
+	result, err := wr.Collection.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		logger.Error("Error trying to delete webhook", err)
+		return internal_error.NewInternalServerError("Error trying to delete webhook")
+	}
+
+	if result.DeletedCount == 0 {
+		return internal_error.NewNotFoundError(
+			fmt.Sprintf("Webhook not found with this id = %s", id))
+	}
+
+	return nil
+}
+
+func (wr *WebhookRepository) findWebhooks(
+	ctx context.Context, filter bson.M) ([]webhook_entity.Webhook, *internal_error.InternalError) {
+	cursor, err := wr.Collection.Find(ctx, filter)
+	if err != nil {
+		logger.Error("Error trying to find webhooks", err)
+		return nil, internal_error.NewInternalServerError("Error trying to find webhooks")
+	}
+	defer cursor.Close(ctx)
+
+	var webhooksMongo []WebhookEntityMongo
+	if err := cursor.All(ctx, &webhooksMongo); err != nil {
+		logger.Error("Error trying to decode webhooks", err)
+		return nil, internal_error.NewInternalServerError("Error trying to decode webhooks")
+	}
+
+	webhookEntities := make([]webhook_entity.Webhook, 0, len(webhooksMongo))
+	for _, webhookMongo := range webhooksMongo {
+		webhookEntities = append(webhookEntities, webhookMongo.toEntity())
+	}
+
+	return webhookEntities, nil
+}
+
+func (wm *WebhookEntityMongo) toEntity() webhook_entity.Webhook {
+	return webhook_entity.Webhook{
+		Id:         wm.Id,
+		URL:        wm.URL,
+		Secret:     wm.Secret,
+		EventTypes: wm.EventTypes,
+		CreatedAt:  time.Unix(wm.CreatedAt, 0),
+	}
+}
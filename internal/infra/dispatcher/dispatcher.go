@@ -0,0 +1,196 @@
+package dispatcher
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/configuration/logger"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/entity/webhook_entity"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/infra/scheduler"
+	"go.uber.org/zap"
+)
+
+const maxRetryDelay = time.Hour
+
+// Dispatcher envia as entregas pelo agendador, reagendando as falhas com
+// backoff exponencial. Cada tentativa atualiza o log de entregas.
+type Dispatcher struct {
+	repository  webhook_entity.WebhookRepositoryInterface
+	scheduler   *scheduler.Scheduler
+	client      *http.Client
+	retryDelay  time.Duration
+	maxAttempts int
+}
+
+func NewDispatcher(
+	repository webhook_entity.WebhookRepositoryInterface,
+	scheduler *scheduler.Scheduler) *Dispatcher {
+	return &Dispatcher{
+		repository:  repository,
+		scheduler:   scheduler,
+		client:      &http.Client{Timeout: 10 * time.Second},
+		retryDelay:  getRetryDelay(),
+		maxAttempts: getMaxAttempts(),
+	}
+}
+
+func (d *Dispatcher) Dispatch(ctx context.Context, webhook webhook_entity.Webhook, delivery webhook_entity.Delivery) {
+	d.scheduler.Schedule("webhook_delivery:"+delivery.Id, delivery.NextAttemptAt, func(ctx context.Context) {
+		d.attempt(ctx, webhook, delivery)
+	})
+}
+
+// RecoverPendingDeliveries retoma as entregas interrompidas por uma parada da aplicação
+func (d *Dispatcher) RecoverPendingDeliveries(ctx context.Context) {
+	deliveries, err := d.repository.FindPendingDeliveries(ctx)
+	if err != nil {
+		return
+	}
+
+	// Cada tentativa relê o webhook, então basta o Id para retomar a entrega
+	for _, delivery := range deliveries {
+		d.Dispatch(ctx, webhook_entity.Webhook{Id: delivery.WebhookId}, delivery)
+	}
+}
+
+// attempt relê o webhook antes de cada envio: entregas de um webhook removido
+// ou que deixou de assinar o evento são abandonadas, e mudanças de URL ou
+// segredo valem já na próxima tentativa.
+func (d *Dispatcher) attempt(ctx context.Context, webhook webhook_entity.Webhook, delivery webhook_entity.Delivery) {
+	current, internalErr := d.repository.FindWebhookById(ctx, webhook.Id)
+	if internalErr != nil {
+		if internalErr.Err == "not_found" {
+			d.abandon(ctx, delivery, "webhook was removed")
+			return
+		}
+
+		// Sem saber se o webhook existe, a tentativa fica para depois
+		delivery.NextAttemptAt = time.Now().Add(d.backoff(delivery.Attempts + 1))
+		d.Dispatch(ctx, webhook, delivery)
+		return
+	}
+
+	if !current.Subscribes(delivery.EventType) {
+		d.abandon(ctx, delivery, "webhook no longer subscribes to "+delivery.EventType)
+		return
+	}
+	webhook = *current
+
+	responseStatus, err := d.send(ctx, webhook, delivery)
+
+	delivery.Attempts++
+	delivery.ResponseStatus = responseStatus
+	switch {
+	case err == nil:
+		delivery.Status = webhook_entity.DeliverySucceeded
+		delivery.LastError = ""
+	case delivery.Attempts >= d.maxAttempts:
+		delivery.Status = webhook_entity.DeliveryFailed
+		delivery.LastError = err.Error()
+	default:
+		delivery.LastError = err.Error()
+		delivery.NextAttemptAt = time.Now().Add(d.backoff(delivery.Attempts))
+	}
+
+	if err != nil {
+		logger.Error("Error trying to deliver webhook", err,
+			zap.String("webhook_id", webhook.Id),
+			zap.String("delivery_id", delivery.Id),
+			zap.Int("attempts", delivery.Attempts),
+		)
+	}
+
+	d.repository.SaveDelivery(ctx, &delivery)
+
+	if delivery.Status == webhook_entity.DeliveryPending {
+		d.Dispatch(ctx, webhook, delivery)
+	}
+}
+
+func (d *Dispatcher) abandon(ctx context.Context, delivery webhook_entity.Delivery, reason string) {
+	delivery.Status = webhook_entity.DeliveryAbandoned
+	delivery.LastError = reason
+
+	logger.Info("Webhook delivery abandoned",
+		zap.String("webhook_id", delivery.WebhookId),
+		zap.String("delivery_id", delivery.Id),
+		zap.String("reason", reason),
+	)
+
+	d.repository.SaveDelivery(ctx, &delivery)
+}
+
+func (d *Dispatcher) send(
+	ctx context.Context, webhook webhook_entity.Webhook, delivery webhook_entity.Delivery) (int, error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, err
+	}
+
+	timestamp := time.Now().Unix()
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("X-Webhook-Id", webhook.Id)
+	request.Header.Set("X-Webhook-Event", delivery.EventType)
+	request.Header.Set("X-Webhook-Delivery", delivery.Id)
+	request.Header.Set("X-Webhook-Timestamp", strconv.FormatInt(timestamp, 10))
+	request.Header.Set("X-Webhook-Signature", Sign(webhook.Secret, timestamp, delivery.Payload))
+
+	response, err := d.client.Do(request)
+	if err != nil {
+		return 0, err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		return response.StatusCode, fmt.Errorf("webhook responded with status %d", response.StatusCode)
+	}
+
+	return response.StatusCode, nil
+}
+
+func (d *Dispatcher) backoff(attempts int) time.Duration {
+	delay := d.retryDelay << uint(attempts-1)
+	if delay <= 0 || delay > maxRetryDelay {
+		return maxRetryDelay
+	}
+
+	return delay
+}
+
+// Sign calcula o HMAC-SHA256 de "<timestamp>.<payload>" com o segredo do webhook.
+// O timestamp assinado impede o reaproveitamento de uma entrega antiga.
+func Sign(secret string, timestamp int64, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(payload)
+
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func getRetryDelay() time.Duration {
+	retryDelay := os.Getenv("WEBHOOK_RETRY_DELAY")
+	duration, err := time.ParseDuration(retryDelay)
+	if err != nil || duration <= 0 {
+		return 5 * time.Second
+	}
+
+	return duration
+}
+
+func getMaxAttempts() int {
+	value, err := strconv.Atoi(os.Getenv("WEBHOOK_MAX_ATTEMPTS"))
+	if err != nil || value <= 0 {
+		return 6
+	}
+
+	return value
+}
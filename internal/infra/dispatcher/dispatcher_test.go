@@ -0,0 +1,216 @@
+package dispatcher
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/entity/webhook_entity"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/infra/scheduler"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/internal_error"
+	"github.com/stretchr/testify/assert"
+)
+
+type webhookRepositoryMock struct {
+	webhook_entity.WebhookRepositoryInterface
+
+	mu         sync.Mutex
+	webhooks   map[string]webhook_entity.Webhook
+	deliveries []webhook_entity.Delivery
+	pending    []webhook_entity.Delivery
+}
+
+func newWebhookRepositoryMock(webhooks ...webhook_entity.Webhook) *webhookRepositoryMock {
+	repository := &webhookRepositoryMock{webhooks: make(map[string]webhook_entity.Webhook)}
+	for _, webhook := range webhooks {
+		repository.webhooks[webhook.Id] = webhook
+	}
+	return repository
+}
+
+func (m *webhookRepositoryMock) FindWebhookById(
+	ctx context.Context, id string) (*webhook_entity.Webhook, *internal_error.InternalError) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	webhook, ok := m.webhooks[id]
+	if !ok {
+		return nil, internal_error.NewNotFoundError("Webhook not found")
+	}
+	return &webhook, nil
+}
+
+func (m *webhookRepositoryMock) DeleteWebhook(ctx context.Context, id string) *internal_error.InternalError {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.webhooks, id)
+	return nil
+}
+
+func (m *webhookRepositoryMock) FindPendingDeliveries(
+	ctx context.Context) ([]webhook_entity.Delivery, *internal_error.InternalError) {
+	return m.pending, nil
+}
+
+func (m *webhookRepositoryMock) SaveDelivery(
+	ctx context.Context, delivery *webhook_entity.Delivery) *internal_error.InternalError {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.deliveries = append(m.deliveries, *delivery)
+	return nil
+}
+
+func (m *webhookRepositoryMock) lastDelivery() webhook_entity.Delivery {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.deliveries[len(m.deliveries)-1]
+}
+
+func TestDispatcherSignsAndRetriesDeliveries(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	secret := "0123456789abcdef"
+	payload := []byte(`{"event":"auction.closed"}`)
+
+	var mu sync.Mutex
+	requests := 0
+	validSignature := true
+	done := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		timestamp, _ := strconv.ParseInt(r.Header.Get("X-Webhook-Timestamp"), 10, 64)
+
+		mu.Lock()
+		defer mu.Unlock()
+
+		validSignature = validSignature && r.Header.Get("X-Webhook-Signature") == Sign(secret, timestamp, body)
+		requests++
+		if requests == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		close(done)
+	}))
+	defer server.Close()
+
+	webhook := webhook_entity.Webhook{Id: "webhook-id", URL: server.URL, Secret: secret,
+		EventTypes: []string{webhook_entity.AuctionClosedEvent}}
+	repository := newWebhookRepositoryMock(webhook)
+	dispatcher := NewDispatcher(repository, scheduler.NewScheduler(ctx))
+	dispatcher.retryDelay = 10 * time.Millisecond
+
+	delivery := webhook_entity.NewDelivery(webhook.Id, webhook_entity.AuctionClosedEvent, "auction-id", payload)
+	dispatcher.Dispatch(ctx, webhook, *delivery)
+
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("webhook was not retried")
+	}
+
+	assert.Eventually(t, func() bool {
+		return repository.lastDelivery().Status == webhook_entity.DeliverySucceeded
+	}, time.Second, 10*time.Millisecond)
+
+	mu.Lock()
+	assert.True(t, validSignature)
+	mu.Unlock()
+
+	logged := repository.lastDelivery()
+	assert.Equal(t, 2, logged.Attempts)
+	assert.Equal(t, http.StatusOK, logged.ResponseStatus)
+	assert.Empty(t, logged.LastError)
+}
+
+func TestDispatcherStopsAfterMaxAttempts(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	webhook := webhook_entity.Webhook{Id: "webhook-id", URL: server.URL, Secret: "0123456789abcdef",
+		EventTypes: []string{webhook_entity.AuctionClosedEvent}}
+	repository := newWebhookRepositoryMock(webhook)
+	dispatcher := NewDispatcher(repository, scheduler.NewScheduler(ctx))
+	dispatcher.retryDelay = time.Millisecond
+	dispatcher.maxAttempts = 2
+
+	delivery := webhook_entity.NewDelivery(webhook.Id, webhook_entity.AuctionClosedEvent, "auction-id", []byte(`{}`))
+	dispatcher.Dispatch(ctx, webhook, *delivery)
+
+	assert.Eventually(t, func() bool {
+		repository.mu.Lock()
+		defer repository.mu.Unlock()
+		return len(repository.deliveries) > 0 &&
+			repository.deliveries[len(repository.deliveries)-1].Status == webhook_entity.DeliveryFailed
+	}, 2*time.Second, 10*time.Millisecond)
+
+	logged := repository.lastDelivery()
+	assert.Equal(t, 2, logged.Attempts)
+	assert.Equal(t, http.StatusInternalServerError, logged.ResponseStatus)
+}
+
+func TestDispatcherAbandonsDeliveriesOfRemovedWebhooks(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var mu sync.Mutex
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		requests++
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	webhook := webhook_entity.Webhook{Id: "webhook-id", URL: server.URL, Secret: "0123456789abcdef",
+		EventTypes: []string{webhook_entity.AuctionClosedEvent}}
+	repository := newWebhookRepositoryMock(webhook)
+	dispatcher := NewDispatcher(repository, scheduler.NewScheduler(ctx))
+	dispatcher.retryDelay = 50 * time.Millisecond
+
+	delivery := webhook_entity.NewDelivery(webhook.Id, webhook_entity.AuctionClosedEvent, "auction-id", []byte(`{}`))
+	dispatcher.Dispatch(ctx, webhook, *delivery)
+
+	// A primeira tentativa falha; o webhook é removido antes da segunda
+	assert.Eventually(t, func() bool {
+		repository.mu.Lock()
+		defer repository.mu.Unlock()
+		return len(repository.deliveries) == 1
+	}, time.Second, 5*time.Millisecond)
+	repository.DeleteWebhook(ctx, webhook.Id)
+
+	assert.Eventually(t, func() bool {
+		return repository.lastDelivery().Status == webhook_entity.DeliveryAbandoned
+	}, time.Second, 10*time.Millisecond)
+
+	mu.Lock()
+	assert.Equal(t, 1, requests)
+	mu.Unlock()
+	assert.Equal(t, "webhook was removed", repository.lastDelivery().LastError)
+
+	// Entregas pendentes de um webhook removido também são abandonadas na recuperação
+	orphan := webhook_entity.NewDelivery("removed-id", webhook_entity.AuctionClosedEvent, "auction-id", []byte(`{}`))
+	repository.pending = []webhook_entity.Delivery{*orphan}
+	dispatcher.RecoverPendingDeliveries(ctx)
+
+	assert.Eventually(t, func() bool {
+		logged := repository.lastDelivery()
+		return logged.Id == orphan.Id && logged.Status == webhook_entity.DeliveryAbandoned
+	}, time.Second, 10*time.Millisecond)
+}
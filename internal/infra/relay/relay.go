@@ -0,0 +1,123 @@
+package relay
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/configuration/logger"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/entity/outbox_entity"
+	"go.uber.org/zap"
+)
+
+const batchSize = 100
+
+// Relay drena o outbox para os sinks configurados. Uma mensagem só é marcada
+// como entregue quando todos os sinks a aceitam; caso contrário ela volta a
+// ser enviada a todos depois do backoff.
+type Relay struct {
+	repository  outbox_entity.OutboxRepositoryInterface
+	sinks       []Sink
+	interval    time.Duration
+	retryDelay  time.Duration
+	maxAttempts int
+}
+
+func NewRelay(repository outbox_entity.OutboxRepositoryInterface, sinks ...Sink) *Relay {
+	return &Relay{
+		repository:  repository,
+		sinks:       sinks,
+		interval:    getRelayInterval(),
+		retryDelay:  getRetryDelay(),
+		maxAttempts: getMaxAttempts(),
+	}
+}
+
+func (r *Relay) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(r.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				r.ProcessPending(ctx)
+			}
+		}
+	}()
+}
+
+// ProcessPending entrega as mensagens pendentes e retorna quantas foram entregues
+func (r *Relay) ProcessPending(ctx context.Context) int {
+	now := time.Now()
+	messages, err := r.repository.FindPendingMessages(ctx, now, batchSize)
+	if err != nil {
+		return 0
+	}
+
+	delivered := 0
+	for _, message := range messages {
+		if err := r.deliver(ctx, message); err != nil {
+			message.RegisterFailure(err, now, r.retryDelay, r.maxAttempts)
+			logger.Error("Error trying to deliver outbox message", err,
+				zap.String("id", message.Id),
+				zap.Int("attempts", message.Attempts),
+			)
+
+			r.repository.UpdateFailedMessage(ctx, message)
+			continue
+		}
+
+		if err := r.repository.MarkDelivered(ctx, message.Id); err != nil {
+			continue
+		}
+		delivered++
+	}
+
+	return delivered
+}
+
+func (r *Relay) deliver(ctx context.Context, message outbox_entity.Message) error {
+	var errs []error
+	for _, sink := range r.sinks {
+		if err := sink.Deliver(ctx, message); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", sink.Name(), err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func getRelayInterval() time.Duration {
+	relayInterval := os.Getenv("OUTBOX_RELAY_INTERVAL")
+	duration, err := time.ParseDuration(relayInterval)
+	if err != nil || duration <= 0 {
+		return time.Second
+	}
+
+	return duration
+}
+
+func getRetryDelay() time.Duration {
+	retryDelay := os.Getenv("OUTBOX_RETRY_DELAY")
+	duration, err := time.ParseDuration(retryDelay)
+	if err != nil || duration <= 0 {
+		return 2 * time.Second
+	}
+
+	return duration
+}
+
+func getMaxAttempts() int {
+	value, err := strconv.Atoi(os.Getenv("OUTBOX_MAX_ATTEMPTS"))
+	if err != nil || value <= 0 {
+		return 10
+	}
+
+	return value
+}
@@ -0,0 +1,132 @@
+package relay
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/entity/outbox_entity"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/internal_error"
+	"github.com/stretchr/testify/assert"
+)
+
+type outboxRepositoryMock struct {
+	messages map[string]outbox_entity.Message
+}
+
+func newOutboxRepositoryMock(messages ...outbox_entity.Message) *outboxRepositoryMock {
+	repository := &outboxRepositoryMock{messages: make(map[string]outbox_entity.Message)}
+	for _, message := range messages {
+		repository.messages[message.Id] = message
+	}
+	return repository
+}
+
+func (m *outboxRepositoryMock) FindPendingMessages(
+	ctx context.Context, now time.Time, limit int64) ([]outbox_entity.Message, *internal_error.InternalError) {
+	var pending []outbox_entity.Message
+	for _, message := range m.messages {
+		if message.Status == outbox_entity.Pending && !message.NextAttemptAt.After(now) {
+			pending = append(pending, message)
+		}
+	}
+	return pending, nil
+}
+
+func (m *outboxRepositoryMock) MarkDelivered(ctx context.Context, id string) *internal_error.InternalError {
+	message := m.messages[id]
+	message.Status = outbox_entity.Delivered
+	m.messages[id] = message
+	return nil
+}
+
+func (m *outboxRepositoryMock) UpdateFailedMessage(
+	ctx context.Context, message outbox_entity.Message) *internal_error.InternalError {
+	m.messages[message.Id] = message
+	return nil
+}
+
+type flakySink struct {
+	failures int
+}
+
+func (s *flakySink) Name() string { return "flaky" }
+
+func (s *flakySink) Deliver(ctx context.Context, message outbox_entity.Message) error {
+	if s.failures > 0 {
+		s.failures--
+		return errors.New("unavailable")
+	}
+	return nil
+}
+
+func newTestMessage(t *testing.T, aggregateId string) outbox_entity.Message {
+	message, err := outbox_entity.NewMessage(outbox_entity.AuctionClosedEvent, aggregateId, map[string]string{})
+	assert.Nil(t, err)
+	message.NextAttemptAt = time.Now().Add(-time.Second)
+	return *message
+}
+
+func TestRelayDeliversPendingMessages(t *testing.T) {
+	repository := newOutboxRepositoryMock(newTestMessage(t, "first"), newTestMessage(t, "second"))
+	sink := NewMemorySink()
+
+	relay := NewRelay(repository, sink)
+
+	assert.Equal(t, 2, relay.ProcessPending(context.Background()))
+	assert.Equal(t, 0, relay.ProcessPending(context.Background()))
+	assert.Len(t, sink.Messages(), 2)
+}
+
+func TestRelayRetriesFailedDeliveriesOnce(t *testing.T) {
+	message := newTestMessage(t, "auction-id")
+	repository := newOutboxRepositoryMock(message)
+	sink := NewMemorySink()
+
+	relay := NewRelay(repository, sink, &flakySink{failures: 1})
+	relay.retryDelay = time.Millisecond
+
+	assert.Equal(t, 0, relay.ProcessPending(context.Background()))
+	failed := repository.messages[message.Id]
+	assert.Equal(t, 1, failed.Attempts)
+	assert.Equal(t, "flaky: unavailable", failed.LastError)
+
+	time.Sleep(5 * time.Millisecond)
+	assert.Equal(t, 1, relay.ProcessPending(context.Background()))
+
+	// O MemorySink recebeu a mensagem nas duas tentativas, mas a registra uma só vez
+	assert.Len(t, sink.Messages(), 1)
+	assert.Equal(t, outbox_entity.Delivered, repository.messages[message.Id].Status)
+}
+
+func TestRelayGivesUpAfterMaxAttempts(t *testing.T) {
+	message := newTestMessage(t, "auction-id")
+	repository := newOutboxRepositoryMock(message)
+
+	relay := NewRelay(repository, &flakySink{failures: 10})
+	relay.maxAttempts = 1
+
+	relay.ProcessPending(context.Background())
+
+	assert.Equal(t, outbox_entity.Failed, repository.messages[message.Id].Status)
+}
+
+func TestWebhookSinkSendsIdempotencyKey(t *testing.T) {
+	var idempotencyKey, eventType string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		idempotencyKey = r.Header.Get("Idempotency-Key")
+		eventType = r.Header.Get("X-Event-Type")
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	message := newTestMessage(t, "auction-id")
+	err := NewWebhookSink(server.URL).Deliver(context.Background(), message)
+
+	assert.Nil(t, err)
+	assert.Equal(t, message.Id, idempotencyKey)
+	assert.Equal(t, outbox_entity.AuctionClosedEvent, eventType)
+}
@@ -0,0 +1,116 @@
+package relay
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/configuration/logger"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/entity/outbox_entity"
+	"go.uber.org/zap"
+)
+
+// Sink recebe as mensagens do outbox. A entrega é pelo menos uma vez, então
+// cada sink deve usar o Id da mensagem como chave de idempotência.
+type Sink interface {
+	Name() string
+	Deliver(ctx context.Context, message outbox_entity.Message) error
+}
+
+type LogSink struct{}
+
+func NewLogSink() *LogSink {
+	return &LogSink{}
+}
+
+func (s *LogSink) Name() string { return "log" }
+
+func (s *LogSink) Deliver(ctx context.Context, message outbox_entity.Message) error {
+	logger.Info("Outbox message delivered",
+		zap.String("id", message.Id),
+		zap.String("event_type", message.EventType),
+		zap.String("aggregate_id", message.AggregateId),
+		zap.ByteString("payload", message.Payload),
+	)
+
+	return nil
+}
+
+type WebhookSink struct {
+	URL    string
+	Client *http.Client
+}
+
+func NewWebhookSink(url string) *WebhookSink {
+	return &WebhookSink{
+		URL:    url,
+		Client: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+func (s *WebhookSink) Name() string { return "webhook" }
+
+func (s *WebhookSink) Deliver(ctx context.Context, message outbox_entity.Message) error {
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, s.URL, bytes.NewReader(message.Payload))
+	if err != nil {
+		return err
+	}
+
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("Idempotency-Key", message.Id)
+	request.Header.Set("X-Event-Type", message.EventType)
+
+	response, err := s.Client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		return fmt.Errorf("webhook responded with status %d", response.StatusCode)
+	}
+
+	return nil
+}
+
+// MemorySink guarda as mensagens recebidas, descartando repetições pelo Id
+type MemorySink struct {
+	mu       sync.Mutex
+	messages []outbox_entity.Message
+	received map[string]struct{}
+}
+
+func NewMemorySink() *MemorySink {
+	return &MemorySink{
+		received: make(map[string]struct{}),
+	}
+}
+
+func (s *MemorySink) Name() string { return "memory" }
+
+func (s *MemorySink) Deliver(ctx context.Context, message outbox_entity.Message) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.received[message.Id]; ok {
+		return nil
+	}
+
+	s.received[message.Id] = struct{}{}
+	s.messages = append(s.messages, message)
+
+	return nil
+}
+
+func (s *MemorySink) Messages() []outbox_entity.Message {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	messages := make([]outbox_entity.Message, len(s.messages))
+	copy(messages, s.messages)
+
+	return messages
+}
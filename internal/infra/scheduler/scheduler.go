@@ -0,0 +1,174 @@
+package scheduler
+
+import (
+	"container/heap"
+	"context"
+	"sync"
+	"time"
+)
+
+type Job func(ctx context.Context)
+
+type scheduledJob struct {
+	key   string
+	at    time.Time
+	job   Job
+	index int
+}
+
+// Scheduler mantém todos os agendamentos em um único heap ordenado pelo
+// horário de execução, usando um só timer em vez de um ticker por item.
+type Scheduler struct {
+	mu     sync.Mutex
+	jobs   map[string]*scheduledJob
+	queue  jobQueue
+	wakeup chan struct{}
+}
+
+func NewScheduler(ctx context.Context) *Scheduler {
+	scheduler := &Scheduler{
+		jobs:   make(map[string]*scheduledJob),
+		queue:  jobQueue{},
+		wakeup: make(chan struct{}, 1),
+	}
+
+	go scheduler.run(ctx)
+
+	return scheduler
+}
+
+// Schedule agenda o job para o horário informado. Um agendamento existente
+// com a mesma chave é substituído.
+func (s *Scheduler) Schedule(key string, at time.Time, job Job) {
+	s.mu.Lock()
+	if current, ok := s.jobs[key]; ok {
+		current.at = at
+		current.job = job
+		heap.Fix(&s.queue, current.index)
+	} else {
+		scheduled := &scheduledJob{key: key, at: at, job: job}
+		heap.Push(&s.queue, scheduled)
+		s.jobs[key] = scheduled
+	}
+	s.mu.Unlock()
+
+	s.notify()
+}
+
+func (s *Scheduler) Cancel(key string) bool {
+	s.mu.Lock()
+	scheduled, ok := s.jobs[key]
+	if ok {
+		heap.Remove(&s.queue, scheduled.index)
+		delete(s.jobs, key)
+	}
+	s.mu.Unlock()
+
+	if ok {
+		s.notify()
+	}
+
+	return ok
+}
+
+func (s *Scheduler) ScheduledAt(key string) (time.Time, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	scheduled, ok := s.jobs[key]
+	if !ok {
+		return time.Time{}, false
+	}
+
+	return scheduled.at, true
+}
+
+func (s *Scheduler) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return len(s.queue)
+}
+
+func (s *Scheduler) notify() {
+	select {
+	case s.wakeup <- struct{}{}:
+	default:
+	}
+}
+
+func (s *Scheduler) run(ctx context.Context) {
+	for {
+		due, wait, hasNext := s.popDueJobs(time.Now())
+		for _, scheduled := range due {
+			go scheduled.job(ctx)
+		}
+
+		var timer *time.Timer
+		var timerChannel <-chan time.Time
+		if hasNext {
+			timer = time.NewTimer(wait)
+			timerChannel = timer.C
+		}
+
+		select {
+		case <-ctx.Done():
+			if timer != nil {
+				timer.Stop()
+			}
+			return
+		case <-s.wakeup:
+		case <-timerChannel:
+		}
+
+		if timer != nil {
+			timer.Stop()
+		}
+	}
+}
+
+func (s *Scheduler) popDueJobs(now time.Time) ([]*scheduledJob, time.Duration, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var due []*scheduledJob
+	for len(s.queue) > 0 && !s.queue[0].at.After(now) {
+		scheduled := heap.Pop(&s.queue).(*scheduledJob)
+		delete(s.jobs, scheduled.key)
+		due = append(due, scheduled)
+	}
+
+	if len(s.queue) == 0 {
+		return due, 0, false
+	}
+
+	return due, s.queue[0].at.Sub(now), true
+}
+
+type jobQueue []*scheduledJob
+
+func (q jobQueue) Len() int { return len(q) }
+
+func (q jobQueue) Less(i, j int) bool { return q[i].at.Before(q[j].at) }
+
+func (q jobQueue) Swap(i, j int) {
+	q[i], q[j] = q[j], q[i]
+	q[i].index = i
+	q[j].index = j
+}
+
+func (q *jobQueue) Push(x any) {
+	scheduled := x.(*scheduledJob)
+	scheduled.index = len(*q)
+	*q = append(*q, scheduled)
+}
+
+func (q *jobQueue) Pop() any {
+	old := *q
+	n := len(old)
+	scheduled := old[n-1]
+	old[n-1] = nil
+	scheduled.index = -1
+	*q = old[:n-1]
+	return scheduled
+}
@@ -0,0 +1,103 @@
+package scheduler
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSchedulerRunsJobsInOrder(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	s := NewScheduler(ctx)
+
+	var mu sync.Mutex
+	var executed []string
+	done := make(chan struct{}, 3)
+	record := func(key string) Job {
+		return func(ctx context.Context) {
+			mu.Lock()
+			executed = append(executed, key)
+			mu.Unlock()
+			done <- struct{}{}
+		}
+	}
+
+	now := time.Now()
+	s.Schedule("third", now.Add(150*time.Millisecond), record("third"))
+	s.Schedule("first", now.Add(30*time.Millisecond), record("first"))
+	s.Schedule("second", now.Add(90*time.Millisecond), record("second"))
+
+	for i := 0; i < 3; i++ {
+		select {
+		case <-done:
+		case <-time.After(2 * time.Second):
+			t.Fatal("timeout waiting for scheduled jobs")
+		}
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, []string{"first", "second", "third"}, executed)
+	assert.Equal(t, 0, s.Len())
+}
+
+func TestSchedulerRunsOverdueJobsImmediately(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	s := NewScheduler(ctx)
+
+	done := make(chan struct{}, 1)
+	s.Schedule("overdue", time.Now().Add(-time.Hour), func(ctx context.Context) {
+		done <- struct{}{}
+	})
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("overdue job was not executed")
+	}
+}
+
+func TestSchedulerReplacesAndCancelsJobs(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	s := NewScheduler(ctx)
+
+	executed := make(chan string, 2)
+	s.Schedule("auction", time.Now().Add(time.Hour), func(ctx context.Context) {
+		executed <- "old"
+	})
+	s.Schedule("auction", time.Now().Add(20*time.Millisecond), func(ctx context.Context) {
+		executed <- "new"
+	})
+	s.Schedule("cancelled", time.Now().Add(20*time.Millisecond), func(ctx context.Context) {
+		executed <- "cancelled"
+	})
+
+	assert.Equal(t, 2, s.Len())
+	assert.True(t, s.Cancel("cancelled"))
+	assert.False(t, s.Cancel("unknown"))
+
+	select {
+	case key := <-executed:
+		assert.Equal(t, "new", key)
+	case <-time.After(time.Second):
+		t.Fatal("rescheduled job was not executed")
+	}
+
+	select {
+	case key := <-executed:
+		t.Fatalf("unexpected job executed: %s", key)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	_, ok := s.ScheduledAt("auction")
+	assert.False(t, ok)
+}
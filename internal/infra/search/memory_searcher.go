@@ -0,0 +1,116 @@
+package search
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/entity/auction_entity"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/internal_error"
+	"golang.org/x/text/unicode/norm"
+)
+
+// MemorySearcher implementa a busca textual em memória, com os mesmos pesos
+// do índice de texto do MongoDB. Serve para testes e ambientes sem banco:
+// não há stemming, apenas termos inteiros sem acento e sem diferenciar caixa.
+type MemorySearcher struct {
+	mu       sync.RWMutex
+	auctions map[string]auction_entity.Auction
+}
+
+func NewMemorySearcher(auctions ...auction_entity.Auction) *MemorySearcher {
+	searcher := &MemorySearcher{auctions: make(map[string]auction_entity.Auction)}
+	for _, auction := range auctions {
+		searcher.Index(auction)
+	}
+
+	return searcher
+}
+
+func (s *MemorySearcher) Index(auction auction_entity.Auction) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.auctions[auction.Id] = auction
+}
+
+func (s *MemorySearcher) Remove(auctionId string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.auctions, auctionId)
+}
+
+func (s *MemorySearcher) SearchAuctions(
+	ctx context.Context,
+	query auction_entity.AuctionSearchQuery) ([]auction_entity.AuctionSearchResult, *internal_error.InternalError) {
+	terms := tokenize(query.Text)
+
+	s.mu.RLock()
+	var results []auction_entity.AuctionSearchResult
+	for _, auction := range s.auctions {
+		if query.Status != nil && auction.Status != *query.Status {
+			continue
+		}
+
+		if score := relevance(auction, terms); score > 0 {
+			results = append(results, auction_entity.AuctionSearchResult{Auction: auction, Score: score})
+		}
+	}
+	s.mu.RUnlock()
+
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		return results[i].Auction.Id < results[j].Auction.Id
+	})
+
+	if len(results) > query.Limit {
+		results = results[:query.Limit]
+	}
+
+	return results, nil
+}
+
+// relevance soma, para cada termo buscado, as ocorrências em cada campo
+// multiplicadas pelo peso do campo
+func relevance(auction auction_entity.Auction, terms []string) float64 {
+	fields := []struct {
+		tokens []string
+		weight float64
+	}{
+		{tokenize(auction.ProductName), auction_entity.ProductNameSearchWeight},
+		{tokenize(auction.Category), auction_entity.CategorySearchWeight},
+		{tokenize(auction.Description), auction_entity.DescriptionSearchWeight},
+	}
+
+	var score float64
+	for _, term := range terms {
+		for _, field := range fields {
+			for _, token := range field.tokens {
+				if token == term {
+					score += field.weight
+				}
+			}
+		}
+	}
+
+	return score
+}
+
+func tokenize(text string) []string {
+	var folded strings.Builder
+	for _, r := range norm.NFD.String(strings.ToLower(text)) {
+		if unicode.Is(unicode.Mn, r) {
+			continue
+		}
+		folded.WriteRune(r)
+	}
+
+	return strings.FieldsFunc(folded.String(), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+}
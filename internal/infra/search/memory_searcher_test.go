@@ -0,0 +1,51 @@
+package search
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/entity/auction_entity"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemorySearcherRanksByFieldWeight(t *testing.T) {
+	searcher := NewMemorySearcher(
+		auction_entity.Auction{Id: "description", ProductName: "Notebook",
+			Category: "Informática", Description: "Acompanha câmera externa"},
+		auction_entity.Auction{Id: "product", ProductName: "Câmera Canon",
+			Category: "Fotografia", Description: "Corpo e lente"},
+		auction_entity.Auction{Id: "unrelated", ProductName: "Bicicleta",
+			Category: "Esportes", Description: "Aro 29"},
+	)
+
+	results, err := searcher.SearchAuctions(context.Background(),
+		auction_entity.AuctionSearchQuery{Text: "CAMERA", Limit: 10})
+
+	assert.Nil(t, err)
+	assert.Len(t, results, 2)
+	assert.Equal(t, "product", results[0].Auction.Id)
+	assert.Equal(t, "description", results[1].Auction.Id)
+	assert.Greater(t, results[0].Score, results[1].Score)
+}
+
+func TestMemorySearcherFiltersAndLimits(t *testing.T) {
+	completed := auction_entity.Completed
+	searcher := NewMemorySearcher(
+		auction_entity.Auction{Id: "a", ProductName: "Guitarra", Status: auction_entity.Active},
+		auction_entity.Auction{Id: "b", ProductName: "Guitarra", Status: auction_entity.Completed},
+		auction_entity.Auction{Id: "c", ProductName: "Guitarra", Status: auction_entity.Completed},
+	)
+	searcher.Remove("c")
+
+	results, err := searcher.SearchAuctions(context.Background(),
+		auction_entity.AuctionSearchQuery{Text: "guitarra", Status: &completed, Limit: 10})
+	assert.Nil(t, err)
+	assert.Len(t, results, 1)
+	assert.Equal(t, "b", results[0].Auction.Id)
+
+	searcher.Index(auction_entity.Auction{Id: "d", ProductName: "Guitarra", Status: auction_entity.Active})
+	results, _ = searcher.SearchAuctions(context.Background(),
+		auction_entity.AuctionSearchQuery{Text: "guitarra", Limit: 1})
+	assert.Len(t, results, 1)
+	assert.Equal(t, "a", results[0].Auction.Id)
+}
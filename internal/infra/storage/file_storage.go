@@ -0,0 +1,97 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/configuration/logger"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/internal_error"
+)
+
+// FileStorage grava as fotos em disco. PublicURL é o prefixo pelo qual os
+// arquivos de Dir são servidos; quando é um caminho relativo, a própria
+// aplicação serve os arquivos.
+type FileStorage struct {
+	Dir       string
+	PublicURL string
+}
+
+func NewFileStorage(dir, publicURL string) (*FileStorage, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+
+	return &FileStorage{Dir: dir, PublicURL: publicURL}, nil
+}
+
+// PutObject escreve em um arquivo temporário e renomeia no final, para que
+// uma leitura concorrente nunca veja uma foto pela metade.
+func (fs *FileStorage) PutObject(
+	ctx context.Context, key, contentType string, data []byte) *internal_error.InternalError {
+	path, err := fs.path(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		logger.Error("Error trying to create storage directory", err)
+		return internal_error.NewInternalServerError("Error trying to store file")
+	}
+
+	temporary, createErr := os.CreateTemp(filepath.Dir(path), ".upload-*")
+	if createErr != nil {
+		logger.Error("Error trying to create storage file", createErr)
+		return internal_error.NewInternalServerError("Error trying to store file")
+	}
+	defer os.Remove(temporary.Name())
+
+	if _, writeErr := temporary.Write(data); writeErr != nil {
+		temporary.Close()
+		logger.Error("Error trying to write storage file", writeErr)
+		return internal_error.NewInternalServerError("Error trying to store file")
+	}
+
+	if closeErr := temporary.Close(); closeErr != nil {
+		logger.Error("Error trying to write storage file", closeErr)
+		return internal_error.NewInternalServerError("Error trying to store file")
+	}
+
+	if renameErr := os.Rename(temporary.Name(), path); renameErr != nil {
+		logger.Error("Error trying to move storage file", renameErr)
+		return internal_error.NewInternalServerError("Error trying to store file")
+	}
+
+	return nil
+}
+
+func (fs *FileStorage) DeleteObject(ctx context.Context, key string) *internal_error.InternalError {
+	path, err := fs.path(key)
+	if err != nil {
+		return err
+	}
+
+	if removeErr := os.Remove(path); removeErr != nil && !errors.Is(removeErr, os.ErrNotExist) {
+		logger.Error("Error trying to delete storage file", removeErr)
+		return internal_error.NewInternalServerError("Error trying to delete file")
+	}
+
+	return nil
+}
+
+func (fs *FileStorage) ObjectURL(key string) string {
+	return joinURL(fs.PublicURL, key)
+}
+
+// path recusa chaves que escapariam do diretório da storage.
+func (fs *FileStorage) path(key string) (string, *internal_error.InternalError) {
+	cleaned := filepath.Clean(filepath.FromSlash(key))
+	if key == "" || filepath.IsAbs(cleaned) || cleaned == ".." ||
+		strings.HasPrefix(cleaned, ".."+string(filepath.Separator)) {
+		return "", internal_error.NewBadRequestError("invalid storage key")
+	}
+
+	return filepath.Join(fs.Dir, cleaned), nil
+}
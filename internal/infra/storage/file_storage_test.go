@@ -0,0 +1,32 @@
+package storage
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFileStorage(t *testing.T) {
+	dir := t.TempDir()
+	fileStorage, err := NewFileStorage(dir, "/media/")
+	assert.NoError(t, err)
+	ctx := context.Background()
+
+	key := "auctions/1/photo.jpg"
+	assert.Nil(t, fileStorage.PutObject(ctx, key, "image/jpeg", []byte("jpeg bytes")))
+	assert.Equal(t, "/media/auctions/1/photo.jpg", fileStorage.ObjectURL(key))
+
+	data, readErr := os.ReadFile(filepath.Join(dir, "auctions", "1", "photo.jpg"))
+	assert.NoError(t, readErr)
+	assert.Equal(t, "jpeg bytes", string(data))
+
+	assert.Nil(t, fileStorage.DeleteObject(ctx, key))
+	assert.Nil(t, fileStorage.DeleteObject(ctx, key))
+	_, statErr := os.Stat(filepath.Join(dir, "auctions", "1", "photo.jpg"))
+	assert.True(t, os.IsNotExist(statErr))
+
+	assert.NotNil(t, fileStorage.PutObject(ctx, "../outside.jpg", "image/jpeg", []byte("jpeg bytes")))
+}
@@ -0,0 +1,138 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/configuration/logger"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/internal_error"
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultS3Region  = "us-east-1"
+	s3RequestTimeout = 30 * time.Second
+)
+
+type S3Config struct {
+	Endpoint        string
+	Region          string
+	Bucket          string
+	AccessKeyId     string
+	SecretAccessKey string
+	// PublicURL é o prefixo usado nas URLs das fotos (CDN ou bucket público);
+	// sem ele as URLs apontam para o próprio endpoint.
+	PublicURL string
+}
+
+// S3Storage grava as fotos em um bucket compatível com S3 (AWS, MinIO etc.)
+// pelo cliente minio-go, com endereçamento por caminho.
+type S3Storage struct {
+	client   *minio.Client
+	config   S3Config
+	endpoint *url.URL
+}
+
+func NewS3Storage(config S3Config) (*S3Storage, error) {
+	endpoint, err := url.Parse(config.Endpoint)
+	if err != nil || (endpoint.Scheme != "http" && endpoint.Scheme != "https") || endpoint.Host == "" {
+		return nil, errors.New("S3_ENDPOINT must be an absolute http or https URL")
+	}
+
+	if strings.Trim(endpoint.Path, "/") != "" {
+		return nil, errors.New("S3_ENDPOINT must not have a path")
+	}
+
+	if config.Bucket == "" || config.AccessKeyId == "" || config.SecretAccessKey == "" {
+		return nil, errors.New("S3_BUCKET, S3_ACCESS_KEY_ID and S3_SECRET_ACCESS_KEY are required")
+	}
+
+	if config.Region == "" {
+		config.Region = defaultS3Region
+	}
+
+	client, err := minio.New(endpoint.Host, &minio.Options{
+		Creds:        credentials.NewStaticV4(config.AccessKeyId, config.SecretAccessKey, ""),
+		Secure:       endpoint.Scheme == "https",
+		Region:       config.Region,
+		BucketLookup: minio.BucketLookupPath,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("invalid S3 configuration: %w", err)
+	}
+
+	return &S3Storage{
+		client:   client,
+		config:   config,
+		endpoint: endpoint,
+	}, nil
+}
+
+func (s *S3Storage) PutObject(
+	ctx context.Context, key, contentType string, data []byte) *internal_error.InternalError {
+	ctx, cancel := context.WithTimeout(ctx, s3RequestTimeout)
+	defer cancel()
+
+	_, err := s.client.PutObject(ctx, s.config.Bucket, key, bytes.NewReader(data), int64(len(data)),
+		minio.PutObjectOptions{ContentType: contentType})
+	if err != nil {
+		return storageError(err, http.MethodPut, key)
+	}
+
+	return nil
+}
+
+func (s *S3Storage) DeleteObject(ctx context.Context, key string) *internal_error.InternalError {
+	ctx, cancel := context.WithTimeout(ctx, s3RequestTimeout)
+	defer cancel()
+
+	err := s.client.RemoveObject(ctx, s.config.Bucket, key, minio.RemoveObjectOptions{})
+	// Remover um objeto que não existe não é erro
+	if err != nil && minio.ToErrorResponse(err).StatusCode != http.StatusNotFound {
+		return storageError(err, http.MethodDelete, key)
+	}
+
+	return nil
+}
+
+func (s *S3Storage) ObjectURL(key string) string {
+	if s.config.PublicURL != "" {
+		return joinURL(s.config.PublicURL, escapePath(key))
+	}
+
+	return fmt.Sprintf("%s://%s/%s/%s", s.endpoint.Scheme, s.endpoint.Host, s.config.Bucket, escapePath(key))
+}
+
+func storageError(err error, method, key string) *internal_error.InternalError {
+	logger.Error("File storage rejected request", err,
+		zap.String("method", method),
+		zap.String("key", key),
+		zap.Int("status", minio.ToErrorResponse(err).StatusCode),
+	)
+
+	return internal_error.NewInternalServerError("Error trying to access file storage")
+}
+
+// escapePath codifica cada segmento do caminho como o S3 espera,
+// preservando apenas os caracteres não reservados e as barras.
+func escapePath(path string) string {
+	var escaped strings.Builder
+	for _, b := range []byte(path) {
+		if ('A' <= b && b <= 'Z') || ('a' <= b && b <= 'z') || ('0' <= b && b <= '9') ||
+			b == '-' || b == '_' || b == '.' || b == '~' || b == '/' {
+			escaped.WriteByte(b)
+		} else {
+			fmt.Fprintf(&escaped, "%%%02X", b)
+		}
+	}
+
+	return escaped.String()
+}
@@ -0,0 +1,158 @@
+package storage
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeS3 imita um servidor compatível com S3 (como o MinIO) com um bucket
+// público para leitura.
+type fakeS3 struct {
+	mu      sync.Mutex
+	objects map[string][]byte
+	types   map[string]string
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	switch r.Method {
+	case http.MethodGet:
+		object, ok := f.objects[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", f.types[r.URL.Path])
+		w.Write(object)
+		return
+	}
+
+	if !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=minio-access/") {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodPut:
+		body, ok := readPayload(r)
+		if !ok {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		f.objects[r.URL.Path] = body
+		f.types[r.URL.Path] = r.Header.Get("Content-Type")
+		w.WriteHeader(http.StatusOK)
+	case http.MethodDelete:
+		delete(f.objects, r.URL.Path)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+// readPayload aceita o corpo assinado inteiro ou enviado em blocos
+// (aws-chunked), como o minio-go faz sem TLS.
+func readPayload(r *http.Request) ([]byte, bool) {
+	body, _ := io.ReadAll(r.Body)
+	if r.Header.Get("X-Amz-Content-Sha256") != "STREAMING-AWS4-HMAC-SHA256-PAYLOAD" {
+		sum := sha256.Sum256(body)
+		return body, r.Header.Get("X-Amz-Content-Sha256") == hex.EncodeToString(sum[:])
+	}
+
+	var payload []byte
+	reader := bufio.NewReader(bytes.NewReader(body))
+	for {
+		header, err := reader.ReadString('\n')
+		if err != nil {
+			return nil, false
+		}
+		size, err := strconv.ParseInt(strings.SplitN(header, ";", 2)[0], 16, 64)
+		if err != nil {
+			return nil, false
+		}
+
+		chunk := make([]byte, size+2)
+		if _, err := io.ReadFull(reader, chunk); err != nil {
+			return nil, false
+		}
+		if size == 0 {
+			break
+		}
+		payload = append(payload, chunk[:size]...)
+	}
+
+	return payload, strconv.Itoa(len(payload)) == r.Header.Get("X-Amz-Decoded-Content-Length")
+}
+
+func TestS3StorageAgainstCompatibleServer(t *testing.T) {
+	fake := &fakeS3{objects: make(map[string][]byte), types: make(map[string]string)}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	s3Storage, err := NewS3Storage(S3Config{
+		Endpoint:        server.URL,
+		Bucket:          "auctions",
+		AccessKeyId:     "minio-access",
+		SecretAccessKey: "minio-secret",
+	})
+	assert.NoError(t, err)
+	ctx := context.Background()
+
+	key := "auctions/1/photo.jpg"
+	assert.Nil(t, s3Storage.PutObject(ctx, key, "image/jpeg", []byte("jpeg bytes")))
+	assert.Equal(t, server.URL+"/auctions/auctions/1/photo.jpg", s3Storage.ObjectURL(key))
+
+	response, getErr := http.Get(s3Storage.ObjectURL(key))
+	assert.NoError(t, getErr)
+	body, _ := io.ReadAll(response.Body)
+	response.Body.Close()
+	assert.Equal(t, "jpeg bytes", string(body))
+	assert.Equal(t, "image/jpeg", response.Header.Get("Content-Type"))
+
+	assert.Nil(t, s3Storage.DeleteObject(ctx, key))
+	assert.Nil(t, s3Storage.DeleteObject(ctx, key))
+	assert.Empty(t, fake.objects)
+
+	rejected, err := NewS3Storage(S3Config{
+		Endpoint:        server.URL,
+		Bucket:          "auctions",
+		AccessKeyId:     "another-access",
+		SecretAccessKey: "minio-secret",
+	})
+	assert.NoError(t, err)
+	assert.NotNil(t, rejected.PutObject(ctx, key, "image/jpeg", []byte("jpeg bytes")))
+}
+
+func TestS3StoragePublicURL(t *testing.T) {
+	s3Storage, err := NewS3Storage(S3Config{
+		Endpoint:        "https://s3.example.com",
+		Bucket:          "auctions",
+		AccessKeyId:     "access",
+		SecretAccessKey: "secret",
+		PublicURL:       "https://cdn.example.com/",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "https://cdn.example.com/auctions/1/photo%201.jpg", s3Storage.ObjectURL("auctions/1/photo 1.jpg"))
+
+	_, err = NewS3Storage(S3Config{Endpoint: "s3.example.com", Bucket: "auctions"})
+	assert.Error(t, err)
+
+	_, err = NewS3Storage(S3Config{
+		Endpoint: "https://s3.example.com/prefix", Bucket: "auctions", AccessKeyId: "access", SecretAccessKey: "secret",
+	})
+	assert.Error(t, err)
+}
@@ -0,0 +1,45 @@
+package storage
+
+import (
+	"os"
+	"strings"
+
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/entity/auction_entity"
+)
+
+const (
+	defaultStorageDir       = "media"
+	defaultStoragePublicURL = "/media"
+)
+
+// NewImageStorageFromEnv escolhe onde as fotos dos leilões são gravadas:
+// STORAGE_DRIVER=s3 usa um bucket compatível com S3; qualquer outro valor
+// grava em disco, em STORAGE_DIR.
+func NewImageStorageFromEnv() (auction_entity.ImageStorage, error) {
+	if strings.EqualFold(os.Getenv("STORAGE_DRIVER"), "s3") {
+		return NewS3Storage(S3Config{
+			Endpoint:        os.Getenv("S3_ENDPOINT"),
+			Region:          os.Getenv("S3_REGION"),
+			Bucket:          os.Getenv("S3_BUCKET"),
+			AccessKeyId:     os.Getenv("S3_ACCESS_KEY_ID"),
+			SecretAccessKey: os.Getenv("S3_SECRET_ACCESS_KEY"),
+			PublicURL:       os.Getenv("S3_PUBLIC_URL"),
+		})
+	}
+
+	return NewFileStorage(
+		envOrDefault("STORAGE_DIR", defaultStorageDir),
+		envOrDefault("STORAGE_PUBLIC_URL", defaultStoragePublicURL))
+}
+
+func envOrDefault(name, defaultValue string) string {
+	if value := os.Getenv(name); value != "" {
+		return value
+	}
+
+	return defaultValue
+}
+
+func joinURL(baseURL, key string) string {
+	return strings.TrimRight(baseURL, "/") + "/" + key
+}
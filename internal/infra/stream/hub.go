@@ -0,0 +1,132 @@
+package stream
+
+import (
+	"sync"
+	"time"
+)
+
+type EventType string
+
+const (
+	BidAccepted       EventType = "bid_accepted"
+	HighestBidChanged EventType = "highest_bid_changed"
+	AuctionExtended   EventType = "auction_extended"
+	AuctionClosed     EventType = "auction_closed"
+	AuctionCancelled  EventType = "auction_cancelled"
+)
+
+const subscriptionBufferSize = 32
+
+type Event struct {
+	Type      EventType   `json:"type"`
+	AuctionId string      `json:"auction_id"`
+	Data      interface{} `json:"data,omitempty"`
+	Timestamp time.Time   `json:"timestamp"`
+}
+
+type Subscription struct {
+	AuctionId string
+	Events    <-chan Event
+
+	events chan Event
+}
+
+// Hub distribui os eventos de cada leilão para todos os espectadores
+// conectados, mantendo o último maior lance para quem acabou de chegar.
+type Hub struct {
+	mu            sync.RWMutex
+	subscriptions map[string]map[*Subscription]struct{}
+	lastHighest   map[string]Event
+}
+
+func NewHub() *Hub {
+	return &Hub{
+		subscriptions: make(map[string]map[*Subscription]struct{}),
+		lastHighest:   make(map[string]Event),
+	}
+}
+
+func (h *Hub) Subscribe(auctionId string) *Subscription {
+	events := make(chan Event, subscriptionBufferSize)
+	subscription := &Subscription{
+		AuctionId: auctionId,
+		Events:    events,
+		events:    events,
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if _, ok := h.subscriptions[auctionId]; !ok {
+		h.subscriptions[auctionId] = make(map[*Subscription]struct{})
+	}
+	h.subscriptions[auctionId][subscription] = struct{}{}
+
+	if snapshot, ok := h.lastHighest[auctionId]; ok {
+		events <- snapshot
+	}
+
+	return subscription
+}
+
+func (h *Hub) Unsubscribe(subscription *Subscription) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.remove(subscription)
+}
+
+// Publish nunca bloqueia quem produz o evento: espectadores que não
+// acompanham o ritmo são desconectados e precisam se inscrever novamente.
+func (h *Hub) Publish(event Event) {
+	if h == nil {
+		return
+	}
+
+	if event.Timestamp.IsZero() {
+		event.Timestamp = time.Now()
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	switch event.Type {
+	case HighestBidChanged:
+		h.lastHighest[event.AuctionId] = event
+	case AuctionClosed, AuctionCancelled:
+		delete(h.lastHighest, event.AuctionId)
+	}
+
+	for subscription := range h.subscriptions[event.AuctionId] {
+		select {
+		case subscription.events <- event:
+		default:
+			h.remove(subscription)
+		}
+	}
+}
+
+func (h *Hub) SubscriberCount(auctionId string) int {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	return len(h.subscriptions[auctionId])
+}
+
+func (h *Hub) remove(subscription *Subscription) {
+	subscriptions, ok := h.subscriptions[subscription.AuctionId]
+	if !ok {
+		return
+	}
+
+	if _, ok := subscriptions[subscription]; !ok {
+		return
+	}
+
+	delete(subscriptions, subscription)
+	close(subscription.events)
+
+	if len(subscriptions) == 0 {
+		delete(h.subscriptions, subscription.AuctionId)
+	}
+}
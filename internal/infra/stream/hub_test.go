@@ -0,0 +1,69 @@
+package stream
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHubFanOut(t *testing.T) {
+	hub := NewHub()
+
+	first := hub.Subscribe("auction")
+	second := hub.Subscribe("auction")
+	other := hub.Subscribe("other-auction")
+
+	hub.Publish(Event{Type: BidAccepted, AuctionId: "auction"})
+
+	assert.Equal(t, BidAccepted, (<-first.Events).Type)
+	assert.Equal(t, BidAccepted, (<-second.Events).Type)
+	assert.Len(t, other.Events, 0)
+	assert.Equal(t, 2, hub.SubscriberCount("auction"))
+
+	hub.Unsubscribe(first)
+	_, open := <-first.Events
+	assert.False(t, open)
+	assert.Equal(t, 1, hub.SubscriberCount("auction"))
+}
+
+func TestHubSendsLastHighestBidToNewSubscribers(t *testing.T) {
+	hub := NewHub()
+
+	hub.Publish(Event{Type: HighestBidChanged, AuctionId: "auction", Data: 10.0})
+	hub.Publish(Event{Type: HighestBidChanged, AuctionId: "auction", Data: 20.0})
+
+	subscription := hub.Subscribe("auction")
+	snapshot := <-subscription.Events
+	assert.Equal(t, HighestBidChanged, snapshot.Type)
+	assert.Equal(t, 20.0, snapshot.Data)
+
+	hub.Publish(Event{Type: AuctionClosed, AuctionId: "auction"})
+	assert.Equal(t, AuctionClosed, (<-subscription.Events).Type)
+
+	late := hub.Subscribe("auction")
+	assert.Len(t, late.Events, 0)
+}
+
+func TestHubDisconnectsSlowSubscribers(t *testing.T) {
+	hub := NewHub()
+	subscription := hub.Subscribe("auction")
+
+	for i := 0; i <= subscriptionBufferSize; i++ {
+		hub.Publish(Event{Type: BidAccepted, AuctionId: "auction"})
+	}
+
+	assert.Equal(t, 0, hub.SubscriberCount("auction"))
+
+	received := 0
+	for range subscription.Events {
+		received++
+	}
+	assert.Equal(t, subscriptionBufferSize, received)
+}
+
+func TestNilHubIgnoresEvents(t *testing.T) {
+	var hub *Hub
+	assert.NotPanics(t, func() {
+		hub.Publish(Event{Type: BidAccepted, AuctionId: "auction"})
+	})
+}
@@ -0,0 +1,85 @@
+package stream
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/entity/bid_entity"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/event"
+)
+
+type BidData struct {
+	Id            string      `json:"id"`
+	UserId        string      `json:"user_id"`
+	AuctionId     string      `json:"auction_id"`
+	Amount        json.Number `json:"amount"`
+	Currency      string      `json:"currency"`
+	Timestamp     time.Time   `json:"timestamp" time_format:"2006-01-02 15:04:05"`
+	Automatic     bool        `json:"automatic"`
+	BuyNow        bool        `json:"buy_now,omitempty"`
+	DutchPurchase bool        `json:"dutch_purchase,omitempty"`
+}
+
+type AuctionExtendedData struct {
+	EndTime time.Time `json:"end_time" time_format:"2006-01-02 15:04:05"`
+}
+
+// SubscribeTo inscreve o hub nos eventos de domínio que interessam aos espectadores
+func (h *Hub) SubscribeTo(bus *event.Bus) {
+	bus.Subscribe(h.HandleEvent,
+		event.BidAcceptedEvent,
+		event.HighestBidChangedEvent,
+		event.AuctionExtendedEvent,
+		event.AuctionClosedEvent,
+		event.AuctionCancelledEvent)
+}
+
+func (h *Hub) HandleEvent(ctx context.Context, e event.Event) {
+	switch domainEvent := e.(type) {
+	case event.BidAccepted:
+		h.Publish(Event{
+			Type:      BidAccepted,
+			AuctionId: domainEvent.Bid.AuctionId,
+			Data:      toBidData(domainEvent.Bid),
+		})
+	case event.HighestBidChanged:
+		h.Publish(Event{
+			Type:      HighestBidChanged,
+			AuctionId: domainEvent.Bid.AuctionId,
+			Data:      toBidData(domainEvent.Bid),
+		})
+	case event.AuctionExtended:
+		h.Publish(Event{
+			Type:      AuctionExtended,
+			AuctionId: domainEvent.AuctionId,
+			Data:      AuctionExtendedData{EndTime: domainEvent.EndTime},
+		})
+	case event.AuctionClosed:
+		h.Publish(Event{
+			Type:      AuctionClosed,
+			AuctionId: domainEvent.AuctionId,
+			Timestamp: domainEvent.ClosedAt,
+		})
+	case event.AuctionCancelled:
+		h.Publish(Event{
+			Type:      AuctionCancelled,
+			AuctionId: domainEvent.AuctionId,
+			Timestamp: domainEvent.CancelledAt,
+		})
+	}
+}
+
+func toBidData(bid bid_entity.Bid) BidData {
+	return BidData{
+		Id:            bid.Id,
+		UserId:        bid.UserId,
+		AuctionId:     bid.AuctionId,
+		Amount:        json.Number(bid.Amount.String()),
+		Currency:      bid.Amount.Currency,
+		Timestamp:     bid.Timestamp,
+		Automatic:     bid.Automatic,
+		BuyNow:        bid.BuyNow,
+		DutchPurchase: bid.DutchPurchase,
+	}
+}
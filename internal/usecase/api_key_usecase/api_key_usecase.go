@@ -0,0 +1,116 @@
+package api_key_usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/entity/api_key_entity"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/entity/user_entity"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/internal_error"
+)
+
+type ApiKeyInputDTO struct {
+	Name   string   `json:"name" binding:"required,min=1,max=100"`
+	UserId string   `json:"user_id" binding:"required,uuid"`
+	Scopes []string `json:"scopes" binding:"required,min=1,dive,oneof=auction:read auction:write bid:write"`
+}
+
+type ApiKeyOutputDTO struct {
+	Id        string     `json:"id"`
+	Name      string     `json:"name"`
+	UserId    string     `json:"user_id"`
+	Prefix    string     `json:"prefix"`
+	Scopes    []string   `json:"scopes"`
+	CreatedAt time.Time  `json:"created_at" time_format:"2006-01-02 15:04:05"`
+	RevokedAt *time.Time `json:"revoked_at,omitempty" time_format:"2006-01-02 15:04:05"`
+}
+
+// IssuedApiKeyOutputDTO é a única resposta que traz a chave em texto
+type IssuedApiKeyOutputDTO struct {
+	ApiKeyOutputDTO
+	Key string `json:"key"`
+}
+
+type ApiKeyUseCase struct {
+	apiKeyRepository api_key_entity.ApiKeyRepositoryInterface
+	userRepository   user_entity.UserRepositoryInterface
+}
+
+func NewApiKeyUseCase(
+	apiKeyRepository api_key_entity.ApiKeyRepositoryInterface,
+	userRepository user_entity.UserRepositoryInterface) ApiKeyUseCaseInterface {
+	return &ApiKeyUseCase{
+		apiKeyRepository: apiKeyRepository,
+		userRepository:   userRepository,
+	}
+}
+
+type ApiKeyUseCaseInterface interface {
+	IssueApiKey(
+		ctx context.Context,
+		apiKeyInput ApiKeyInputDTO) (*IssuedApiKeyOutputDTO, *internal_error.InternalError)
+
+	FindApiKeys(ctx context.Context) ([]ApiKeyOutputDTO, *internal_error.InternalError)
+
+	RevokeApiKey(ctx context.Context, id string) *internal_error.InternalError
+}
+
+func (au *ApiKeyUseCase) IssueApiKey(
+	ctx context.Context,
+	apiKeyInput ApiKeyInputDTO) (*IssuedApiKeyOutputDTO, *internal_error.InternalError) {
+	// A chave age em nome do usuário, então ele precisa existir
+	if _, err := au.userRepository.FindUserById(ctx, apiKeyInput.UserId); err != nil {
+		if err.Err == "not_found" {
+			return nil, internal_error.NewBadRequestError("user_id does not match a registered user")
+		}
+		return nil, err
+	}
+
+	apiKey, plainKey, err := api_key_entity.IssueApiKey(apiKeyInput.Name, apiKeyInput.UserId, apiKeyInput.Scopes)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := au.apiKeyRepository.CreateApiKey(ctx, apiKey); err != nil {
+		return nil, err
+	}
+
+	return &IssuedApiKeyOutputDTO{
+		ApiKeyOutputDTO: toApiKeyOutputDTO(*apiKey),
+		Key:             plainKey,
+	}, nil
+}
+
+func (au *ApiKeyUseCase) FindApiKeys(ctx context.Context) ([]ApiKeyOutputDTO, *internal_error.InternalError) {
+	apiKeys, err := au.apiKeyRepository.FindApiKeys(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	apiKeyOutputs := make([]ApiKeyOutputDTO, 0, len(apiKeys))
+	for _, apiKey := range apiKeys {
+		apiKeyOutputs = append(apiKeyOutputs, toApiKeyOutputDTO(apiKey))
+	}
+
+	return apiKeyOutputs, nil
+}
+
+func (au *ApiKeyUseCase) RevokeApiKey(ctx context.Context, id string) *internal_error.InternalError {
+	return au.apiKeyRepository.RevokeApiKey(ctx, id)
+}
+
+func toApiKeyOutputDTO(apiKey api_key_entity.ApiKey) ApiKeyOutputDTO {
+	apiKeyOutput := ApiKeyOutputDTO{
+		Id:        apiKey.Id,
+		Name:      apiKey.Name,
+		UserId:    apiKey.UserId,
+		Prefix:    apiKey.Prefix,
+		Scopes:    apiKey.Scopes,
+		CreatedAt: apiKey.CreatedAt,
+	}
+	if apiKey.Revoked() {
+		apiKeyOutput.RevokedAt = &apiKey.RevokedAt
+	}
+
+	return apiKeyOutput
+}
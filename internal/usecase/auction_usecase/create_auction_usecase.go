@@ -2,42 +2,118 @@ package auction_usecase
 
 import (
 	"context"
+	"encoding/json"
 	"time"
 
 	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/entity/auction_entity"
 	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/entity/bid_entity"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/entity/category_entity"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/entity/money_entity"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/event"
 	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/internal_error"
 	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/usecase/bid_usecase"
 )
 
 type AuctionInputDTO struct {
+	SellerId    string           `json:"-"`
 	ProductName string           `json:"product_name" binding:"required,min=1"`
-	Category    string           `json:"category" binding:"required,min=2"`
+	CategoryId  string           `json:"category_id" binding:"required,uuid"`
 	Description string           `json:"description" binding:"required,min=10,max=200"`
 	Condition   ProductCondition `json:"condition" binding:"oneof=1 2 3"`
+	Duration    string           `json:"duration,omitempty"`
+	StartsAt    *time.Time       `json:"starts_at,omitempty"`
+	EndsAt      *time.Time       `json:"ends_at,omitempty"`
+
+	// Valores são decimais exatos na moeda do leilão; o incremento percentual
+	// aceita até duas casas (2.5 = 2,5%)
+	Currency      string        `json:"currency" binding:"omitempty,iso4217"`
+	StartingPrice json.Number   `json:"starting_price"`
+	MinIncrement  json.Number   `json:"min_increment"`
+	IncrementType IncrementType `json:"increment_type" binding:"oneof=0 1"`
+	ReservePrice  json.Number   `json:"reserve_price"`
+	BuyNowPrice   json.Number   `json:"buy_now_price"`
+
+	// Leilões holandeses partem do starting_price e caem conforme price_schedule
+	Type          AuctionType            `json:"type" binding:"oneof=0 1"`
+	PriceSchedule *PriceScheduleInputDTO `json:"price_schedule,omitempty"`
+}
+
+type PriceScheduleInputDTO struct {
+	FloorPrice json.Number `json:"floor_price"`
+	Decrement  json.Number `json:"decrement"`
+	Interval   string      `json:"interval" binding:"required"`
 }
 
 type AuctionOutputDTO struct {
 	Id          string           `json:"id"`
+	SellerId    string           `json:"seller_id,omitempty"`
 	ProductName string           `json:"product_name"`
+	CategoryId  string           `json:"category_id,omitempty"`
 	Category    string           `json:"category"`
 	Description string           `json:"description"`
 	Condition   ProductCondition `json:"condition"`
 	Status      AuctionStatus    `json:"status"`
 	Timestamp   time.Time        `json:"timestamp" time_format:"2006-01-02 15:04:05"`
+	StartsAt    *time.Time       `json:"starts_at,omitempty" time_format:"2006-01-02 15:04:05"`
+	EndTime     time.Time        `json:"end_time" time_format:"2006-01-02 15:04:05"`
+
+	Currency      string        `json:"currency"`
+	StartingPrice json.Number   `json:"starting_price"`
+	MinIncrement  json.Number   `json:"min_increment"`
+	IncrementType IncrementType `json:"increment_type"`
+	HasReserve    bool          `json:"has_reserve"`
+	BuyNowPrice   json.Number   `json:"buy_now_price,omitempty"`
+
+	Type          AuctionType             `json:"type"`
+	PriceSchedule *PriceScheduleOutputDTO `json:"price_schedule,omitempty"`
+
+	HighestBidAmount json.Number `json:"highest_bid_amount"`
+	BidCount         int64       `json:"bid_count"`
+
+	Images []AuctionImageOutputDTO `json:"images,omitempty"`
+}
+
+type PriceScheduleOutputDTO struct {
+	FloorPrice   json.Number `json:"floor_price"`
+	Decrement    json.Number `json:"decrement"`
+	Interval     string      `json:"interval"`
+	CurrentPrice json.Number `json:"current_price"`
+}
+
+type AuctionQueryInputDTO struct {
+	Status      *AuctionStatus `form:"status"`
+	Category    string         `form:"category"`
+	ProductName string         `form:"productName"`
+	Sort        string         `form:"sort" binding:"omitempty,oneof=newest ending_soonest highest_bid most_bids"`
+	Limit       int            `form:"limit" binding:"omitempty,min=1,max=100"`
+	PageToken   string         `form:"page_token"`
+}
+
+type AuctionPageOutputDTO struct {
+	Items         []AuctionOutputDTO `json:"items"`
+	NextPageToken string             `json:"next_page_token,omitempty"`
 }
 
 type WinningInfoOutputDTO struct {
-	Auction AuctionOutputDTO          `json:"auction"`
-	Bid     *bid_usecase.BidOutputDTO `json:"bid,omitempty"`
+	Auction    AuctionOutputDTO          `json:"auction"`
+	Bid        *bid_usecase.BidOutputDTO `json:"bid,omitempty"`
+	ReserveMet bool                      `json:"reserve_met"`
 }
 
 func NewAuctionUseCase(
 	auctionRepositoryInterface auction_entity.AuctionRepositoryInterface,
-	bidRepositoryInterface bid_entity.BidEntityRepository) AuctionUseCaseInterface {
+	bidRepositoryInterface bid_entity.BidEntityRepository,
+	categoryRepositoryInterface category_entity.CategoryRepositoryInterface,
+	auctionSearcherInterface auction_entity.AuctionSearcherInterface,
+	imageStorage auction_entity.ImageStorage,
+	eventBus *event.Bus) AuctionUseCaseInterface {
 	return &AuctionUseCase{
-		auctionRepositoryInterface: auctionRepositoryInterface,
-		bidRepositoryInterface:     bidRepositoryInterface,
+		auctionRepositoryInterface:  auctionRepositoryInterface,
+		bidRepositoryInterface:      bidRepositoryInterface,
+		categoryRepositoryInterface: categoryRepositoryInterface,
+		auctionSearcherInterface:    auctionSearcherInterface,
+		imageStorage:                imageStorage,
+		eventBus:                    eventBus,
 	}
 }
 
@@ -51,38 +127,237 @@ type AuctionUseCaseInterface interface {
 
 	FindAuctions(
 		ctx context.Context,
-		status AuctionStatus,
-		category, productName string) ([]AuctionOutputDTO, *internal_error.InternalError)
+		queryInput AuctionQueryInputDTO) (*AuctionPageOutputDTO, *internal_error.InternalError)
 
 	FindWinningBidByAuctionId(
 		ctx context.Context,
 		auctionId string) (*WinningInfoOutputDTO, *internal_error.InternalError)
+
+	SearchAuctions(
+		ctx context.Context,
+		searchInput AuctionSearchInputDTO) (*AuctionSearchOutputDTO, *internal_error.InternalError)
+
+	FindAuctionsBySeller(
+		ctx context.Context,
+		sellerId string,
+		queryInput SellerAuctionQueryInputDTO) (*SellerAuctionPageOutputDTO, *internal_error.InternalError)
+
+	UpdateAuction(
+		ctx context.Context,
+		auctionId, sellerId string,
+		auctionInput AuctionUpdateInputDTO) (*AuctionOutputDTO, *internal_error.InternalError)
+
+	CancelAuction(
+		ctx context.Context, auctionId, userId string, admin bool) *internal_error.InternalError
+
+	AddAuctionImage(
+		ctx context.Context,
+		auctionId, sellerId string,
+		data []byte) (*AuctionImageOutputDTO, *internal_error.InternalError)
+
+	RemoveAuctionImage(
+		ctx context.Context, auctionId, imageId, sellerId string) *internal_error.InternalError
 }
 
+// Incrementos percentuais têm duas casas decimais, guardados em pontos-base
+const percentageScale = 2
+
 type ProductCondition int64
 type AuctionStatus int64
+type IncrementType int64
+type AuctionType int64
 
 type AuctionUseCase struct {
-	auctionRepositoryInterface auction_entity.AuctionRepositoryInterface
-	bidRepositoryInterface     bid_entity.BidEntityRepository
+	auctionRepositoryInterface  auction_entity.AuctionRepositoryInterface
+	bidRepositoryInterface      bid_entity.BidEntityRepository
+	categoryRepositoryInterface category_entity.CategoryRepositoryInterface
+	auctionSearcherInterface    auction_entity.AuctionSearcherInterface
+	imageStorage                auction_entity.ImageStorage
+	eventBus                    *event.Bus
 }
 
 func (au *AuctionUseCase) CreateAuction(
 	ctx context.Context,
 	auctionInput AuctionInputDTO) *internal_error.InternalError {
+	category, err := au.findCategory(ctx, auctionInput.CategoryId)
+	if err != nil {
+		return err
+	}
+
 	auction, err := auction_entity.CreateAuction(
+		auctionInput.SellerId,
 		auctionInput.ProductName,
-		auctionInput.Category,
+		category.Name,
 		auctionInput.Description,
 		auction_entity.ProductCondition(auctionInput.Condition))
 	if err != nil {
 		return err
 	}
 
+	if err := auction.SetCategory(category.Id, category.Name); err != nil {
+		return err
+	}
+
+	currency := auctionInput.Currency
+	if currency == "" {
+		currency = money_entity.DefaultCurrency
+	}
+
+	startingPrice, err := money_entity.Parse(auctionInput.StartingPrice.String(), currency)
+	if err != nil {
+		return err
+	}
+
+	minIncrement, err := auctionInput.minIncrement(currency)
+	if err != nil {
+		return err
+	}
+
+	if err := auction.SetBidRules(
+		startingPrice,
+		minIncrement,
+		auction_entity.IncrementType(auctionInput.IncrementType)); err != nil {
+		return err
+	}
+
+	reservePrice, err := money_entity.Parse(auctionInput.ReservePrice.String(), currency)
+	if err != nil {
+		return err
+	}
+
+	if err := auction.SetReservePrice(reservePrice); err != nil {
+		return err
+	}
+
+	buyNowPrice, err := money_entity.Parse(auctionInput.BuyNowPrice.String(), currency)
+	if err != nil {
+		return err
+	}
+
+	if err := auction.SetBuyNowPrice(buyNowPrice); err != nil {
+		return err
+	}
+
+	if err := auctionInput.applyPriceSchedule(auction, currency); err != nil {
+		return err
+	}
+
+	if auctionInput.StartsAt != nil {
+		if err := auction.Schedule(*auctionInput.StartsAt); err != nil {
+			return err
+		}
+	}
+
+	if auctionInput.Duration != "" || auctionInput.EndsAt != nil {
+		endTime, err := auctionInput.endTime(auction.OpensAt())
+		if err != nil {
+			return err
+		}
+
+		if err := auction.SetEndTime(endTime); err != nil {
+			return err
+		}
+	} else if auction.Type == auction_entity.DutchAuction {
+		// Sem término informado, o leilão fica um intervalo inteiro no preço mínimo
+		if err := auction.SetEndTime(
+			auction.FloorReachedAt().Add(auction.PriceSchedule.Interval)); err != nil {
+			return err
+		}
+	}
+
 	if err := au.auctionRepositoryInterface.CreateAuction(
 		ctx, auction); err != nil {
 		return err
 	}
 
+	au.eventBus.Publish(ctx, event.AuctionCreated{Auction: *auction})
+
 	return nil
 }
+
+// minIncrement converte o incremento para unidades menores da moeda ou, se
+// percentual, para pontos-base.
+func (input AuctionInputDTO) minIncrement(currency string) (int64, *internal_error.InternalError) {
+	if input.IncrementType == IncrementType(auction_entity.PercentageIncrement) {
+		return money_entity.ParseDecimal(input.MinIncrement.String(), percentageScale)
+	}
+
+	minIncrement, err := money_entity.Parse(input.MinIncrement.String(), currency)
+	if err != nil {
+		return 0, err
+	}
+
+	return minIncrement.Units, nil
+}
+
+// formatMinIncrement é o inverso de minIncrement, para as respostas.
+func formatMinIncrement(auction *auction_entity.Auction) json.Number {
+	if auction.IncrementType == auction_entity.PercentageIncrement {
+		return json.Number(money_entity.FormatDecimal(auction.MinIncrement, percentageScale))
+	}
+
+	return json.Number(money_entity.New(auction.MinIncrement, auction.Currency).String())
+}
+
+func (input AuctionInputDTO) applyPriceSchedule(
+	auction *auction_entity.Auction, currency string) *internal_error.InternalError {
+	if input.Type != AuctionType(auction_entity.DutchAuction) {
+		if input.PriceSchedule != nil {
+			return internal_error.NewBadRequestError("price_schedule is only allowed for dutch auctions")
+		}
+		return nil
+	}
+
+	if input.PriceSchedule == nil {
+		return internal_error.NewBadRequestError("price_schedule is required for dutch auctions")
+	}
+
+	floorPrice, err := money_entity.Parse(input.PriceSchedule.FloorPrice.String(), currency)
+	if err != nil {
+		return err
+	}
+
+	decrement, err := money_entity.Parse(input.PriceSchedule.Decrement.String(), currency)
+	if err != nil {
+		return err
+	}
+
+	interval, parseErr := time.ParseDuration(input.PriceSchedule.Interval)
+	if parseErr != nil {
+		return internal_error.NewBadRequestError("price_schedule interval is not a valid value")
+	}
+
+	return auction.SetPriceSchedule(floorPrice, decrement.Units, interval)
+}
+
+func (input AuctionInputDTO) endTime(start time.Time) (time.Time, *internal_error.InternalError) {
+	if input.Duration != "" && input.EndsAt != nil {
+		return time.Time{}, internal_error.NewBadRequestError("inform either duration or ends_at, not both")
+	}
+
+	if input.EndsAt != nil {
+		return *input.EndsAt, nil
+	}
+
+	duration, err := time.ParseDuration(input.Duration)
+	if err != nil || duration <= 0 {
+		return time.Time{}, internal_error.NewBadRequestError("duration is not a valid value")
+	}
+
+	return start.Add(duration), nil
+}
+
+// findCategory resolve a categoria informada pelo vendedor; uma categoria
+// inexistente é erro de entrada, não recurso ausente.
+func (au *AuctionUseCase) findCategory(
+	ctx context.Context, categoryId string) (*category_entity.Category, *internal_error.InternalError) {
+	category, err := au.categoryRepositoryInterface.FindCategoryById(ctx, categoryId)
+	if err != nil {
+		if err.Err == "not_found" {
+			return nil, internal_error.NewBadRequestError("Category does not exist")
+		}
+		return nil, err
+	}
+
+	return category, nil
+}
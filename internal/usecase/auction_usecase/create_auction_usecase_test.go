@@ -0,0 +1,86 @@
+package auction_usecase
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/entity/auction_entity"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateScheduledAuction(t *testing.T) {
+	auctionRepository := &auctionRepositoryMock{}
+	useCase := NewAuctionUseCase(auctionRepository, &bidRepositoryMock{}, categoryRepository, nil, nil, nil)
+
+	startsAt := time.Now().Add(72 * time.Hour).Truncate(time.Second)
+	err := useCase.CreateAuction(context.Background(), AuctionInputDTO{
+		SellerId:    sellerId,
+		ProductName: "Vintage camera",
+		CategoryId:  phonesId,
+		Description: "Vintage camera in working condition",
+		Condition:   ProductCondition(auction_entity.Used),
+		StartsAt:    &startsAt,
+		Duration:    "2h",
+	})
+
+	assert.Nil(t, err)
+	assert.Equal(t, auction_entity.Scheduled, auctionRepository.auction.Status)
+	assert.Equal(t, startsAt, auctionRepository.auction.StartsAt)
+	assert.Equal(t, startsAt.Add(2*time.Hour), auctionRepository.auction.EndTime)
+	assert.Equal(t, phonesId, auctionRepository.auction.CategoryId)
+	assert.Equal(t, "Celulares", auctionRepository.auction.Category)
+
+	past := time.Now().Add(-time.Hour)
+	err = useCase.CreateAuction(context.Background(), AuctionInputDTO{
+		SellerId:    sellerId,
+		ProductName: "Vintage camera",
+		CategoryId:  phonesId,
+		Description: "Vintage camera in working condition",
+		Condition:   ProductCondition(auction_entity.Used),
+		StartsAt:    &past,
+	})
+	assert.Equal(t, "bad_request", err.Err)
+
+	err = useCase.CreateAuction(context.Background(), AuctionInputDTO{
+		SellerId:    sellerId,
+		ProductName: "Vintage camera",
+		CategoryId:  "1d2c3b4a-0000-4000-8000-000000000000",
+		Description: "Vintage camera in working condition",
+		Condition:   ProductCondition(auction_entity.Used),
+	})
+	assert.Equal(t, "bad_request", err.Err)
+}
+
+func TestCreateDutchAuction(t *testing.T) {
+	auctionRepository := &auctionRepositoryMock{}
+	useCase := NewAuctionUseCase(auctionRepository, &bidRepositoryMock{}, categoryRepository, nil, nil, nil)
+
+	input := AuctionInputDTO{
+		SellerId:      sellerId,
+		ProductName:   "Clearance lot",
+		CategoryId:    phonesId,
+		Description:   "Clearance lot of refurbished phones",
+		Condition:     ProductCondition(auction_entity.Refurbished),
+		StartingPrice: "1000",
+		Type:          AuctionType(auction_entity.DutchAuction),
+		PriceSchedule: &PriceScheduleInputDTO{FloorPrice: "400", Decrement: "50", Interval: "10m"},
+	}
+
+	err := useCase.CreateAuction(context.Background(), input)
+	assert.Nil(t, err)
+
+	auction := auctionRepository.auction
+	assert.Equal(t, auction_entity.DutchAuction, auction.Type)
+	assert.Equal(t, auction.OpensAt().Add(130*time.Minute), auction.EndTime,
+		"open one interval after reaching the floor")
+
+	output := toAuctionOutputDTO(auction)
+	assert.Equal(t, json.Number("1000.00"), output.PriceSchedule.CurrentPrice)
+	assert.Equal(t, json.Number("50.00"), output.PriceSchedule.Decrement)
+
+	input.PriceSchedule = nil
+	err = useCase.CreateAuction(context.Background(), input)
+	assert.Equal(t, "bad_request", err.Err)
+}
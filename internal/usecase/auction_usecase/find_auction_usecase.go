@@ -2,9 +2,12 @@ package auction_usecase
 
 import (
 	"context"
+	"encoding/json"
+	"time"
 
-	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/configuration/logger"
 	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/entity/auction_entity"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/entity/category_entity"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/entity/money_entity"
 	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/internal_error"
 	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/usecase/bid_usecase"
 )
@@ -16,41 +19,67 @@ func (au *AuctionUseCase) FindAuctionById(
 		return nil, err
 	}
 
-	return &AuctionOutputDTO{
-		Id:          auctionEntity.Id,
-		ProductName: auctionEntity.ProductName,
-		Category:    auctionEntity.Category,
-		Description: auctionEntity.Description,
-		Condition:   ProductCondition(auctionEntity.Condition),
-		Status:      AuctionStatus(auctionEntity.Status),
-		Timestamp:   auctionEntity.Timestamp,
-	}, nil
+	auctionOutput := toAuctionOutputDTO(auctionEntity)
+	return &auctionOutput, nil
 }
 
 func (au *AuctionUseCase) FindAuctions(
 	ctx context.Context,
-	status AuctionStatus,
-	category, productName string) ([]AuctionOutputDTO, *internal_error.InternalError) {
-	auctionEntities, err := au.auctionRepositoryInterface.FindAuctions(
-		ctx, auction_entity.AuctionStatus(status), category, productName)
+	queryInput AuctionQueryInputDTO) (*AuctionPageOutputDTO, *internal_error.InternalError) {
+	query := auction_entity.AuctionQuery{
+		ProductName: queryInput.ProductName,
+		Sort:        auction_entity.AuctionSort(queryInput.Sort),
+		Limit:       queryInput.Limit,
+		PageToken:   queryInput.PageToken,
+	}
+	if queryInput.Status != nil {
+		status := auction_entity.AuctionStatus(*queryInput.Status)
+		query.Status = &status
+	}
+
+	if err := query.Normalize(); err != nil {
+		return nil, err
+	}
+
+	if queryInput.Category != "" {
+		categoryIds, err := au.categoryIds(ctx, queryInput.Category)
+		if err != nil {
+			return nil, err
+		}
+		query.CategoryIds = categoryIds
+	}
+
+	page, err := au.auctionRepositoryInterface.FindAuctions(ctx, query)
 	if err != nil {
 		return nil, err
 	}
 
-	var auctionOutputs []AuctionOutputDTO
-	for _, value := range auctionEntities {
-		auctionOutputs = append(auctionOutputs, AuctionOutputDTO{
-			Id:          value.Id,
-			ProductName: value.ProductName,
-			Category:    value.Category,
-			Description: value.Description,
-			Condition:   ProductCondition(value.Condition),
-			Status:      AuctionStatus(value.Status),
-			Timestamp:   value.Timestamp,
-		})
+	auctionPage := &AuctionPageOutputDTO{
+		Items:         make([]AuctionOutputDTO, 0, len(page.Auctions)),
+		NextPageToken: page.NextPageToken,
+	}
+	for i := range page.Auctions {
+		auctionPage.Items = append(auctionPage.Items, toAuctionOutputDTO(&page.Auctions[i]))
 	}
 
-	return auctionOutputs, nil
+	return auctionPage, nil
+}
+
+// categoryIds expande o filtro de categoria, aceito por id ou slug, para a
+// própria categoria e todas as suas subcategorias.
+func (au *AuctionUseCase) categoryIds(
+	ctx context.Context, idOrSlug string) ([]string, *internal_error.InternalError) {
+	categories, err := au.categoryRepositoryInterface.FindCategories(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	category := category_entity.FindCategory(categories, idOrSlug)
+	if category == nil {
+		return nil, internal_error.NewNotFoundError("Category not found: " + idOrSlug)
+	}
+
+	return category_entity.Descendants(categories, category.Id), nil
 }
 
 func (au *AuctionUseCase) FindWinningBidByAuctionId(
@@ -61,35 +90,91 @@ func (au *AuctionUseCase) FindWinningBidByAuctionId(
 		return nil, err
 	}
 
-	auctionOutputDTO := AuctionOutputDTO{
-		Id:          auction.Id,
-		ProductName: auction.ProductName,
-		Category:    auction.Category,
-		Description: auction.Description,
-		Condition:   ProductCondition(auction.Condition),
-		Status:      AuctionStatus(auction.Status),
-		Timestamp:   auction.Timestamp,
-	}
+	auctionOutputDTO := toAuctionOutputDTO(auction)
 
 	bidWinning, err := au.bidRepositoryInterface.FindWinningBidByAuctionId(ctx, auction.Id)
 	if err != nil {
-		logger.Error("", err)
+		if err.Err != "not_found" {
+			return nil, err
+		}
 		return &WinningInfoOutputDTO{
-			Auction: auctionOutputDTO,
-			Bid:     nil,
+			Auction:    auctionOutputDTO,
+			Bid:        nil,
+			ReserveMet: !auction.HasReserve(),
 		}, nil
 	}
 
-	bidOutputDTO := &bid_usecase.BidOutputDTO{
-		Id:        bidWinning.Id,
-		UserId:    bidWinning.UserId,
-		AuctionId: bidWinning.AuctionId,
-		Amount:    bidWinning.Amount,
-		Timestamp: bidWinning.Timestamp,
+	// Sem atingir a reserva não há vencedor; o valor da reserva nunca é exposto
+	if !auction.ReserveMet(bidWinning.Amount) {
+		return &WinningInfoOutputDTO{
+			Auction:    auctionOutputDTO,
+			Bid:        nil,
+			ReserveMet: false,
+		}, nil
 	}
 
+	bidOutputDTO := bid_usecase.ToBidOutputDTO(*bidWinning)
+
 	return &WinningInfoOutputDTO{
-		Auction: auctionOutputDTO,
-		Bid:     bidOutputDTO,
+		Auction:    auctionOutputDTO,
+		Bid:        &bidOutputDTO,
+		ReserveMet: true,
 	}, nil
 }
+
+func buyNowPrice(auction *auction_entity.Auction) json.Number {
+	if !auction.HasBuyNow() {
+		return ""
+	}
+
+	return json.Number(auction.BuyNowPrice.String())
+}
+
+func toPriceScheduleOutputDTO(auction *auction_entity.Auction) *PriceScheduleOutputDTO {
+	if auction.Type != auction_entity.DutchAuction {
+		return nil
+	}
+
+	return &PriceScheduleOutputDTO{
+		FloorPrice:   json.Number(auction.PriceSchedule.FloorPrice.String()),
+		Decrement:    json.Number(money_entity.New(auction.PriceSchedule.Decrement, auction.Currency).String()),
+		Interval:     auction.PriceSchedule.Interval.String(),
+		CurrentPrice: json.Number(auction.CurrentPrice(time.Now()).String()),
+	}
+}
+
+func toAuctionOutputDTO(auction *auction_entity.Auction) AuctionOutputDTO {
+	var startsAt *time.Time
+	if !auction.StartsAt.IsZero() {
+		startsAt = &auction.StartsAt
+	}
+
+	return AuctionOutputDTO{
+		Id:          auction.Id,
+		SellerId:    auction.SellerId,
+		ProductName: auction.ProductName,
+		CategoryId:  auction.CategoryId,
+		Category:    auction.Category,
+		Description: auction.Description,
+		Condition:   ProductCondition(auction.Condition),
+		Status:      AuctionStatus(auction.Status),
+		Timestamp:   auction.Timestamp,
+		StartsAt:    startsAt,
+		EndTime:     auction.EndTime,
+
+		Currency:      auction.Currency,
+		StartingPrice: json.Number(auction.StartingPrice.String()),
+		MinIncrement:  formatMinIncrement(auction),
+		IncrementType: IncrementType(auction.IncrementType),
+		HasReserve:    auction.HasReserve(),
+		BuyNowPrice:   buyNowPrice(auction),
+
+		Type:          AuctionType(auction.Type),
+		PriceSchedule: toPriceScheduleOutputDTO(auction),
+
+		HighestBidAmount: json.Number(auction.HighestBidAmount.String()),
+		BidCount:         auction.BidCount,
+
+		Images: toAuctionImageOutputDTOs(auction.Images),
+	}
+}
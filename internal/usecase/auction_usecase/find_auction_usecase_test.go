@@ -0,0 +1,271 @@
+package auction_usecase
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/entity/auction_entity"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/entity/bid_entity"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/entity/category_entity"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/entity/money_entity"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/internal_error"
+	"github.com/stretchr/testify/assert"
+)
+
+type auctionRepositoryMock struct {
+	auction *auction_entity.Auction
+	query   auction_entity.AuctionQuery
+}
+
+func (m *auctionRepositoryMock) CreateAuction(
+	ctx context.Context, auctionEntity *auction_entity.Auction) *internal_error.InternalError {
+	m.auction = auctionEntity
+	return nil
+}
+
+func (m *auctionRepositoryMock) FindAuctions(
+	ctx context.Context,
+	query auction_entity.AuctionQuery) (*auction_entity.AuctionPage, *internal_error.InternalError) {
+	m.query = query
+	if m.auction == nil || (query.SellerId != "" && m.auction.SellerId != query.SellerId) {
+		return &auction_entity.AuctionPage{Auctions: []auction_entity.Auction{}}, nil
+	}
+	return &auction_entity.AuctionPage{Auctions: []auction_entity.Auction{*m.auction}}, nil
+}
+
+func (m *auctionRepositoryMock) FindAuctionById(
+	ctx context.Context, id string) (*auction_entity.Auction, *internal_error.InternalError) {
+	if m.auction == nil || m.auction.Id != id {
+		return nil, internal_error.NewNotFoundError("auction not found")
+	}
+	return m.auction, nil
+}
+
+func (m *auctionRepositoryMock) UpdateAuction(
+	ctx context.Context, auctionEntity *auction_entity.Auction) *internal_error.InternalError {
+	if m.auction.BidCount > 0 {
+		return internal_error.NewBadRequestError("Auction already has bids")
+	}
+	m.auction = auctionEntity
+	return nil
+}
+
+func (m *auctionRepositoryMock) UpdateAuctionStatus(
+	ctx context.Context, id string, status auction_entity.AuctionStatus) *internal_error.InternalError {
+	return m.auction.TransitionTo(status)
+}
+
+func (m *auctionRepositoryMock) AddAuctionImage(
+	ctx context.Context, auctionId string, auctionImage auction_entity.AuctionImage) *internal_error.InternalError {
+	if len(m.auction.Images) >= auction_entity.MaxImagesPerAuction {
+		return internal_error.NewBadRequestError("too many images")
+	}
+	m.auction.Images = append(m.auction.Images, auctionImage)
+	return nil
+}
+
+func (m *auctionRepositoryMock) RemoveAuctionImage(
+	ctx context.Context, auctionId, imageId string) *internal_error.InternalError {
+	for i := range m.auction.Images {
+		if m.auction.Images[i].Id == imageId {
+			m.auction.Images = append(m.auction.Images[:i], m.auction.Images[i+1:]...)
+			return nil
+		}
+	}
+	return internal_error.NewNotFoundError("image not found")
+}
+
+type bidRepositoryMock struct {
+	auction    *auction_entity.Auction
+	winningBid *bid_entity.Bid
+	voided     bool
+}
+
+func (m *bidRepositoryMock) AcceptBid(
+	ctx context.Context, bidEntity *bid_entity.Bid) (*bid_entity.BidResult, *internal_error.InternalError) {
+	return bid_entity.NewAcceptedBidResult([]bid_entity.Bid{*bidEntity}, true), nil
+}
+
+func (m *bidRepositoryMock) FindBidByAuctionId(
+	ctx context.Context, auctionId string) ([]bid_entity.Bid, *internal_error.InternalError) {
+	if m.winningBid == nil {
+		return nil, nil
+	}
+	return []bid_entity.Bid{*m.winningBid}, nil
+}
+
+func (m *bidRepositoryMock) FindWinningBidByAuctionId(
+	ctx context.Context, auctionId string) (*bid_entity.Bid, *internal_error.InternalError) {
+	if m.winningBid == nil {
+		return nil, internal_error.NewNotFoundError("no bids")
+	}
+	return m.winningBid, nil
+}
+
+func (m *bidRepositoryMock) FindWinningBidsByAuctionIds(
+	ctx context.Context, auctionIds []string) (map[string]bid_entity.Bid, *internal_error.InternalError) {
+	winningBids := make(map[string]bid_entity.Bid)
+	if m.winningBid != nil {
+		winningBids[m.winningBid.AuctionId] = *m.winningBid
+	}
+	return winningBids, nil
+}
+
+func (m *bidRepositoryMock) BuyNow(
+	ctx context.Context, userId, auctionId string) (*bid_entity.BidResult, *internal_error.InternalError) {
+	return nil, internal_error.NewInternalServerError("not implemented")
+}
+
+func (m *bidRepositoryMock) CancelAuction(
+	ctx context.Context, auctionId string, withoutBids bool) *internal_error.InternalError {
+	if withoutBids && m.auction.BidCount > 0 {
+		return internal_error.NewBadRequestError("Auction already has bids")
+	}
+	if err := m.auction.TransitionTo(auction_entity.Cancelled); err != nil {
+		return err
+	}
+	m.voided = true
+	m.winningBid = nil
+	return nil
+}
+
+const (
+	electronicsId = "6f1c2a4e-0b7d-4c55-9a1e-3d2f8b9c0a11"
+	phonesId      = "0c3e5b7a-2d4f-4e61-8b2a-9f1d3c5e7a22"
+	tabletsId     = "9a8b7c6d-5e4f-4a3b-8c2d-1e0f9a8b7c33"
+)
+
+type categoryRepositoryMock struct {
+	categories []category_entity.Category
+}
+
+var categoryRepository = &categoryRepositoryMock{categories: []category_entity.Category{
+	{Id: electronicsId, Name: "Eletrônicos", Slug: "eletronicos"},
+	{Id: phonesId, Name: "Celulares", Slug: "celulares", ParentId: electronicsId},
+	{Id: tabletsId, Name: "Tablets", Slug: "tablets", ParentId: electronicsId},
+}}
+
+func (m *categoryRepositoryMock) CreateCategory(
+	ctx context.Context, category *category_entity.Category) *internal_error.InternalError {
+	return nil
+}
+
+func (m *categoryRepositoryMock) FindCategories(
+	ctx context.Context) ([]category_entity.Category, *internal_error.InternalError) {
+	return m.categories, nil
+}
+
+func (m *categoryRepositoryMock) FindCategoryById(
+	ctx context.Context, id string) (*category_entity.Category, *internal_error.InternalError) {
+	for i := range m.categories {
+		if m.categories[i].Id == id {
+			return &m.categories[i], nil
+		}
+	}
+	return nil, internal_error.NewNotFoundError("category not found")
+}
+
+func (m *categoryRepositoryMock) UpdateCategory(
+	ctx context.Context, category *category_entity.Category) *internal_error.InternalError {
+	return nil
+}
+
+func (m *categoryRepositoryMock) DeleteCategory(ctx context.Context, id string) *internal_error.InternalError {
+	return nil
+}
+
+func newAuctionWithReserve(t *testing.T, reservePrice int64) *auction_entity.Auction {
+	auction, err := auction_entity.CreateAuction(
+		sellerId, "Test Product", "Test Category", "Test Description for reserve", auction_entity.New)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := auction.SetReservePrice(money_entity.New(reservePrice, "BRL")); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	return auction
+}
+
+func TestFindWinningBidByAuctionIdReserve(t *testing.T) {
+	tests := []struct {
+		name           string
+		reservePrice   int64
+		bidAmount      int64
+		expectWinner   bool
+		expectedResult bool
+	}{
+		{name: "no reserve", reservePrice: 0, bidAmount: 1000, expectWinner: true, expectedResult: true},
+		{name: "reserve met", reservePrice: 10000, bidAmount: 10000, expectWinner: true, expectedResult: true},
+		{name: "reserve not met", reservePrice: 10000, bidAmount: 9999, expectWinner: false, expectedResult: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			auction := newAuctionWithReserve(t, tt.reservePrice)
+			useCase := NewAuctionUseCase(
+				&auctionRepositoryMock{auction: auction},
+				&bidRepositoryMock{winningBid: &bid_entity.Bid{
+					Id:        "bid-id",
+					AuctionId: auction.Id,
+					Amount:    money_entity.New(tt.bidAmount, "BRL"),
+					Timestamp: time.Now(),
+				}},
+				categoryRepository, nil, nil, nil)
+
+			winningInfo, err := useCase.FindWinningBidByAuctionId(context.Background(), auction.Id)
+
+			assert.Nil(t, err)
+			assert.Equal(t, tt.expectedResult, winningInfo.ReserveMet)
+			assert.Equal(t, tt.expectWinner, winningInfo.Bid != nil)
+		})
+	}
+}
+
+func TestWinningInfoNeverExposesReservePrice(t *testing.T) {
+	auction := newAuctionWithReserve(t, 123456)
+	useCase := NewAuctionUseCase(
+		&auctionRepositoryMock{auction: auction}, &bidRepositoryMock{}, categoryRepository, nil, nil, nil)
+
+	winningInfo, err := useCase.FindWinningBidByAuctionId(context.Background(), auction.Id)
+	assert.Nil(t, err)
+
+	body, jsonErr := json.Marshal(winningInfo)
+	assert.NoError(t, jsonErr)
+	assert.NotContains(t, string(body), "1234.56")
+	assert.True(t, winningInfo.Auction.HasReserve)
+	assert.False(t, winningInfo.ReserveMet)
+}
+
+func TestFindAuctionsQuery(t *testing.T) {
+	auctionRepository := &auctionRepositoryMock{auction: newAuctionWithReserve(t, 0)}
+	useCase := NewAuctionUseCase(auctionRepository, &bidRepositoryMock{}, categoryRepository, nil, nil, nil)
+
+	active := AuctionStatus(auction_entity.Active)
+	page, err := useCase.FindAuctions(context.Background(), AuctionQueryInputDTO{Status: &active})
+	assert.Nil(t, err)
+	assert.Len(t, page.Items, 1)
+	assert.Equal(t, auction_entity.SortNewest, auctionRepository.query.Sort)
+	assert.Equal(t, auction_entity.DefaultPageLimit, auctionRepository.query.Limit)
+	assert.Equal(t, auction_entity.Active, *auctionRepository.query.Status)
+
+	_, err = useCase.FindAuctions(context.Background(), AuctionQueryInputDTO{Sort: "cheapest"})
+	assert.Equal(t, "bad_request", err.Err)
+}
+
+func TestFindAuctionsByCategoryIncludesDescendants(t *testing.T) {
+	auctionRepository := &auctionRepositoryMock{auction: newAuctionWithReserve(t, 0)}
+	useCase := NewAuctionUseCase(auctionRepository, &bidRepositoryMock{}, categoryRepository, nil, nil, nil)
+
+	_, err := useCase.FindAuctions(context.Background(), AuctionQueryInputDTO{Category: "eletronicos"})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{electronicsId, phonesId, tabletsId}, auctionRepository.query.CategoryIds)
+
+	_, err = useCase.FindAuctions(context.Background(), AuctionQueryInputDTO{Category: phonesId})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{phonesId}, auctionRepository.query.CategoryIds)
+
+	_, err = useCase.FindAuctions(context.Background(), AuctionQueryInputDTO{Category: "livros"})
+	assert.Equal(t, "not_found", err.Err)
+}
@@ -0,0 +1,114 @@
+package auction_usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/configuration/logger"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/entity/auction_entity"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/internal_error"
+)
+
+type AuctionImageOutputDTO struct {
+	Id           string    `json:"id"`
+	URL          string    `json:"url"`
+	ThumbnailURL string    `json:"thumbnail_url"`
+	ContentType  string    `json:"content_type"`
+	Size         int64     `json:"size"`
+	Width        int       `json:"width"`
+	Height       int       `json:"height"`
+	CreatedAt    time.Time `json:"created_at" time_format:"2006-01-02 15:04:05"`
+}
+
+// AddAuctionImage grava a foto e a miniatura na storage antes de registrar os
+// metadados no leilão; se o registro falhar, os arquivos são descartados.
+func (au *AuctionUseCase) AddAuctionImage(
+	ctx context.Context,
+	auctionId, sellerId string,
+	data []byte) (*AuctionImageOutputDTO, *internal_error.InternalError) {
+	if _, err := au.findManageableAuction(ctx, auctionId, sellerId); err != nil {
+		return nil, err
+	}
+
+	processed, err := auction_entity.ProcessImage(ctx, auctionId, data)
+	if err != nil {
+		return nil, err
+	}
+
+	auctionImage := processed.Image
+	if err := au.imageStorage.PutObject(ctx, auctionImage.Key, auctionImage.ContentType, processed.Data); err != nil {
+		return nil, err
+	}
+
+	if err := au.imageStorage.PutObject(ctx, auctionImage.ThumbnailKey, "image/jpeg", processed.Thumbnail); err != nil {
+		au.deleteImageObjects(ctx, auctionImage)
+		return nil, err
+	}
+
+	auctionImage.URL = au.imageStorage.ObjectURL(auctionImage.Key)
+	auctionImage.ThumbnailURL = au.imageStorage.ObjectURL(auctionImage.ThumbnailKey)
+
+	if err := au.auctionRepositoryInterface.AddAuctionImage(ctx, auctionId, auctionImage); err != nil {
+		au.deleteImageObjects(ctx, auctionImage)
+		return nil, err
+	}
+
+	imageOutput := toAuctionImageOutputDTO(auctionImage)
+	return &imageOutput, nil
+}
+
+func (au *AuctionUseCase) RemoveAuctionImage(
+	ctx context.Context, auctionId, imageId, sellerId string) *internal_error.InternalError {
+	auction, err := au.findManageableAuction(ctx, auctionId, sellerId)
+	if err != nil {
+		return err
+	}
+
+	auctionImage := auction.FindImage(imageId)
+	if auctionImage == nil {
+		return internal_error.NewNotFoundError("Image not found with this id = " + imageId)
+	}
+
+	if err := au.auctionRepositoryInterface.RemoveAuctionImage(ctx, auctionId, imageId); err != nil {
+		return err
+	}
+
+	au.deleteImageObjects(ctx, *auctionImage)
+
+	return nil
+}
+
+// Arquivos órfãos não afetam o leilão, então falhas aqui só são registradas
+func (au *AuctionUseCase) deleteImageObjects(ctx context.Context, auctionImage auction_entity.AuctionImage) {
+	for _, key := range []string{auctionImage.Key, auctionImage.ThumbnailKey} {
+		if err := au.imageStorage.DeleteObject(ctx, key); err != nil {
+			logger.Error("Error trying to delete auction image file", err)
+		}
+	}
+}
+
+func toAuctionImageOutputDTO(auctionImage auction_entity.AuctionImage) AuctionImageOutputDTO {
+	return AuctionImageOutputDTO{
+		Id:           auctionImage.Id,
+		URL:          auctionImage.URL,
+		ThumbnailURL: auctionImage.ThumbnailURL,
+		ContentType:  auctionImage.ContentType,
+		Size:         auctionImage.Size,
+		Width:        auctionImage.Width,
+		Height:       auctionImage.Height,
+		CreatedAt:    auctionImage.CreatedAt,
+	}
+}
+
+func toAuctionImageOutputDTOs(auctionImages []auction_entity.AuctionImage) []AuctionImageOutputDTO {
+	if len(auctionImages) == 0 {
+		return nil
+	}
+
+	imageOutputs := make([]AuctionImageOutputDTO, 0, len(auctionImages))
+	for _, auctionImage := range auctionImages {
+		imageOutputs = append(imageOutputs, toAuctionImageOutputDTO(auctionImage))
+	}
+
+	return imageOutputs
+}
@@ -0,0 +1,79 @@
+package auction_usecase
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/color"
+	"image/png"
+	"testing"
+
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/internal_error"
+	"github.com/stretchr/testify/assert"
+)
+
+type imageStorageMock struct {
+	objects map[string][]byte
+}
+
+func (m *imageStorageMock) PutObject(
+	ctx context.Context, key, contentType string, data []byte) *internal_error.InternalError {
+	m.objects[key] = data
+	return nil
+}
+
+func (m *imageStorageMock) DeleteObject(ctx context.Context, key string) *internal_error.InternalError {
+	delete(m.objects, key)
+	return nil
+}
+
+func (m *imageStorageMock) ObjectURL(key string) string {
+	return "https://cdn.example.com/" + key
+}
+
+func newPNG(t *testing.T, width, height int) []byte {
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	for x := 0; x < width; x++ {
+		img.Set(x, 0, color.NRGBA{R: 200, A: 255})
+	}
+
+	var encoded bytes.Buffer
+	assert.NoError(t, png.Encode(&encoded, img))
+	return encoded.Bytes()
+}
+
+func TestSellerManagesAuctionImages(t *testing.T) {
+	auction := newAuctionWithReserve(t, 0)
+	imageStorage := &imageStorageMock{objects: make(map[string][]byte)}
+	useCase := NewAuctionUseCase(
+		&auctionRepositoryMock{auction: auction}, &bidRepositoryMock{}, categoryRepository, nil, imageStorage, nil)
+	ctx := context.Background()
+
+	imageOutput, err := useCase.AddAuctionImage(ctx, auction.Id, sellerId, newPNG(t, 800, 400))
+	assert.Nil(t, err)
+	assert.Equal(t, "image/png", imageOutput.ContentType)
+	assert.Equal(t, 800, imageOutput.Width)
+	assert.Equal(t, "https://cdn.example.com/auctions/"+auction.Id+"/"+imageOutput.Id+"_thumb.jpg",
+		imageOutput.ThumbnailURL)
+	assert.Len(t, imageStorage.objects, 2)
+
+	auctionOutput, err := useCase.FindAuctionById(ctx, auction.Id)
+	assert.Nil(t, err)
+	assert.Equal(t, imageOutput.URL, auctionOutput.Images[0].URL)
+
+	_, err = useCase.AddAuctionImage(ctx, auction.Id, "another-user", newPNG(t, 10, 10))
+	assert.Equal(t, "forbidden", err.Err)
+
+	_, err = useCase.AddAuctionImage(ctx, auction.Id, sellerId, []byte("%PDF-1.7 not an image"))
+	assert.Equal(t, "bad_request", err.Err)
+
+	assert.Nil(t, useCase.RemoveAuctionImage(ctx, auction.Id, imageOutput.Id, sellerId))
+	assert.Empty(t, auction.Images)
+	assert.Empty(t, imageStorage.objects)
+
+	// Depois do primeiro lance as fotos não mudam mais
+	auction.BidCount = 1
+	_, err = useCase.AddAuctionImage(ctx, auction.Id, sellerId, newPNG(t, 10, 10))
+	assert.Equal(t, "bad_request", err.Err)
+	assert.Empty(t, imageStorage.objects)
+}
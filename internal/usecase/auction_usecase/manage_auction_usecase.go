@@ -0,0 +1,166 @@
+package auction_usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/entity/auction_entity"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/event"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/internal_error"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/usecase/bid_usecase"
+)
+
+type AuctionUpdateInputDTO struct {
+	CategoryId  string `json:"category_id" binding:"required,uuid"`
+	Description string `json:"description" binding:"required,min=10,max=200"`
+}
+
+type SellerAuctionQueryInputDTO struct {
+	Limit     int    `form:"limit" binding:"omitempty,min=1,max=100"`
+	PageToken string `form:"page_token"`
+}
+
+type SellerAuctionOutputDTO struct {
+	AuctionOutputDTO
+	HighestBid *bid_usecase.BidOutputDTO `json:"highest_bid,omitempty"`
+}
+
+type SellerAuctionPageOutputDTO struct {
+	Items         []SellerAuctionOutputDTO `json:"items"`
+	NextPageToken string                   `json:"next_page_token,omitempty"`
+}
+
+// FindAuctionsBySeller pagina os leilões do vendedor, do mais novo para o mais
+// antigo. Os maiores lances da página vêm numa única consulta.
+func (au *AuctionUseCase) FindAuctionsBySeller(
+	ctx context.Context,
+	sellerId string,
+	queryInput SellerAuctionQueryInputDTO) (*SellerAuctionPageOutputDTO, *internal_error.InternalError) {
+	query := auction_entity.AuctionQuery{
+		SellerId:  sellerId,
+		Sort:      auction_entity.SortNewest,
+		Limit:     queryInput.Limit,
+		PageToken: queryInput.PageToken,
+	}
+	if err := query.Normalize(); err != nil {
+		return nil, err
+	}
+
+	page, err := au.auctionRepositoryInterface.FindAuctions(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	auctionIds := make([]string, 0, len(page.Auctions))
+	for i := range page.Auctions {
+		auctionIds = append(auctionIds, page.Auctions[i].Id)
+	}
+
+	winningBids, err := au.bidRepositoryInterface.FindWinningBidsByAuctionIds(ctx, auctionIds)
+	if err != nil {
+		return nil, err
+	}
+
+	auctionPage := &SellerAuctionPageOutputDTO{
+		Items:         make([]SellerAuctionOutputDTO, 0, len(page.Auctions)),
+		NextPageToken: page.NextPageToken,
+	}
+	for i := range page.Auctions {
+		auctionOutput := SellerAuctionOutputDTO{AuctionOutputDTO: toAuctionOutputDTO(&page.Auctions[i])}
+		if highestBid, ok := winningBids[page.Auctions[i].Id]; ok {
+			bidOutput := bid_usecase.ToBidOutputDTO(highestBid)
+			auctionOutput.HighestBid = &bidOutput
+		}
+
+		auctionPage.Items = append(auctionPage.Items, auctionOutput)
+	}
+
+	return auctionPage, nil
+}
+
+// UpdateAuction permite ao vendedor corrigir categoria e descrição enquanto
+// o leilão ainda não recebeu lances.
+func (au *AuctionUseCase) UpdateAuction(
+	ctx context.Context,
+	auctionId, sellerId string,
+	auctionInput AuctionUpdateInputDTO) (*AuctionOutputDTO, *internal_error.InternalError) {
+	auction, err := au.findManageableAuction(ctx, auctionId, sellerId)
+	if err != nil {
+		return nil, err
+	}
+
+	category, err := au.findCategory(ctx, auctionInput.CategoryId)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := auction.SetDetails(category.Id, category.Name, auctionInput.Description); err != nil {
+		return nil, err
+	}
+
+	if err := au.auctionRepositoryInterface.UpdateAuction(ctx, auction); err != nil {
+		return nil, err
+	}
+
+	auctionOutput := toAuctionOutputDTO(auction)
+	return &auctionOutput, nil
+}
+
+// CancelAuction encerra o leilão sem vencedor. O vendedor só cancela leilões
+// sem lances; administradores cancelam a qualquer momento e os lances
+// existentes são anulados.
+func (au *AuctionUseCase) CancelAuction(
+	ctx context.Context, auctionId, userId string, admin bool) *internal_error.InternalError {
+	if admin {
+		auction, err := au.auctionRepositoryInterface.FindAuctionById(ctx, auctionId)
+		if err != nil {
+			return err
+		}
+
+		if !auction.Status.CanTransitionTo(auction_entity.Cancelled) {
+			return auction_entity.NewIllegalTransitionError(auction.Status, auction_entity.Cancelled)
+		}
+	} else if _, err := au.findManageableAuction(ctx, auctionId, userId); err != nil {
+		return err
+	}
+
+	// O vendedor só cancela sem lances; a condição é conferida na gravação,
+	// junto com a anulação dos lances
+	if err := au.bidRepositoryInterface.CancelAuction(ctx, auctionId, !admin); err != nil {
+		return err
+	}
+
+	au.eventBus.Publish(ctx, event.AuctionCancelled{
+		AuctionId:   auctionId,
+		CancelledAt: time.Now(),
+	})
+
+	return nil
+}
+
+// findManageableAuction garante que o leilão pertence ao vendedor, não foi
+// encerrado e ainda não tem lances. Vale para a edição, o cancelamento e as
+// fotos, que não mudam depois que alguém deu um lance.
+func (au *AuctionUseCase) findManageableAuction(
+	ctx context.Context,
+	auctionId, sellerId string) (*auction_entity.Auction, *internal_error.InternalError) {
+	auction, err := au.auctionRepositoryInterface.FindAuctionById(ctx, auctionId)
+	if err != nil {
+		return nil, err
+	}
+
+	if !auction.OwnedBy(sellerId) {
+		return nil, internal_error.NewForbiddenError("Only the seller can manage this auction")
+	}
+
+	if auction.Status.Final() {
+		return nil, internal_error.NewBadRequestError("Auction can no longer be changed")
+	}
+
+	// A gravação confere de novo, caso um lance chegue depois desta leitura
+	if auction.BidCount > 0 {
+		return nil, internal_error.NewBadRequestError("Auction already has bids")
+	}
+
+	return auction, nil
+}
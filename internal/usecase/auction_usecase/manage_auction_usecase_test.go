@@ -0,0 +1,117 @@
+package auction_usecase
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/entity/auction_entity"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/entity/bid_entity"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/entity/money_entity"
+	"github.com/stretchr/testify/assert"
+)
+
+const sellerId = "seller-id"
+
+func TestSellerManagesAuctionBeforeFirstBid(t *testing.T) {
+	auction := newAuctionWithReserve(t, 0)
+	auctionRepository := &auctionRepositoryMock{auction: auction}
+	useCase := NewAuctionUseCase(
+		auctionRepository, &bidRepositoryMock{auction: auction}, categoryRepository, nil, nil, nil)
+	ctx := context.Background()
+
+	updated, err := useCase.UpdateAuction(ctx, auction.Id, sellerId, AuctionUpdateInputDTO{
+		CategoryId:  tabletsId,
+		Description: "Updated description for the auction",
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, tabletsId, updated.CategoryId)
+	assert.Equal(t, "Tablets", updated.Category)
+	assert.Equal(t, "Updated description for the auction", auctionRepository.auction.Description)
+
+	myAuctions, err := useCase.FindAuctionsBySeller(ctx, sellerId, SellerAuctionQueryInputDTO{})
+	assert.Nil(t, err)
+	assert.Len(t, myAuctions.Items, 1)
+	assert.Nil(t, myAuctions.Items[0].HighestBid)
+
+	assert.Nil(t, useCase.CancelAuction(ctx, auction.Id, sellerId, false))
+	assert.Equal(t, auction_entity.Cancelled, auctionRepository.auction.Status)
+
+	err = useCase.CancelAuction(ctx, auction.Id, sellerId, false)
+	assert.Equal(t, "bad_request", err.Err)
+}
+
+func TestOnlySellerManagesAuction(t *testing.T) {
+	auction := newAuctionWithReserve(t, 0)
+	useCase := NewAuctionUseCase(
+		&auctionRepositoryMock{auction: auction}, &bidRepositoryMock{}, categoryRepository, nil, nil, nil)
+
+	err := useCase.CancelAuction(context.Background(), auction.Id, "another-user", false)
+	assert.Equal(t, "forbidden", err.Err)
+	assert.Equal(t, auction_entity.Active, auction.Status)
+}
+
+func TestAuctionWithBidsCannotBeChanged(t *testing.T) {
+	auction := newAuctionWithReserve(t, 0)
+	auction.BidCount = 1
+	highestBid := &bid_entity.Bid{Id: "bid-id", AuctionId: auction.Id, Amount: money_entity.New(4200, "BRL"),
+		Timestamp: time.Now(), BuyNow: true}
+	auctionRepository := &auctionRepositoryMock{auction: auction}
+	useCase := NewAuctionUseCase(
+		auctionRepository, &bidRepositoryMock{auction: auction, winningBid: highestBid},
+		categoryRepository, nil, nil, nil)
+	ctx := context.Background()
+
+	_, err := useCase.UpdateAuction(ctx, auction.Id, sellerId, AuctionUpdateInputDTO{
+		CategoryId:  tabletsId,
+		Description: "Updated description for the auction",
+	})
+	assert.Equal(t, "bad_request", err.Err)
+
+	err = useCase.CancelAuction(ctx, auction.Id, sellerId, false)
+	assert.Equal(t, "bad_request", err.Err)
+	assert.Equal(t, auction_entity.Active, auction.Status)
+
+	myAuctions, err := useCase.FindAuctionsBySeller(ctx, sellerId, SellerAuctionQueryInputDTO{})
+	assert.Nil(t, err)
+	assert.Equal(t, json.Number("42.00"), myAuctions.Items[0].HighestBid.Amount)
+	assert.True(t, myAuctions.Items[0].HighestBid.BuyNow)
+	assert.Equal(t, sellerId, auctionRepository.query.SellerId)
+}
+
+func TestAdminCancelVoidsBids(t *testing.T) {
+	auction := newAuctionWithReserve(t, 0)
+	auction.BidCount = 1
+	bidRepository := &bidRepositoryMock{auction: auction, winningBid: &bid_entity.Bid{
+		Id: "bid-id", AuctionId: auction.Id, Amount: money_entity.New(4200, "BRL"), Timestamp: time.Now(),
+	}}
+	useCase := NewAuctionUseCase(
+		&auctionRepositoryMock{auction: auction}, bidRepository, categoryRepository, nil, nil, nil)
+	ctx := context.Background()
+
+	assert.Nil(t, useCase.CancelAuction(ctx, auction.Id, "admin-id", true))
+	assert.Equal(t, auction_entity.Cancelled, auction.Status)
+	assert.True(t, bidRepository.voided)
+
+	winningInfo, err := useCase.FindWinningBidByAuctionId(ctx, auction.Id)
+	assert.Nil(t, err)
+	assert.Nil(t, winningInfo.Bid)
+
+	err = useCase.CancelAuction(ctx, auction.Id, "admin-id", true)
+	assert.Equal(t, "bad_request", err.Err)
+}
+
+func TestSellerCancelRefusedWhenBidArrivesAfterRead(t *testing.T) {
+	auction := newAuctionWithReserve(t, 0)
+	// O lance foi gravado depois da leitura feita pelo caso de uso
+	stored := *auction
+	stored.BidCount = 1
+	useCase := NewAuctionUseCase(
+		&auctionRepositoryMock{auction: auction}, &bidRepositoryMock{auction: &stored},
+		categoryRepository, nil, nil, nil)
+
+	err := useCase.CancelAuction(context.Background(), auction.Id, sellerId, false)
+	assert.Equal(t, "bad_request", err.Err)
+	assert.Equal(t, auction_entity.Active, stored.Status)
+}
@@ -0,0 +1,61 @@
+package auction_usecase
+
+import (
+	"context"
+
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/entity/auction_entity"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/internal_error"
+)
+
+type AuctionSearchInputDTO struct {
+	Text   string         `form:"q" binding:"required,min=2,max=100"`
+	Status *AuctionStatus `form:"status"`
+	Limit  int            `form:"limit" binding:"omitempty,min=1,max=100"`
+}
+
+type AuctionSearchResultOutputDTO struct {
+	AuctionOutputDTO
+	Score float64 `json:"score"`
+}
+
+type AuctionSearchOutputDTO struct {
+	Items []AuctionSearchResultOutputDTO `json:"items"`
+}
+
+func (au *AuctionUseCase) SearchAuctions(
+	ctx context.Context,
+	searchInput AuctionSearchInputDTO) (*AuctionSearchOutputDTO, *internal_error.InternalError) {
+	if au.auctionSearcherInterface == nil {
+		return nil, internal_error.NewInternalServerError("Auction search is not configured")
+	}
+
+	query := auction_entity.AuctionSearchQuery{
+		Text:  searchInput.Text,
+		Limit: searchInput.Limit,
+	}
+	if searchInput.Status != nil {
+		status := auction_entity.AuctionStatus(*searchInput.Status)
+		query.Status = &status
+	}
+
+	if err := query.Normalize(); err != nil {
+		return nil, err
+	}
+
+	results, err := au.auctionSearcherInterface.SearchAuctions(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	searchOutput := &AuctionSearchOutputDTO{
+		Items: make([]AuctionSearchResultOutputDTO, 0, len(results)),
+	}
+	for i := range results {
+		searchOutput.Items = append(searchOutput.Items, AuctionSearchResultOutputDTO{
+			AuctionOutputDTO: toAuctionOutputDTO(&results[i].Auction),
+			Score:            results[i].Score,
+		})
+	}
+
+	return searchOutput, nil
+}
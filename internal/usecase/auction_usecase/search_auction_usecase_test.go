@@ -0,0 +1,32 @@
+package auction_usecase
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/entity/auction_entity"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/infra/search"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSearchAuctions(t *testing.T) {
+	searcher := search.NewMemorySearcher(
+		auction_entity.Auction{Id: "1", ProductName: "Relógio antigo", Category: "Antiguidades",
+			Description: "Relógio de bolso"},
+		auction_entity.Auction{Id: "2", ProductName: "Quadro", Category: "Arte",
+			Description: "Moldura com relógio pintado"},
+		auction_entity.Auction{Id: "3", ProductName: "Vaso", Category: "Decoração",
+			Description: "Vaso de cerâmica"},
+	)
+	useCase := NewAuctionUseCase(
+		&auctionRepositoryMock{}, &bidRepositoryMock{}, categoryRepository, searcher, nil, nil)
+
+	output, err := useCase.SearchAuctions(context.Background(), AuctionSearchInputDTO{Text: "relogio"})
+	assert.Nil(t, err)
+	assert.Len(t, output.Items, 2)
+	assert.Equal(t, "1", output.Items[0].Id)
+	assert.Equal(t, "2", output.Items[1].Id)
+
+	_, err = useCase.SearchAuctions(context.Background(), AuctionSearchInputDTO{Text: " "})
+	assert.Equal(t, "bad_request", err.Err)
+}
@@ -0,0 +1,53 @@
+package bid_usecase
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/event"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/internal_error"
+)
+
+// BuyNow arremata o leilão pelo preço de compra imediata ou, se holandês,
+// pelo preço corrente. O lance da compra já sai gravado do repositório, junto
+// com o fechamento do leilão.
+func (bu *BidUseCase) BuyNow(
+	ctx context.Context, auctionId, userId string) (*BidAcceptanceOutputDTO, *internal_error.InternalError) {
+	bidResult, err := bu.checkBidder(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	if bidResult == nil {
+		bidResult, err = bu.BidRepository.BuyNow(ctx, userId, auctionId)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if !bidResult.Accepted {
+		return &BidAcceptanceOutputDTO{
+			AuctionId: auctionId,
+			Status:    BidRejected,
+			Reason:    string(bidResult.Reason),
+			Message:   bidResult.Message,
+		}, nil
+	}
+
+	bu.publishAcceptance(ctx, bidResult)
+	bu.EventBus.Publish(ctx, event.AuctionClosed{
+		AuctionId: auctionId,
+		ClosedAt:  time.Now(),
+	})
+
+	buyNowBid := bidResult.Bids[0]
+	return &BidAcceptanceOutputDTO{
+		Id:        buyNowBid.Id,
+		AuctionId: buyNowBid.AuctionId,
+		Status:    BidAccepted,
+		Amount:    json.Number(buyNowBid.Amount.String()),
+		Currency:  buyNowBid.Currency(),
+		Leading:   true,
+	}, nil
+}
@@ -2,135 +2,195 @@ package bid_usecase
 
 import (
 	"context"
-	"os"
-	"strconv"
+	"encoding/json"
 	"time"
 
-	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/configuration/logger"
 	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/entity/bid_entity"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/entity/money_entity"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/entity/user_entity"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/event"
 	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/internal_error"
 )
 
+// Valores são decimais exatos, como 10.10 ou "10.10", na moeda informada
 type BidInputDTO struct {
-	UserId    string  `json:"user_id"`
-	AuctionId string  `json:"auction_id"`
-	Amount    float64 `json:"amount"`
+	UserId    string      `json:"-"`
+	AuctionId string      `json:"auction_id"`
+	Amount    json.Number `json:"amount"`
+	MaxAmount json.Number `json:"max_amount"`
+	Currency  string      `json:"currency" binding:"required,iso4217"`
 }
 
 type BidOutputDTO struct {
-	Id        string    `json:"id"`
-	UserId    string    `json:"user_id"`
-	AuctionId string    `json:"auction_id"`
-	Amount    float64   `json:"amount"`
-	Timestamp time.Time `json:"timestamp" time_format:"2006-01-02 15:04:05"`
+	Id            string      `json:"id"`
+	UserId        string      `json:"user_id"`
+	AuctionId     string      `json:"auction_id"`
+	Amount        json.Number `json:"amount"`
+	Currency      string      `json:"currency"`
+	Timestamp     time.Time   `json:"timestamp" time_format:"2006-01-02 15:04:05"`
+	Automatic     bool        `json:"automatic"`
+	BuyNow        bool        `json:"buy_now,omitempty"`
+	DutchPurchase bool        `json:"dutch_purchase,omitempty"`
+	Void          bool        `json:"void,omitempty"`
 }
 
-type BidUseCase struct {
-	BidRepository bid_entity.BidEntityRepository
+const (
+	BidAccepted      = "accepted"
+	BidRejected      = "rejected"
+	BidMaxBidUpdated = "max_bid_updated"
+)
 
-	timer               *time.Timer
-	maxBatchSize        int
-	batchInsertInterval time.Duration
-	bidChannel          chan bid_entity.Bid
+// Ajustes do máximo pelo líder não criam lance, então não têm id nem amount
+type BidAcceptanceOutputDTO struct {
+	Id        string      `json:"id,omitempty"`
+	AuctionId string      `json:"auction_id"`
+	Status    string      `json:"status"`
+	Amount    json.Number `json:"amount,omitempty"`
+	MaxAmount json.Number `json:"max_amount,omitempty"`
+	Currency  string      `json:"currency,omitempty"`
+	Leading   bool        `json:"leading"`
+	Reason    string      `json:"reason,omitempty"`
+	Message   string      `json:"message,omitempty"`
+
+	ExtendedEndTime *time.Time `json:"extended_end_time,omitempty"`
 }
 
-func NewBidUseCase(bidRepository bid_entity.BidEntityRepository) BidUseCaseInterface {
-	maxSizeInterval := getMaxBatchSizeInterval()
-	maxBatchSize := getMaxBatchSize()
+type BidUseCase struct {
+	BidRepository  bid_entity.BidEntityRepository
+	UserRepository user_entity.UserRepositoryInterface
+	EventBus       *event.Bus
+}
 
-	bidUseCase := &BidUseCase{
-		BidRepository:       bidRepository,
-		maxBatchSize:        maxBatchSize,
-		batchInsertInterval: maxSizeInterval,
-		timer:               time.NewTimer(maxSizeInterval),
-		bidChannel:          make(chan bid_entity.Bid, maxBatchSize),
+func NewBidUseCase(
+	bidRepository bid_entity.BidEntityRepository,
+	userRepository user_entity.UserRepositoryInterface,
+	eventBus *event.Bus) BidUseCaseInterface {
+	return &BidUseCase{
+		BidRepository:  bidRepository,
+		UserRepository: userRepository,
+		EventBus:       eventBus,
 	}
-
-	bidUseCase.triggerCreateRoutine(context.Background())
-
-	return bidUseCase
 }
 
-var bidBatch []bid_entity.Bid
-
 type BidUseCaseInterface interface {
 	CreateBid(
 		ctx context.Context,
-		bidInputDTO BidInputDTO) *internal_error.InternalError
+		bidInputDTO BidInputDTO) (*BidAcceptanceOutputDTO, *internal_error.InternalError)
 
 	FindWinningBidByAuctionId(
 		ctx context.Context, auctionId string) (*BidOutputDTO, *internal_error.InternalError)
 
 	FindBidByAuctionId(
 		ctx context.Context, auctionId string) ([]BidOutputDTO, *internal_error.InternalError)
-}
 
-func (bu *BidUseCase) triggerCreateRoutine(ctx context.Context) {
-	go func() {
-		defer close(bu.bidChannel)
-
-		for {
-			select {
-			case bidEntity, ok := <-bu.bidChannel:
-				if !ok {
-					if len(bidBatch) > 0 {
-						if err := bu.BidRepository.CreateBid(ctx, bidBatch); err != nil {
-							logger.Error("error trying to process bid batch list", err)
-						}
-					}
-					return
-				}
-
-				bidBatch = append(bidBatch, bidEntity)
-
-				if len(bidBatch) >= bu.maxBatchSize {
-					if err := bu.BidRepository.CreateBid(ctx, bidBatch); err != nil {
-						logger.Error("error trying to process bid batch list", err)
-					}
-
-					bidBatch = nil
-					bu.timer.Reset(bu.batchInsertInterval)
-				}
-			case <-bu.timer.C:
-				if err := bu.BidRepository.CreateBid(ctx, bidBatch); err != nil {
-					logger.Error("error trying to process bid batch list", err)
-				}
-				bidBatch = nil
-				bu.timer.Reset(bu.batchInsertInterval)
-			}
-		}
-	}()
+	BuyNow(
+		ctx context.Context, auctionId, userId string) (*BidAcceptanceOutputDTO, *internal_error.InternalError)
 }
 
 func (bu *BidUseCase) CreateBid(
 	ctx context.Context,
-	bidInputDTO BidInputDTO) *internal_error.InternalError {
+	bidInputDTO BidInputDTO) (*BidAcceptanceOutputDTO, *internal_error.InternalError) {
 
-	bidEntity, err := bid_entity.CreateBid(bidInputDTO.UserId, bidInputDTO.AuctionId, bidInputDTO.Amount)
+	amount, err := money_entity.Parse(bidInputDTO.Amount.String(), bidInputDTO.Currency)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	bu.bidChannel <- *bidEntity
+	maxAmount, err := money_entity.Parse(bidInputDTO.MaxAmount.String(), bidInputDTO.Currency)
+	if err != nil {
+		return nil, err
+	}
 
-	return nil
-}
+	bidEntity, err := bid_entity.CreateBid(bidInputDTO.UserId, bidInputDTO.AuctionId, amount, maxAmount)
+	if err != nil {
+		return nil, err
+	}
 
-func getMaxBatchSizeInterval() time.Duration {
-	batchInsertInterval := os.Getenv("BATCH_INSERT_INTERVAL")
-	duration, err := time.ParseDuration(batchInsertInterval)
+	bidResult, err := bu.checkBidder(ctx, bidEntity.UserId)
 	if err != nil {
-		return 3 * time.Minute
+		return nil, err
+	}
+
+	if bidResult == nil {
+		bidResult, err = bu.BidRepository.AcceptBid(ctx, bidEntity)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if !bidResult.Accepted {
+		return &BidAcceptanceOutputDTO{
+			Id:        bidEntity.Id,
+			AuctionId: bidEntity.AuctionId,
+			Status:    BidRejected,
+			Reason:    string(bidResult.Reason),
+			Message:   bidResult.Message,
+		}, nil
 	}
 
-	return duration
+	bu.publishAcceptance(ctx, bidResult)
+
+	if bidResult.MaxBidUpdated {
+		return &BidAcceptanceOutputDTO{
+			AuctionId: bidEntity.AuctionId,
+			Status:    BidMaxBidUpdated,
+			MaxAmount: json.Number(bidEntity.MaxAmount.String()),
+			Currency:  bidEntity.Currency(),
+			Leading:   bidResult.Leading,
+		}, nil
+	}
+
+	bidAcceptance := &BidAcceptanceOutputDTO{
+		Id:        bidEntity.Id,
+		AuctionId: bidEntity.AuctionId,
+		Status:    BidAccepted,
+		Amount:    json.Number(bidEntity.Amount.String()),
+		Currency:  bidEntity.Currency(),
+		Leading:   bidResult.Leading,
+	}
+
+	if !bidResult.ExtendedEndTime.IsZero() {
+		bidAcceptance.ExtendedEndTime = &bidResult.ExtendedEndTime
+	}
+
+	return bidAcceptance, nil
 }
 
-func getMaxBatchSize() int {
-	value, err := strconv.Atoi(os.Getenv("MAX_BATCH_SIZE"))
+// checkBidder retorna a rejeição quando o usuário não pode dar lances
+func (bu *BidUseCase) checkBidder(
+	ctx context.Context, userId string) (*bid_entity.BidResult, *internal_error.InternalError) {
+	user, err := bu.UserRepository.FindUserById(ctx, userId)
 	if err != nil {
-		return 5
+		if err.Err == "not_found" {
+			return bid_entity.NewRejectedBidResult(bid_entity.UserNotFound, "User is not registered"), nil
+		}
+		return nil, err
 	}
 
-	return value
+	switch user.Status {
+	case user_entity.Suspended:
+		return bid_entity.NewRejectedBidResult(bid_entity.UserSuspended, "User is suspended"), nil
+	case user_entity.Banned:
+		return bid_entity.NewRejectedBidResult(bid_entity.UserBanned, "User is banned"), nil
+	}
+
+	return nil, nil
+}
+
+func (bu *BidUseCase) publishAcceptance(ctx context.Context, bidResult *bid_entity.BidResult) {
+	for _, placedBid := range bidResult.Bids {
+		bu.EventBus.Publish(ctx, event.BidAccepted{Bid: placedBid})
+	}
+
+	if len(bidResult.Bids) > 0 {
+		highestBid := bidResult.Bids[len(bidResult.Bids)-1]
+		bu.EventBus.Publish(ctx, event.HighestBidChanged{Bid: highestBid})
+
+		if !bidResult.ExtendedEndTime.IsZero() {
+			bu.EventBus.Publish(ctx, event.AuctionExtended{
+				AuctionId: highestBid.AuctionId,
+				EndTime:   bidResult.ExtendedEndTime,
+			})
+		}
+	}
 }
@@ -0,0 +1,271 @@
+package bid_usecase
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/entity/bid_entity"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/entity/money_entity"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/entity/user_entity"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/event"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/internal_error"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+type bidRepositoryMock struct {
+	rejection  *bid_entity.BidResult
+	maxBidOnly bool
+}
+
+func (m *bidRepositoryMock) AcceptBid(
+	ctx context.Context, bidEntity *bid_entity.Bid) (*bid_entity.BidResult, *internal_error.InternalError) {
+	if m.rejection != nil {
+		return m.rejection, nil
+	}
+	if m.maxBidOnly {
+		return bid_entity.NewMaxBidUpdatedResult(nil, true), nil
+	}
+	return bid_entity.NewAcceptedBidResult([]bid_entity.Bid{*bidEntity}, true), nil
+}
+
+func (m *bidRepositoryMock) FindBidByAuctionId(
+	ctx context.Context, auctionId string) ([]bid_entity.Bid, *internal_error.InternalError) {
+	return nil, nil
+}
+
+func (m *bidRepositoryMock) FindWinningBidByAuctionId(
+	ctx context.Context, auctionId string) (*bid_entity.Bid, *internal_error.InternalError) {
+	return nil, internal_error.NewNotFoundError("no bids")
+}
+
+func (m *bidRepositoryMock) FindWinningBidsByAuctionIds(
+	ctx context.Context, auctionIds []string) (map[string]bid_entity.Bid, *internal_error.InternalError) {
+	return map[string]bid_entity.Bid{}, nil
+}
+
+func (m *bidRepositoryMock) CancelAuction(
+	ctx context.Context, auctionId string, withoutBids bool) *internal_error.InternalError {
+	return nil
+}
+
+func (m *bidRepositoryMock) BuyNow(
+	ctx context.Context, userId, auctionId string) (*bid_entity.BidResult, *internal_error.InternalError) {
+	if m.rejection != nil {
+		return m.rejection, nil
+	}
+
+	bidEntity, err := bid_entity.CreateBuyNowBid(userId, auctionId, money_entity.New(50000, "BRL"))
+	if err != nil {
+		return nil, err
+	}
+	return bid_entity.NewAcceptedBidResult([]bid_entity.Bid{*bidEntity}, true), nil
+}
+
+// userRepositoryMock trata qualquer id como um usuário ativo, exceto os listados
+type userRepositoryMock struct {
+	statuses map[string]user_entity.UserStatus
+	missing  string
+}
+
+func (m userRepositoryMock) CreateUser(ctx context.Context, userEntity *user_entity.User) *internal_error.InternalError {
+	return nil
+}
+
+func (m userRepositoryMock) FindUserById(
+	ctx context.Context, userId string) (*user_entity.User, *internal_error.InternalError) {
+	if userId == m.missing {
+		return nil, internal_error.NewNotFoundError("user not found")
+	}
+	return &user_entity.User{Id: userId, Status: m.statuses[userId]}, nil
+}
+
+func (m userRepositoryMock) UpdateUser(ctx context.Context, userEntity *user_entity.User) *internal_error.InternalError {
+	return nil
+}
+
+func (m userRepositoryMock) DeleteUser(ctx context.Context, userId string) *internal_error.InternalError {
+	return nil
+}
+
+func TestCreateBidRejectsIneligibleUsers(t *testing.T) {
+	unknownUser := uuid.New().String()
+	suspendedUser := uuid.New().String()
+	bannedUser := uuid.New().String()
+	users := userRepositoryMock{
+		statuses: map[string]user_entity.UserStatus{
+			suspendedUser: user_entity.Suspended,
+			bannedUser:    user_entity.Banned,
+		},
+		missing: unknownUser,
+	}
+
+	tests := []struct {
+		userId string
+		reason bid_entity.RejectionReason
+	}{
+		{unknownUser, bid_entity.UserNotFound},
+		{suspendedUser, bid_entity.UserSuspended},
+		{bannedUser, bid_entity.UserBanned},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.reason), func(t *testing.T) {
+			useCase := NewBidUseCase(&bidRepositoryMock{}, users, nil)
+
+			output, err := useCase.CreateBid(context.Background(), BidInputDTO{
+				UserId:    tt.userId,
+				AuctionId: uuid.New().String(),
+				Amount:    "100",
+				Currency:  "BRL",
+			})
+
+			assert.Nil(t, err)
+			assert.Equal(t, BidRejected, output.Status)
+			assert.Equal(t, string(tt.reason), output.Reason)
+		})
+	}
+}
+
+func TestCreateBid(t *testing.T) {
+	t.Run("accepted bid returns its id", func(t *testing.T) {
+		repository := &bidRepositoryMock{}
+		useCase := NewBidUseCase(repository, userRepositoryMock{}, nil)
+
+		auctionId := uuid.New().String()
+		output, err := useCase.CreateBid(context.Background(), BidInputDTO{
+			UserId:    uuid.New().String(),
+			AuctionId: auctionId,
+			Amount:    "100",
+			Currency:  "BRL",
+		})
+
+		assert.Nil(t, err)
+		assert.Equal(t, BidAccepted, output.Status)
+		assert.Equal(t, auctionId, output.AuctionId)
+		assert.Equal(t, json.Number("100.00"), output.Amount)
+		assert.Equal(t, "BRL", output.Currency)
+		assert.True(t, output.Leading)
+		assert.NotEmpty(t, output.Id)
+	})
+
+	t.Run("accepted bid publishes domain events", func(t *testing.T) {
+		bus := event.NewBus()
+		var published []string
+		bus.Subscribe(func(ctx context.Context, e event.Event) {
+			published = append(published, e.Name())
+		}, event.BidAcceptedEvent, event.HighestBidChangedEvent, event.AuctionExtendedEvent)
+
+		useCase := NewBidUseCase(&bidRepositoryMock{}, userRepositoryMock{}, bus)
+		_, err := useCase.CreateBid(context.Background(), BidInputDTO{
+			UserId:    uuid.New().String(),
+			AuctionId: uuid.New().String(),
+			Amount:    "100",
+			Currency:  "BRL",
+		})
+
+		assert.Nil(t, err)
+		assert.Equal(t, []string{event.BidAcceptedEvent, event.HighestBidChangedEvent}, published)
+	})
+
+	t.Run("max bid update by the leader creates no bid", func(t *testing.T) {
+		useCase := NewBidUseCase(&bidRepositoryMock{maxBidOnly: true}, userRepositoryMock{}, nil)
+
+		output, err := useCase.CreateBid(context.Background(), BidInputDTO{
+			UserId:    uuid.New().String(),
+			AuctionId: uuid.New().String(),
+			MaxAmount: "300",
+			Currency:  "BRL",
+		})
+
+		assert.Nil(t, err)
+		assert.Equal(t, BidMaxBidUpdated, output.Status)
+		assert.Empty(t, output.Id)
+		assert.Empty(t, output.Amount)
+		assert.Equal(t, json.Number("300.00"), output.MaxAmount)
+		assert.True(t, output.Leading)
+	})
+
+	t.Run("rejected bid reports the reason", func(t *testing.T) {
+		repository := &bidRepositoryMock{rejection: bid_entity.NewRejectedBidResult(
+			bid_entity.AuctionClosed, "Auction is already closed")}
+		useCase := NewBidUseCase(repository, userRepositoryMock{}, nil)
+
+		output, err := useCase.CreateBid(context.Background(), BidInputDTO{
+			UserId:    uuid.New().String(),
+			AuctionId: uuid.New().String(),
+			Amount:    "100",
+			Currency:  "BRL",
+		})
+
+		assert.Nil(t, err)
+		assert.Equal(t, BidRejected, output.Status)
+		assert.Equal(t, string(bid_entity.AuctionClosed), output.Reason)
+		assert.NotEmpty(t, output.Id)
+	})
+
+	t.Run("invalid bid is a bad request", func(t *testing.T) {
+		repository := &bidRepositoryMock{}
+		useCase := NewBidUseCase(repository, userRepositoryMock{}, nil)
+
+		output, err := useCase.CreateBid(context.Background(), BidInputDTO{
+			UserId:    "invalid",
+			AuctionId: uuid.New().String(),
+			Amount:    "100",
+			Currency:  "BRL",
+		})
+
+		assert.Nil(t, output)
+		assert.Equal(t, "bad_request", err.Err)
+	})
+
+	t.Run("amount with more decimals than the currency is a bad request", func(t *testing.T) {
+		useCase := NewBidUseCase(&bidRepositoryMock{}, userRepositoryMock{}, nil)
+
+		output, err := useCase.CreateBid(context.Background(), BidInputDTO{
+			UserId:    uuid.New().String(),
+			AuctionId: uuid.New().String(),
+			Amount:    "10.101",
+			Currency:  "BRL",
+		})
+
+		assert.Nil(t, output)
+		assert.Equal(t, "bad_request", err.Err)
+	})
+}
+
+func TestBuyNow(t *testing.T) {
+	t.Run("purchase closes the auction", func(t *testing.T) {
+		bus := event.NewBus()
+		var published []string
+		bus.Subscribe(func(ctx context.Context, e event.Event) {
+			published = append(published, e.Name())
+		}, event.BidAcceptedEvent, event.HighestBidChangedEvent, event.AuctionClosedEvent)
+
+		repository := &bidRepositoryMock{}
+		useCase := NewBidUseCase(repository, userRepositoryMock{}, bus)
+
+		auctionId := uuid.New().String()
+		output, err := useCase.BuyNow(context.Background(), auctionId, uuid.New().String())
+
+		assert.Nil(t, err)
+		assert.Equal(t, BidAccepted, output.Status)
+		assert.Equal(t, json.Number("500.00"), output.Amount)
+		assert.True(t, output.Leading)
+		assert.Equal(t,
+			[]string{event.BidAcceptedEvent, event.HighestBidChangedEvent, event.AuctionClosedEvent}, published)
+	})
+
+	t.Run("unavailable purchase reports the reason", func(t *testing.T) {
+		repository := &bidRepositoryMock{rejection: bid_entity.NewRejectedBidResult(
+			bid_entity.BuyNowUnavailable, "Buy now is no longer available for this auction")}
+		useCase := NewBidUseCase(repository, userRepositoryMock{}, nil)
+
+		output, err := useCase.BuyNow(context.Background(), uuid.New().String(), uuid.New().String())
+
+		assert.Nil(t, err)
+		assert.Equal(t, BidRejected, output.Status)
+		assert.Equal(t, string(bid_entity.BuyNowUnavailable), output.Reason)
+	})
+}
@@ -2,7 +2,9 @@ package bid_usecase
 
 import (
 	"context"
+	"encoding/json"
 
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/entity/bid_entity"
 	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/internal_error"
 )
 
@@ -15,13 +17,7 @@ func (bu *BidUseCase) FindBidByAuctionId(
 
 	var bidOutputList []BidOutputDTO
 	for _, bid := range bidList {
-		bidOutputList = append(bidOutputList, BidOutputDTO{
-			Id:        bid.Id,
-			UserId:    bid.UserId,
-			AuctionId: bid.AuctionId,
-			Amount:    bid.Amount,
-			Timestamp: bid.Timestamp,
-		})
+		bidOutputList = append(bidOutputList, ToBidOutputDTO(bid))
 	}
 
 	return bidOutputList, nil
@@ -34,13 +30,23 @@ func (bu *BidUseCase) FindWinningBidByAuctionId(
 		return nil, err
 	}
 
-	bidOutput := &BidOutputDTO{
-		Id:        bidEntity.Id,
-		UserId:    bidEntity.UserId,
-		AuctionId: bidEntity.AuctionId,
-		Amount:    bidEntity.Amount,
-		Timestamp: bidEntity.Timestamp,
-	}
+	bidOutput := ToBidOutputDTO(*bidEntity)
+	return &bidOutput, nil
+}
 
-	return bidOutput, nil
+// ToBidOutputDTO é a única conversão de lance para a resposta da API, usada
+// também pelas rotas de leilão que devolvem lances.
+func ToBidOutputDTO(bid bid_entity.Bid) BidOutputDTO {
+	return BidOutputDTO{
+		Id:            bid.Id,
+		UserId:        bid.UserId,
+		AuctionId:     bid.AuctionId,
+		Amount:        json.Number(bid.Amount.String()),
+		Currency:      bid.Amount.Currency,
+		Timestamp:     bid.Timestamp,
+		Automatic:     bid.Automatic,
+		BuyNow:        bid.BuyNow,
+		DutchPurchase: bid.DutchPurchase,
+		Void:          bid.Void,
+	}
 }
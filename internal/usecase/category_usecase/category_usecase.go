@@ -0,0 +1,183 @@
+package category_usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/entity/category_entity"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/internal_error"
+)
+
+type CategoryInputDTO struct {
+	Name     string `json:"name" binding:"required,min=2,max=60"`
+	Slug     string `json:"slug" binding:"omitempty,max=60"`
+	ParentId string `json:"parent_id" binding:"omitempty,uuid"`
+}
+
+type CategoryOutputDTO struct {
+	Id        string              `json:"id"`
+	Name      string              `json:"name"`
+	Slug      string              `json:"slug"`
+	ParentId  string              `json:"parent_id,omitempty"`
+	CreatedAt time.Time           `json:"created_at" time_format:"2006-01-02 15:04:05"`
+	Children  []CategoryOutputDTO `json:"children,omitempty"`
+}
+
+// CategoryAuctions é o que a categoria precisa dos leilões que a usam: saber
+// se ainda há algum e manter neles o nome copiado da categoria.
+type CategoryAuctions interface {
+	CountAuctionsByCategory(
+		ctx context.Context, categoryId string) (int64, *internal_error.InternalError)
+
+	RenameCategory(ctx context.Context, categoryId, name string) *internal_error.InternalError
+}
+
+type CategoryUseCase struct {
+	categoryRepository category_entity.CategoryRepositoryInterface
+	categoryAuctions   CategoryAuctions
+}
+
+func NewCategoryUseCase(
+	categoryRepository category_entity.CategoryRepositoryInterface,
+	categoryAuctions CategoryAuctions) CategoryUseCaseInterface {
+	return &CategoryUseCase{
+		categoryRepository: categoryRepository,
+		categoryAuctions:   categoryAuctions,
+	}
+}
+
+type CategoryUseCaseInterface interface {
+	CreateCategory(
+		ctx context.Context,
+		categoryInput CategoryInputDTO) (*CategoryOutputDTO, *internal_error.InternalError)
+
+	FindCategories(ctx context.Context) ([]CategoryOutputDTO, *internal_error.InternalError)
+
+	FindCategoryById(
+		ctx context.Context, id string) (*CategoryOutputDTO, *internal_error.InternalError)
+
+	UpdateCategory(
+		ctx context.Context,
+		id string,
+		categoryInput CategoryInputDTO) (*CategoryOutputDTO, *internal_error.InternalError)
+
+	DeleteCategory(ctx context.Context, id string) *internal_error.InternalError
+}
+
+func (cu *CategoryUseCase) CreateCategory(
+	ctx context.Context,
+	categoryInput CategoryInputDTO) (*CategoryOutputDTO, *internal_error.InternalError) {
+	category, err := category_entity.CreateCategory(categoryInput.Name, categoryInput.Slug, categoryInput.ParentId)
+	if err != nil {
+		return nil, err
+	}
+
+	if category.ParentId != "" {
+		if _, err := cu.findParent(ctx, category.ParentId); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := cu.categoryRepository.CreateCategory(ctx, category); err != nil {
+		return nil, err
+	}
+
+	categoryOutput := toCategoryOutputDTO(*category)
+	return &categoryOutput, nil
+}
+
+// UpdateCategory permite renomear e mover a categoria, desde que ela não
+// passe a ficar abaixo de uma das suas próprias subcategorias. O novo nome é
+// copiado para os leilões da categoria; se a cópia falhar, repetir a
+// requisição a completa.
+func (cu *CategoryUseCase) UpdateCategory(
+	ctx context.Context,
+	id string,
+	categoryInput CategoryInputDTO) (*CategoryOutputDTO, *internal_error.InternalError) {
+	categories, err := cu.categoryRepository.FindCategories(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	category := findCategoryById(categories, id)
+	if category == nil {
+		return nil, internal_error.NewNotFoundError("Category not found with this id = " + id)
+	}
+
+	if err := category.Update(categoryInput.Name, categoryInput.Slug, categoryInput.ParentId); err != nil {
+		return nil, err
+	}
+
+	if category.ParentId != "" {
+		if findCategoryById(categories, category.ParentId) == nil {
+			return nil, internal_error.NewBadRequestError("Parent category does not exist")
+		}
+
+		for _, descendantId := range category_entity.Descendants(categories, category.Id) {
+			if descendantId == category.ParentId {
+				return nil, internal_error.NewBadRequestError("Category cannot be moved below one of its subcategories")
+			}
+		}
+	}
+
+	if err := cu.categoryRepository.UpdateCategory(ctx, category); err != nil {
+		return nil, err
+	}
+
+	if err := cu.categoryAuctions.RenameCategory(ctx, category.Id, category.Name); err != nil {
+		return nil, err
+	}
+
+	categoryOutput := toCategoryOutputDTO(*category)
+	return &categoryOutput, nil
+}
+
+// DeleteCategory só remove folhas da árvore que nenhum leilão referencia.
+func (cu *CategoryUseCase) DeleteCategory(ctx context.Context, id string) *internal_error.InternalError {
+	categories, err := cu.categoryRepository.FindCategories(ctx)
+	if err != nil {
+		return err
+	}
+
+	if findCategoryById(categories, id) == nil {
+		return internal_error.NewNotFoundError("Category not found with this id = " + id)
+	}
+
+	if len(category_entity.Descendants(categories, id)) > 1 {
+		return internal_error.NewBadRequestError("Category has subcategories")
+	}
+
+	count, err := cu.categoryAuctions.CountAuctionsByCategory(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if count > 0 {
+		return internal_error.NewBadRequestError("Category is used by auctions")
+	}
+
+	return cu.categoryRepository.DeleteCategory(ctx, id)
+}
+
+func (cu *CategoryUseCase) findParent(
+	ctx context.Context, parentId string) (*category_entity.Category, *internal_error.InternalError) {
+	parent, err := cu.categoryRepository.FindCategoryById(ctx, parentId)
+	if err != nil {
+		if err.Err == "not_found" {
+			return nil, internal_error.NewBadRequestError("Parent category does not exist")
+		}
+		return nil, err
+	}
+
+	return parent, nil
+}
+
+func findCategoryById(categories []category_entity.Category, id string) *category_entity.Category {
+	for i := range categories {
+		if categories[i].Id == id {
+			return &categories[i]
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,148 @@
+package category_usecase
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/entity/category_entity"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/internal_error"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	electronicsId = "6f1c2a4e-0b7d-4c55-9a1e-3d2f8b9c0a11"
+	phonesId      = "0c3e5b7a-2d4f-4e61-8b2a-9f1d3c5e7a22"
+	furnitureId   = "9a8b7c6d-5e4f-4a3b-8c2d-1e0f9a8b7c33"
+)
+
+type categoryRepositoryMock struct {
+	categories []category_entity.Category
+	deleted    string
+}
+
+func (m *categoryRepositoryMock) CreateCategory(
+	ctx context.Context, category *category_entity.Category) *internal_error.InternalError {
+	m.categories = append(m.categories, *category)
+	return nil
+}
+
+func (m *categoryRepositoryMock) FindCategories(
+	ctx context.Context) ([]category_entity.Category, *internal_error.InternalError) {
+	categories := make([]category_entity.Category, len(m.categories))
+	copy(categories, m.categories)
+	return categories, nil
+}
+
+func (m *categoryRepositoryMock) FindCategoryById(
+	ctx context.Context, id string) (*category_entity.Category, *internal_error.InternalError) {
+	for i := range m.categories {
+		if m.categories[i].Id == id {
+			category := m.categories[i]
+			return &category, nil
+		}
+	}
+	return nil, internal_error.NewNotFoundError("category not found")
+}
+
+func (m *categoryRepositoryMock) UpdateCategory(
+	ctx context.Context, category *category_entity.Category) *internal_error.InternalError {
+	for i := range m.categories {
+		if m.categories[i].Id == category.Id {
+			m.categories[i] = *category
+		}
+	}
+	return nil
+}
+
+func (m *categoryRepositoryMock) DeleteCategory(ctx context.Context, id string) *internal_error.InternalError {
+	m.deleted = id
+	return nil
+}
+
+type categoryAuctionsMock struct {
+	counts  map[string]int64
+	renamed map[string]string
+}
+
+func (m *categoryAuctionsMock) CountAuctionsByCategory(
+	ctx context.Context, categoryId string) (int64, *internal_error.InternalError) {
+	return m.counts[categoryId], nil
+}
+
+func (m *categoryAuctionsMock) RenameCategory(
+	ctx context.Context, categoryId, name string) *internal_error.InternalError {
+	if m.renamed == nil {
+		m.renamed = make(map[string]string)
+	}
+	m.renamed[categoryId] = name
+	return nil
+}
+
+func newCategoryRepositoryMock() *categoryRepositoryMock {
+	return &categoryRepositoryMock{categories: []category_entity.Category{
+		{Id: electronicsId, Name: "Eletrônicos", Slug: "eletronicos"},
+		{Id: phonesId, Name: "Celulares", Slug: "celulares", ParentId: electronicsId},
+		{Id: furnitureId, Name: "Móveis", Slug: "moveis"},
+	}}
+}
+
+func TestCategoryTree(t *testing.T) {
+	categoryRepository := newCategoryRepositoryMock()
+	useCase := NewCategoryUseCase(categoryRepository, &categoryAuctionsMock{})
+	ctx := context.Background()
+
+	created, err := useCase.CreateCategory(ctx, CategoryInputDTO{Name: "Smartphones Android", ParentId: phonesId})
+	assert.Nil(t, err)
+	assert.Equal(t, "smartphones-android", created.Slug)
+
+	_, err = useCase.CreateCategory(ctx, CategoryInputDTO{Name: "Livros", ParentId: created.Id[:8] + phonesId[8:]})
+	assert.Equal(t, "bad_request", err.Err)
+
+	tree, err := useCase.FindCategories(ctx)
+	assert.Nil(t, err)
+	assert.Len(t, tree, 2)
+	assert.Equal(t, electronicsId, tree[0].Id)
+	assert.Equal(t, phonesId, tree[0].Children[0].Id)
+	assert.Equal(t, created.Id, tree[0].Children[0].Children[0].Id)
+
+	electronics, err := useCase.FindCategoryById(ctx, electronicsId)
+	assert.Nil(t, err)
+	assert.Len(t, electronics.Children, 1)
+}
+
+func TestUpdateCategoryRejectsCycles(t *testing.T) {
+	useCase := NewCategoryUseCase(newCategoryRepositoryMock(), &categoryAuctionsMock{})
+	ctx := context.Background()
+
+	_, err := useCase.UpdateCategory(ctx, electronicsId, CategoryInputDTO{Name: "Eletrônicos", ParentId: phonesId})
+	assert.Equal(t, "bad_request", err.Err)
+
+	moved, err := useCase.UpdateCategory(ctx, phonesId, CategoryInputDTO{Name: "Celulares", ParentId: furnitureId})
+	assert.Nil(t, err)
+	assert.Equal(t, furnitureId, moved.ParentId)
+}
+
+func TestUpdateCategoryRenamesAuctions(t *testing.T) {
+	categoryAuctions := &categoryAuctionsMock{}
+	useCase := NewCategoryUseCase(newCategoryRepositoryMock(), categoryAuctions)
+
+	_, err := useCase.UpdateCategory(context.Background(), phonesId,
+		CategoryInputDTO{Name: "Smartphones", ParentId: electronicsId})
+	assert.Nil(t, err)
+	assert.Equal(t, "Smartphones", categoryAuctions.renamed[phonesId])
+}
+
+func TestDeleteCategoryOnlyRemovesUnusedLeaves(t *testing.T) {
+	categoryRepository := newCategoryRepositoryMock()
+	useCase := NewCategoryUseCase(categoryRepository, &categoryAuctionsMock{counts: map[string]int64{phonesId: 3}})
+	ctx := context.Background()
+
+	err := useCase.DeleteCategory(ctx, electronicsId)
+	assert.Equal(t, "bad_request", err.Err)
+
+	err = useCase.DeleteCategory(ctx, phonesId)
+	assert.Equal(t, "bad_request", err.Err)
+
+	assert.Nil(t, useCase.DeleteCategory(ctx, furnitureId))
+	assert.Equal(t, furnitureId, categoryRepository.deleted)
+}
@@ -0,0 +1,73 @@
+package category_usecase
+
+import (
+	"context"
+
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/entity/category_entity"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/internal_error"
+)
+
+// FindCategories devolve a árvore completa, a partir das categorias raiz.
+func (cu *CategoryUseCase) FindCategories(ctx context.Context) ([]CategoryOutputDTO, *internal_error.InternalError) {
+	categories, err := cu.categoryRepository.FindCategories(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return buildTree(categories, ""), nil
+}
+
+// FindCategoryById devolve a categoria com todas as suas subcategorias.
+func (cu *CategoryUseCase) FindCategoryById(
+	ctx context.Context, id string) (*CategoryOutputDTO, *internal_error.InternalError) {
+	categories, err := cu.categoryRepository.FindCategories(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	category := findCategoryById(categories, id)
+	if category == nil {
+		return nil, internal_error.NewNotFoundError("Category not found with this id = " + id)
+	}
+
+	categoryOutput := toCategoryOutputDTO(*category)
+	categoryOutput.Children = buildTree(categories, category.Id)
+
+	return &categoryOutput, nil
+}
+
+func buildTree(categories []category_entity.Category, parentId string) []CategoryOutputDTO {
+	children := make(map[string][]category_entity.Category)
+	for _, category := range categories {
+		children[category.ParentId] = append(children[category.ParentId], category)
+	}
+
+	var build func(parentId string, visited map[string]bool) []CategoryOutputDTO
+	build = func(parentId string, visited map[string]bool) []CategoryOutputDTO {
+		nodes := make([]CategoryOutputDTO, 0, len(children[parentId]))
+		for _, category := range children[parentId] {
+			if visited[category.Id] {
+				continue
+			}
+			visited[category.Id] = true
+
+			node := toCategoryOutputDTO(category)
+			node.Children = build(category.Id, visited)
+			nodes = append(nodes, node)
+		}
+
+		return nodes
+	}
+
+	return build(parentId, map[string]bool{parentId: true})
+}
+
+func toCategoryOutputDTO(category category_entity.Category) CategoryOutputDTO {
+	return CategoryOutputDTO{
+		Id:        category.Id,
+		Name:      category.Name,
+		Slug:      category.Slug,
+		ParentId:  category.ParentId,
+		CreatedAt: category.CreatedAt,
+	}
+}
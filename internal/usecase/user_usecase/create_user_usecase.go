@@ -0,0 +1,66 @@
+package user_usecase
+
+import (
+	"context"
+
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/entity/user_entity"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/internal_error"
+)
+
+type UserInputDTO struct {
+	Name        string `json:"name" binding:"required,min=1,max=100"`
+	Email       string `json:"email" binding:"required,email"`
+	DisplayName string `json:"display_name" binding:"omitempty,max=50"`
+}
+
+type UserUpdateInputDTO struct {
+	Name        string     `json:"name" binding:"required,min=1,max=100"`
+	Email       string     `json:"email" binding:"required,email"`
+	DisplayName string     `json:"display_name" binding:"omitempty,max=50"`
+	Status      UserStatus `json:"status" binding:"oneof=0 1 2"`
+}
+
+func (u *UserUseCase) CreateUser(
+	ctx context.Context,
+	userInput UserInputDTO) (*UserOutputDTO, *internal_error.InternalError) {
+	userEntity, err := user_entity.CreateUser(userInput.Name, userInput.Email, userInput.DisplayName)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := u.UserRepository.CreateUser(ctx, userEntity); err != nil {
+		return nil, err
+	}
+
+	return toUserOutputDTO(userEntity), nil
+}
+
+func (u *UserUseCase) UpdateUser(
+	ctx context.Context,
+	id string,
+	userInput UserUpdateInputDTO) (*UserOutputDTO, *internal_error.InternalError) {
+	userEntity, err := u.UserRepository.FindUserById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := userEntity.Update(
+		userInput.Name,
+		userInput.Email,
+		userInput.DisplayName,
+		user_entity.UserStatus(userInput.Status)); err != nil {
+		return nil, err
+	}
+
+	if err := u.UserRepository.UpdateUser(ctx, userEntity); err != nil {
+		return nil, err
+	}
+
+	return toUserOutputDTO(userEntity), nil
+}
+
+func (u *UserUseCase) DeleteUser(
+	ctx context.Context,
+	id string) *internal_error.InternalError {
+	return u.UserRepository.DeleteUser(ctx, id)
+}
@@ -2,6 +2,7 @@ package user_usecase
 
 import (
 	"context"
+	"time"
 
 	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/entity/user_entity"
 	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/internal_error"
@@ -18,14 +19,33 @@ type UserUseCase struct {
 }
 
 type UserOutputDTO struct {
-	Id   string `json:"id"`
-	Name string `json:"name"`
+	Id          string     `json:"id"`
+	Name        string     `json:"name"`
+	Email       string     `json:"email,omitempty"`
+	DisplayName string     `json:"display_name,omitempty"`
+	Status      UserStatus `json:"status"`
+	CreatedAt   *time.Time `json:"created_at,omitempty" time_format:"2006-01-02 15:04:05"`
 }
 
+type UserStatus int64
+
 type UserUseCaseInterface interface {
+	CreateUser(
+		ctx context.Context,
+		userInput UserInputDTO) (*UserOutputDTO, *internal_error.InternalError)
+
 	FindUserById(
 		ctx context.Context,
 		id string) (*UserOutputDTO, *internal_error.InternalError)
+
+	UpdateUser(
+		ctx context.Context,
+		id string,
+		userInput UserUpdateInputDTO) (*UserOutputDTO, *internal_error.InternalError)
+
+	DeleteUser(
+		ctx context.Context,
+		id string) *internal_error.InternalError
 }
 
 func (u *UserUseCase) FindUserById(
@@ -35,8 +55,20 @@ func (u *UserUseCase) FindUserById(
 		return nil, err
 	}
 
-	return &UserOutputDTO{
-		Id:   userEntity.Id,
-		Name: userEntity.Name,
-	}, nil
+	return toUserOutputDTO(userEntity), nil
+}
+
+func toUserOutputDTO(userEntity *user_entity.User) *UserOutputDTO {
+	userOutput := &UserOutputDTO{
+		Id:          userEntity.Id,
+		Name:        userEntity.Name,
+		Email:       userEntity.Email,
+		DisplayName: userEntity.DisplayName,
+		Status:      UserStatus(userEntity.Status),
+	}
+	if !userEntity.CreatedAt.IsZero() {
+		userOutput.CreatedAt = &userEntity.CreatedAt
+	}
+
+	return userOutput
 }
@@ -0,0 +1,104 @@
+package webhook_usecase
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/entity/outbox_entity"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/entity/webhook_entity"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/internal_error"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/usecase/auction_usecase"
+)
+
+type WinnerFinder interface {
+	FindWinningBidByAuctionId(
+		ctx context.Context,
+		auctionId string) (*auction_usecase.WinningInfoOutputDTO, *internal_error.InternalError)
+}
+
+type WebhookPayloadDTO struct {
+	Event      string                               `json:"event"`
+	AuctionId  string                               `json:"auction_id"`
+	OccurredAt time.Time                            `json:"occurred_at"`
+	Data       auction_usecase.WinningInfoOutputDTO `json:"data"`
+}
+
+// OutboxSink entrega ao caso de uso as mensagens auction.closed do outbox,
+// gravadas na mesma transação que fechou o leilão. Implementa relay.Sink.
+type OutboxSink struct {
+	useCase WebhookUseCaseInterface
+}
+
+func NewOutboxSink(useCase WebhookUseCaseInterface) *OutboxSink {
+	return &OutboxSink{useCase: useCase}
+}
+
+func (s *OutboxSink) Name() string { return "webhooks" }
+
+func (s *OutboxSink) Deliver(ctx context.Context, message outbox_entity.Message) error {
+	if message.EventType != outbox_entity.AuctionClosedEvent {
+		return nil
+	}
+
+	var payload struct {
+		ClosedAt time.Time `json:"closed_at"`
+	}
+	if err := json.Unmarshal(message.Payload, &payload); err != nil {
+		return err
+	}
+
+	if err := s.useCase.NotifyAuctionClosed(ctx, message.AggregateId, payload.ClosedAt); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// NotifyAuctionClosed cria uma entrega para cada webhook inscrito em
+// auction.closed e, quando há vencedor, em auction.winner_determined.
+// O relay pode repetir a notificação; entregas já criadas não são reenviadas.
+func (wu *WebhookUseCase) NotifyAuctionClosed(
+	ctx context.Context, auctionId string, closedAt time.Time) *internal_error.InternalError {
+	winningInfo, err := wu.winnerFinder.FindWinningBidByAuctionId(ctx, auctionId)
+	if err != nil {
+		return err
+	}
+
+	eventTypes := []string{webhook_entity.AuctionClosedEvent}
+	if winningInfo.Bid != nil {
+		eventTypes = append(eventTypes, webhook_entity.WinnerDeterminedEvent)
+	}
+
+	for _, eventType := range eventTypes {
+		payload, errMarshal := json.Marshal(WebhookPayloadDTO{
+			Event:      eventType,
+			AuctionId:  auctionId,
+			OccurredAt: closedAt,
+			Data:       *winningInfo,
+		})
+		if errMarshal != nil {
+			return internal_error.NewInternalServerError("Error trying to encode webhook payload")
+		}
+
+		webhooks, err := wu.webhookRepository.FindWebhooksByEventType(ctx, eventType)
+		if err != nil {
+			return err
+		}
+
+		for _, webhook := range webhooks {
+			delivery := webhook_entity.NewDelivery(webhook.Id, eventType, auctionId, payload)
+			created, err := wu.webhookRepository.CreateDelivery(ctx, delivery)
+			if err != nil {
+				return err
+			}
+			if !created {
+				continue
+			}
+
+			wu.dispatcher.Dispatch(ctx, webhook, *delivery)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,125 @@
+package webhook_usecase
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/entity/outbox_entity"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/entity/webhook_entity"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/internal_error"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/usecase/auction_usecase"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/usecase/bid_usecase"
+	"github.com/stretchr/testify/assert"
+)
+
+type webhookRepositoryMock struct {
+	webhook_entity.WebhookRepositoryInterface
+
+	webhooks   []webhook_entity.Webhook
+	deliveries []webhook_entity.Delivery
+}
+
+func (m *webhookRepositoryMock) FindWebhooksByEventType(
+	ctx context.Context, eventType string) ([]webhook_entity.Webhook, *internal_error.InternalError) {
+	var webhooks []webhook_entity.Webhook
+	for _, webhook := range m.webhooks {
+		if webhook.Subscribes(eventType) {
+			webhooks = append(webhooks, webhook)
+		}
+	}
+	return webhooks, nil
+}
+
+func (m *webhookRepositoryMock) CreateDelivery(
+	ctx context.Context, delivery *webhook_entity.Delivery) (bool, *internal_error.InternalError) {
+	for _, existing := range m.deliveries {
+		if existing.Id == delivery.Id {
+			return false, nil
+		}
+	}
+	m.deliveries = append(m.deliveries, *delivery)
+	return true, nil
+}
+
+type winnerFinderMock struct {
+	winningInfo *auction_usecase.WinningInfoOutputDTO
+}
+
+func (m *winnerFinderMock) FindWinningBidByAuctionId(
+	ctx context.Context, auctionId string) (*auction_usecase.WinningInfoOutputDTO, *internal_error.InternalError) {
+	return m.winningInfo, nil
+}
+
+type dispatcherMock struct {
+	dispatched []string
+}
+
+func (m *dispatcherMock) Dispatch(ctx context.Context, webhook webhook_entity.Webhook, delivery webhook_entity.Delivery) {
+	m.dispatched = append(m.dispatched, webhook.Id+":"+delivery.EventType)
+}
+
+func TestNotifyAuctionClosed(t *testing.T) {
+	webhooks := []webhook_entity.Webhook{
+		{Id: "closed", EventTypes: []string{webhook_entity.AuctionClosedEvent}},
+		{Id: "winner", EventTypes: []string{webhook_entity.WinnerDeterminedEvent}},
+	}
+
+	t.Run("auction with winner notifies both event types", func(t *testing.T) {
+		repository := &webhookRepositoryMock{webhooks: webhooks}
+		dispatcher := &dispatcherMock{}
+		useCase := NewWebhookUseCase(repository, &winnerFinderMock{winningInfo: &auction_usecase.WinningInfoOutputDTO{
+			Auction:    auction_usecase.AuctionOutputDTO{Id: "auction-id"},
+			Bid:        &bid_usecase.BidOutputDTO{Id: "bid-id", Amount: "150.00", Currency: "BRL"},
+			ReserveMet: true,
+		}}, dispatcher)
+
+		err := useCase.NotifyAuctionClosed(context.Background(), "auction-id", time.Now())
+
+		assert.Nil(t, err)
+		assert.Equal(t, []string{
+			"closed:" + webhook_entity.AuctionClosedEvent,
+			"winner:" + webhook_entity.WinnerDeterminedEvent,
+		}, dispatcher.dispatched)
+
+		var payload WebhookPayloadDTO
+		assert.Nil(t, json.Unmarshal(repository.deliveries[1].Payload, &payload))
+		assert.Equal(t, webhook_entity.WinnerDeterminedEvent, payload.Event)
+		assert.Equal(t, "bid-id", payload.Data.Bid.Id)
+		assert.True(t, payload.Data.ReserveMet)
+	})
+
+	t.Run("auction without winner only notifies closing", func(t *testing.T) {
+		repository := &webhookRepositoryMock{webhooks: webhooks}
+		dispatcher := &dispatcherMock{}
+		useCase := NewWebhookUseCase(repository, &winnerFinderMock{winningInfo: &auction_usecase.WinningInfoOutputDTO{
+			Auction: auction_usecase.AuctionOutputDTO{Id: "auction-id"},
+		}}, dispatcher)
+
+		err := useCase.NotifyAuctionClosed(context.Background(), "auction-id", time.Now())
+
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"closed:" + webhook_entity.AuctionClosedEvent}, dispatcher.dispatched)
+		assert.Len(t, repository.deliveries, 1)
+	})
+
+	t.Run("repeated notification does not dispatch again", func(t *testing.T) {
+		repository := &webhookRepositoryMock{webhooks: webhooks}
+		dispatcher := &dispatcherMock{}
+		useCase := NewWebhookUseCase(repository, &winnerFinderMock{winningInfo: &auction_usecase.WinningInfoOutputDTO{
+			Auction: auction_usecase.AuctionOutputDTO{Id: "auction-id"},
+		}}, dispatcher)
+		message, _ := outbox_entity.NewMessage(outbox_entity.AuctionClosedEvent, "auction-id", map[string]interface{}{
+			"auction_id": "auction-id",
+			"closed_at":  time.Now(),
+		})
+
+		sink := NewOutboxSink(useCase)
+		assert.Nil(t, sink.Deliver(context.Background(), *message))
+		assert.Nil(t, sink.Deliver(context.Background(), *message))
+
+		assert.Equal(t, []string{"closed:" + webhook_entity.AuctionClosedEvent}, dispatcher.dispatched)
+		assert.Len(t, repository.deliveries, 1)
+	})
+}
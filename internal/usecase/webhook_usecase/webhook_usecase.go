@@ -0,0 +1,152 @@
+package webhook_usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/entity/webhook_entity"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-leilao/internal/internal_error"
+)
+
+type WebhookInputDTO struct {
+	URL        string   `json:"url" binding:"required,url"`
+	Secret     string   `json:"secret" binding:"required,min=16"`
+	EventTypes []string `json:"event_types" binding:"required,min=1,dive,oneof=auction.closed auction.winner_determined"`
+}
+
+type WebhookOutputDTO struct {
+	Id         string    `json:"id"`
+	URL        string    `json:"url"`
+	EventTypes []string  `json:"event_types"`
+	CreatedAt  time.Time `json:"created_at" time_format:"2006-01-02 15:04:05"`
+}
+
+type DeliveryOutputDTO struct {
+	Id             string    `json:"id"`
+	WebhookId      string    `json:"webhook_id"`
+	EventType      string    `json:"event_type"`
+	AuctionId      string    `json:"auction_id"`
+	Status         string    `json:"status"`
+	Attempts       int       `json:"attempts"`
+	ResponseStatus int       `json:"response_status,omitempty"`
+	LastError      string    `json:"last_error,omitempty"`
+	CreatedAt      time.Time `json:"created_at" time_format:"2006-01-02 15:04:05"`
+}
+
+type WebhookUseCase struct {
+	webhookRepository webhook_entity.WebhookRepositoryInterface
+	winnerFinder      WinnerFinder
+	dispatcher        webhook_entity.WebhookDispatcher
+}
+
+func NewWebhookUseCase(
+	webhookRepository webhook_entity.WebhookRepositoryInterface,
+	winnerFinder WinnerFinder,
+	dispatcher webhook_entity.WebhookDispatcher) WebhookUseCaseInterface {
+	return &WebhookUseCase{
+		webhookRepository: webhookRepository,
+		winnerFinder:      winnerFinder,
+		dispatcher:        dispatcher,
+	}
+}
+
+type WebhookUseCaseInterface interface {
+	CreateWebhook(
+		ctx context.Context,
+		webhookInput WebhookInputDTO) (*WebhookOutputDTO, *internal_error.InternalError)
+
+	FindWebhooks(ctx context.Context) ([]WebhookOutputDTO, *internal_error.InternalError)
+
+	FindWebhookById(
+		ctx context.Context, id string) (*WebhookOutputDTO, *internal_error.InternalError)
+
+	DeleteWebhook(ctx context.Context, id string) *internal_error.InternalError
+
+	FindDeliveriesByWebhookId(
+		ctx context.Context, webhookId string) ([]DeliveryOutputDTO, *internal_error.InternalError)
+
+	NotifyAuctionClosed(ctx context.Context, auctionId string, closedAt time.Time) *internal_error.InternalError
+}
+
+func (wu *WebhookUseCase) CreateWebhook(
+	ctx context.Context,
+	webhookInput WebhookInputDTO) (*WebhookOutputDTO, *internal_error.InternalError) {
+	webhook, err := webhook_entity.CreateWebhook(webhookInput.URL, webhookInput.Secret, webhookInput.EventTypes)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := wu.webhookRepository.CreateWebhook(ctx, webhook); err != nil {
+		return nil, err
+	}
+
+	output := toWebhookOutputDTO(*webhook)
+	return &output, nil
+}
+
+func (wu *WebhookUseCase) FindWebhooks(ctx context.Context) ([]WebhookOutputDTO, *internal_error.InternalError) {
+	webhooks, err := wu.webhookRepository.FindWebhooks(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	webhookOutputs := make([]WebhookOutputDTO, 0, len(webhooks))
+	for _, webhook := range webhooks {
+		webhookOutputs = append(webhookOutputs, toWebhookOutputDTO(webhook))
+	}
+
+	return webhookOutputs, nil
+}
+
+func (wu *WebhookUseCase) FindWebhookById(
+	ctx context.Context, id string) (*WebhookOutputDTO, *internal_error.InternalError) {
+	webhook, err := wu.webhookRepository.FindWebhookById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	output := toWebhookOutputDTO(*webhook)
+	return &output, nil
+}
+
+func (wu *WebhookUseCase) DeleteWebhook(ctx context.Context, id string) *internal_error.InternalError {
+	return wu.webhookRepository.DeleteWebhook(ctx, id)
+}
+
+func (wu *WebhookUseCase) FindDeliveriesByWebhookId(
+	ctx context.Context, webhookId string) ([]DeliveryOutputDTO, *internal_error.InternalError) {
+	if _, err := wu.webhookRepository.FindWebhookById(ctx, webhookId); err != nil {
+		return nil, err
+	}
+
+	deliveries, err := wu.webhookRepository.FindDeliveriesByWebhookId(ctx, webhookId)
+	if err != nil {
+		return nil, err
+	}
+
+	deliveryOutputs := make([]DeliveryOutputDTO, 0, len(deliveries))
+	for _, delivery := range deliveries {
+		deliveryOutputs = append(deliveryOutputs, DeliveryOutputDTO{
+			Id:             delivery.Id,
+			WebhookId:      delivery.WebhookId,
+			EventType:      delivery.EventType,
+			AuctionId:      delivery.AuctionId,
+			Status:         string(delivery.Status),
+			Attempts:       delivery.Attempts,
+			ResponseStatus: delivery.ResponseStatus,
+			LastError:      delivery.LastError,
+			CreatedAt:      delivery.CreatedAt,
+		})
+	}
+
+	return deliveryOutputs, nil
+}
+
+func toWebhookOutputDTO(webhook webhook_entity.Webhook) WebhookOutputDTO {
+	return WebhookOutputDTO{
+		Id:         webhook.Id,
+		URL:        webhook.URL,
+		EventTypes: webhook.EventTypes,
+		CreatedAt:  webhook.CreatedAt,
+	}
+}
@@ -39,11 +39,23 @@ while ! docker exec mongodb-test mongosh --eval "db.adminCommand('ping')" > /dev
     counter=$((counter + 1))
 done
 
+# Transações exigem replica set; o nó único vira primário logo após o initiate
+docker exec mongodb-test mongosh --quiet --eval "rs.initiate({_id: 'rs0', members: [{_id: 0, host: 'localhost:27017'}]})" > /dev/null
+counter=0
+while ! docker exec mongodb-test mongosh --quiet --eval "quit(db.hello().isWritablePrimary ? 0 : 1)" > /dev/null 2>&1; do
+    if [ $counter -eq $timeout ]; then
+        echo -e "${RED}❌ Timeout aguardando o replica set eleger o primário${NC}"
+        cleanup 1
+    fi
+    sleep 1
+    counter=$((counter + 1))
+done
+
 echo -e "${GREEN}✅ MongoDB está pronto para testes${NC}"
 
 # Executar testes de integração
 echo -e "${BLUE}🧪 Executando testes de integração...${NC}"
-MONGODB_URL="mongodb://localhost:27018" MONGODB_DB="test_auctions" go test -v -tags=integration ./...
+MONGODB_URL="mongodb://localhost:27018/?directConnection=true" MONGODB_DB="test_auctions" go test -v -tags=integration ./...
 
 # Verificar resultado dos testes
 if [ $? -eq 0 ]; then